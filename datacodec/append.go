@@ -0,0 +1,275 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"math"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// AppendEncoder is implemented by codecs that can encode values directly into a caller-provided buffer, thus avoiding
+// the allocation of a new slice for each encoded value. All codecs for simple CQL types, lists, sets and maps in this
+// package implement this interface; use the AppendEncode function to append-encode with any codec.
+type AppendEncoder interface {
+
+	// AppendEncode encodes the given source and appends the result to dest, returning the extended slice. The
+	// parameter source accepts the same Go types as Encode. If return parameter wasNil is true, then the source was nil
+	// and must be encoded as a CQL NULL; in this case nothing is appended to dest. If an error is returned, the
+	// returned slice is dest, with its original length.
+	AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error)
+}
+
+// AppendEncode encodes the given source with the given codec, and appends the result to dest. If the codec implements
+// AppendEncoder, this function delegates to it, otherwise the source is encoded with Encode, then appended to dest.
+// See AppendEncoder for details about the return parameters.
+func AppendEncode(codec Codec, dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	if appender, ok := codec.(AppendEncoder); ok {
+		return appender.AppendEncode(dest, source, version)
+	}
+	return appendEncoded(codec, dest, source, version)
+}
+
+// AppendValue encodes the given source with the given codec, and appends the result to dest as a [bytes] value, that
+// is, prefixed with its length as a 4-byte integer, or with -1 if the source was nil. This is the format of bound
+// variables in query options, and of collection elements. If an error is returned, the returned slice is dest, with
+// its original length.
+func AppendValue(codec Codec, dest []byte, source interface{}, version primitive.ProtocolVersion) ([]byte, error) {
+	start := len(dest)
+	// reserve space for the length, written once the value is encoded
+	dest = append(dest, 0, 0, 0, 0)
+	dest, wasNil, err := AppendEncode(codec, dest, source, version)
+	if err != nil {
+		return dest[:start], err
+	}
+	length := len(dest) - start - primitive.LengthOfInt
+	if wasNil {
+		length = -1
+	} else if length > math.MaxInt32 {
+		return dest[:start], errValueTooLong(length)
+	}
+	putInt32(dest[start:], int32(length))
+	return dest, nil
+}
+
+// appendEncoded is the slow path of AppendEncode: it encodes the given source with Encode, then appends the result to
+// dest. Errors are returned unchanged.
+func appendEncoded(codec Encoder, dest []byte, source interface{}, version primitive.ProtocolVersion) ([]byte, bool, error) {
+	encoded, err := codec.Encode(source, version)
+	if err != nil {
+		return dest, false, err
+	}
+	return append(dest, encoded...), encoded == nil, nil
+}
+
+// appendCollectionSize appends the size of a collection, as a 4-byte integer, or as a 2-byte integer for protocol
+// versions that do not use 4-byte collection lengths.
+func appendCollectionSize(dest []byte, size int, version primitive.ProtocolVersion) ([]byte, error) {
+	if size < 0 {
+		return dest, cannotWriteCollectionSize(collectionSizeNegative(size))
+	} else if version.Uses4BytesCollectionLength() {
+		if size > math.MaxInt32 {
+			return dest, cannotWriteCollectionSize(collectionSizeTooLarge(size, math.MaxInt32))
+		}
+		return appendInt32(dest, int32(size)), nil
+	} else {
+		if size > math.MaxUint16 {
+			return dest, cannotWriteCollectionSize(collectionSizeTooLarge(size, math.MaxUint16))
+		}
+		return appendInt16(dest, int16(uint16(size))), nil
+	}
+}
+
+func putInt32(dest []byte, val int32) {
+	dest[0] = byte(val >> 24)
+	dest[1] = byte(val >> 16)
+	dest[2] = byte(val >> 8)
+	dest[3] = byte(val)
+}
+
+// The functions below append [bytes] values of the preferred Go types of simple CQL types; they are used by the fast
+// paths for collections and maps.
+
+func appendInt32Value(dest []byte, val int32) []byte {
+	return appendInt32(append(dest, 0, 0, 0, primitive.LengthOfInt), val)
+}
+
+func appendInt64Value(dest []byte, val int64) []byte {
+	return appendInt64(append(dest, 0, 0, 0, primitive.LengthOfLong), val)
+}
+
+func appendStringValue(dest []byte, val string) ([]byte, error) {
+	if len(val) > math.MaxInt32 {
+		return dest, errValueTooLong(len(val))
+	}
+	return append(appendInt32(dest, int32(len(val))), val...), nil
+}
+
+func appendBytesValue(dest []byte, val []byte) ([]byte, error) {
+	if val == nil {
+		return appendInt32(dest, -1), nil
+	} else if len(val) > math.MaxInt32 {
+		return dest, errValueTooLong(len(val))
+	}
+	return append(appendInt32(dest, int32(len(val))), val...), nil
+}
+
+// appendSliceFast appends the given source as a CQL list or set, bypassing reflection, if the source is a slice of
+// the preferred Go type of the element codec, and the element codec is one of the built-in codecs in this package.
+// Return parameter handled is false if the fast path cannot be used. If wasNil is true or an error is returned, the
+// caller must discard the returned slice.
+func appendSliceFast(dest []byte, source interface{}, elementCodec Codec, version primitive.ProtocolVersion) (result []byte, wasNil bool, handled bool, err error) {
+	switch s := source.(type) {
+	case []int32:
+		if elementCodec == Int {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for _, elem := range s {
+					dest = appendInt32Value(dest, elem)
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	case []int64:
+		if elementCodec == Bigint || elementCodec == Counter {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for _, elem := range s {
+					dest = appendInt64Value(dest, elem)
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	case []float64:
+		if elementCodec == Double {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for _, elem := range s {
+					dest = appendInt64Value(dest, int64(math.Float64bits(elem)))
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	case []float32:
+		if elementCodec == Float {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for _, elem := range s {
+					dest = appendInt32Value(dest, int32(math.Float32bits(elem)))
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	case []bool:
+		if elementCodec == Boolean {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for _, elem := range s {
+					dest = appendBool(append(dest, 0, 0, 0, 1), elem)
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	case []string:
+		if elementCodec == Varchar || elementCodec == Ascii {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for _, elem := range s {
+					if dest, err = appendStringValue(dest, elem); err != nil {
+						break
+					}
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	case [][]byte:
+		if elementCodec == Blob {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for _, elem := range s {
+					if dest, err = appendBytesValue(dest, elem); err != nil {
+						break
+					}
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	case []primitive.UUID:
+		if elementCodec == Uuid || elementCodec == Timeuuid {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for _, elem := range s {
+					dest = append(append(dest, 0, 0, 0, primitive.LengthOfUuid), elem[:]...)
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	}
+	return dest, false, false, nil
+}
+
+// appendMapFast appends the given source as a CQL map, bypassing reflection, if the source is a map with string keys
+// and values of the preferred Go type of the value codec, the key codec is Varchar or Ascii, and the value codec is
+// one of the built-in codecs in this package. Return parameter handled is false if the fast path cannot be used. If
+// wasNil is true or an error is returned, the caller must discard the returned slice.
+func appendMapFast(dest []byte, source interface{}, keyCodec Codec, valueCodec Codec, version primitive.ProtocolVersion) (result []byte, wasNil bool, handled bool, err error) {
+	if keyCodec != Varchar && keyCodec != Ascii {
+		return dest, false, false, nil
+	}
+	switch s := source.(type) {
+	case map[string]string:
+		if valueCodec == Varchar || valueCodec == Ascii {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for key, value := range s {
+					if dest, err = appendStringValue(dest, key); err != nil {
+						break
+					} else if dest, err = appendStringValue(dest, value); err != nil {
+						break
+					}
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	case map[string]int32:
+		if valueCodec == Int {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for key, value := range s {
+					if dest, err = appendStringValue(dest, key); err != nil {
+						break
+					}
+					dest = appendInt32Value(dest, value)
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	case map[string]int64:
+		if valueCodec == Bigint || valueCodec == Counter {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for key, value := range s {
+					if dest, err = appendStringValue(dest, key); err != nil {
+						break
+					}
+					dest = appendInt64Value(dest, value)
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	case map[string]float64:
+		if valueCodec == Double {
+			if dest, err = appendCollectionSize(dest, len(s), version); err == nil {
+				for key, value := range s {
+					if dest, err = appendStringValue(dest, key); err != nil {
+						break
+					}
+					dest = appendInt64Value(dest, int64(math.Float64bits(value)))
+				}
+			}
+			return dest, s == nil, true, err
+		}
+	}
+	return dest, false, false, nil
+}
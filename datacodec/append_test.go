@@ -0,0 +1,251 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+var appendPrefix = []byte{0xca, 0xfe}
+
+func TestAppendEncode(t *testing.T) {
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	customCodec := NewCustom(datatype.NewCustom("com.example.Type"))
+	listCodec, _ := NewList(datatype.NewList(datatype.Int))
+	mapCodec, _ := NewMap(datatype.NewMap(datatype.Varchar, datatype.Int))
+	tupleCodec, _ := NewTuple(datatype.NewTuple(datatype.Int))
+	tests := []struct {
+		name   string
+		codec  Codec
+		source interface{}
+	}{
+		{"int", Int, int32(123)},
+		{"int from int", Int, 123},
+		{"int nil", Int, nil},
+		{"int nil pointer", Int, (*int32)(nil)},
+		{"int fallback", Int, binaryID{1}},
+		{"int out of range", Int, int64(1) << 40},
+		{"bigint", Bigint, int64(-1)},
+		{"counter", Counter, int64(1)},
+		{"smallint", Smallint, int16(-2)},
+		{"tinyint", Tinyint, int8(-3)},
+		{"boolean", Boolean, true},
+		{"double", Double, 1.5},
+		{"float", Float, float32(-1.5)},
+		{"date", Date, now},
+		{"time", Time, now},
+		{"timestamp", Timestamp, now},
+		{"varchar", Varchar, "hello"},
+		{"varchar empty", Varchar, ""},
+		{"varchar bytes", Varchar, []byte("hello")},
+		{"varchar nil bytes", Varchar, []byte(nil)},
+		{"varchar fallback", Varchar, textID{"a", "b"}},
+		{"ascii", Ascii, "hello"},
+		{"blob", Blob, []byte{1, 2, 3}},
+		{"blob empty", Blob, []byte{}},
+		{"blob nil", Blob, nil},
+		{"custom", customCodec, []byte{1, 2, 3}},
+		{"uuid", Uuid, primitive.UUID(customerID1)},
+		{"uuid string", Uuid, "01020304-0506-0708-090a-0b0c0d0e0f10"},
+		{"timeuuid", Timeuuid, &customerID1},
+		{"uuid wrong type", Uuid, 123},
+		{"decimal", Decimal, CqlDecimal{}},
+		{"list fast path", listCodec, []int32{1, 2, 3}},
+		{"list fast path nil", listCodec, []int32(nil)},
+		{"list reflection", listCodec, []int{1, 2, 3}},
+		{"list reflection nil", listCodec, []int(nil)},
+		{"list element error", listCodec, []int64{1 << 40}},
+		{"list wrong type", listCodec, 123},
+		{"map fast path", mapCodec, map[string]int32{"a": 1}},
+		{"map fast path nil", mapCodec, map[string]int32(nil)},
+		{"map reflection", mapCodec, map[string]int{"a": 1}},
+		{"map value error", mapCodec, map[string]int64{"a": 1 << 40}},
+		{"tuple", tupleCodec, []interface{}{1}},
+		{"tuple nil", tupleCodec, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, version := range primitive.SupportedProtocolVersions() {
+				t.Run(version.String(), func(t *testing.T) {
+					expected, expectedErr := tt.codec.Encode(tt.source, version)
+					dest := append(make([]byte, 0, 64), appendPrefix...)
+					actual, wasNil, err := AppendEncode(tt.codec, dest, tt.source, version)
+					if expectedErr != nil {
+						assert.EqualError(t, err, expectedErr.Error())
+						assert.Equal(t, appendPrefix, actual)
+					} else {
+						require.NoError(t, err)
+						assert.Equal(t, expected == nil, wasNil)
+						assert.Equal(t, append(append([]byte{}, appendPrefix...), expected...), actual)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestAppendEncode_FastPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		dt     datatype.DataType
+		source interface{}
+		// same as source, but encoded through reflection
+		reflected interface{}
+	}{
+		{"list<int>", datatype.NewList(datatype.Int), []int32{1, -1}, [2]int32{1, -1}},
+		{"set<bigint>", datatype.NewSet(datatype.Bigint), []int64{1, -1}, [2]int64{1, -1}},
+		{"list<counter>", datatype.NewList(datatype.Counter), []int64{1, -1}, [2]int64{1, -1}},
+		{"list<double>", datatype.NewList(datatype.Double), []float64{1.5, -1}, [2]float64{1.5, -1}},
+		{"list<float>", datatype.NewList(datatype.Float), []float32{1.5, -1}, [2]float32{1.5, -1}},
+		{"list<boolean>", datatype.NewList(datatype.Boolean), []bool{true, false}, [2]bool{true, false}},
+		{"list<varchar>", datatype.NewList(datatype.Varchar), []string{"a", ""}, [2]string{"a", ""}},
+		{"list<ascii>", datatype.NewList(datatype.Ascii), []string{"a", ""}, [2]string{"a", ""}},
+		{"list<blob>", datatype.NewList(datatype.Blob), [][]byte{{1}, {}, nil}, [3][]byte{{1}, {}, nil}},
+		{"list<uuid>", datatype.NewList(datatype.Uuid), []primitive.UUID{primitive.UUID(customerID1)}, [1]primitive.UUID{primitive.UUID(customerID1)}},
+		{"list<timeuuid>", datatype.NewList(datatype.Timeuuid), []primitive.UUID{primitive.UUID(customerID1)}, [1]primitive.UUID{primitive.UUID(customerID1)}},
+		{"map<varchar,varchar>", datatype.NewMap(datatype.Varchar, datatype.Varchar), map[string]string{"a": "b"}, map[string]*string{"a": stringPtr("b")}},
+		{"map<ascii,int>", datatype.NewMap(datatype.Ascii, datatype.Int), map[string]int32{"a": 1}, map[string]*int32{"a": int32Ptr(1)}},
+		{"map<varchar,bigint>", datatype.NewMap(datatype.Varchar, datatype.Bigint), map[string]int64{"a": 1}, map[string]*int64{"a": int64Ptr(1)}},
+		{"map<varchar,double>", datatype.NewMap(datatype.Varchar, datatype.Double), map[string]float64{"a": 1.5}, map[string]*float64{"a": float64Ptr(1.5)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := NewCodec(tt.dt)
+			require.NoError(t, err)
+			for _, version := range primitive.SupportedProtocolVersions() {
+				t.Run(version.String(), func(t *testing.T) {
+					expected, err := codec.Encode(tt.reflected, version)
+					require.NoError(t, err)
+					actual, wasNil, err := AppendEncode(codec, nil, tt.source, version)
+					require.NoError(t, err)
+					assert.False(t, wasNil)
+					assert.Equal(t, expected, actual)
+				})
+			}
+		})
+	}
+}
+
+func TestAppendEncode_RegisteredElementCodec(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(upperCaseCodec{datatype.Varchar}))
+	codec, err := registry.NewCodec(datatype.NewList(datatype.Varchar))
+	require.NoError(t, err)
+	actual, _, err := AppendEncode(codec, nil, []string{"a"}, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 1, 0, 0, 0, 7, 'U', 'P', 'P', 'E', 'R', ':', 'a'}, actual)
+}
+
+func TestAppendValue(t *testing.T) {
+	actual, err := AppendValue(Int, appendPrefix[:2:2], int32(1), primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xca, 0xfe, 0, 0, 0, 4, 0, 0, 0, 1}, actual)
+	actual, err = AppendValue(Int, appendPrefix[:2:2], nil, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xca, 0xfe, 0xff, 0xff, 0xff, 0xff}, actual)
+	actual, err = AppendValue(Varchar, appendPrefix[:2:2], "", primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xca, 0xfe, 0, 0, 0, 0}, actual)
+	actual, err = AppendValue(Int, appendPrefix[:2:2], "not a number", primitive.ProtocolVersion5)
+	assert.Error(t, err)
+	assert.Equal(t, appendPrefix, actual)
+}
+
+func TestAppendEncode_Allocations(t *testing.T) {
+	listCodec, _ := NewList(datatype.NewList(datatype.Int))
+	mapCodec, _ := NewMap(datatype.NewMap(datatype.Varchar, datatype.Int))
+	var source interface{} = int32(123456)
+	var str interface{} = "hello"
+	var list interface{} = []int32{1, 2, 3}
+	var m interface{} = map[string]int32{"a": 1, "b": 2}
+	buf := make([]byte, 0, 1024)
+	for _, tt := range []struct {
+		name   string
+		codec  Codec
+		source interface{}
+	}{
+		{"int", Int, source},
+		{"varchar", Varchar, str},
+		{"list<int>", listCodec, list},
+		{"map<varchar,int>", mapCodec, m},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(100, func() {
+				_, _, _ = AppendEncode(tt.codec, buf[:0], tt.source, primitive.ProtocolVersion5)
+			})
+			assert.Zero(t, allocs)
+		})
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	benchmarkEncode(b, func(codec Codec, _ []byte, source interface{}) ([]byte, error) {
+		return codec.Encode(source, primitive.ProtocolVersion5)
+	})
+}
+
+func BenchmarkAppendEncode(b *testing.B) {
+	benchmarkEncode(b, func(codec Codec, dest []byte, source interface{}) ([]byte, error) {
+		dest, _, err := AppendEncode(codec, dest, source, primitive.ProtocolVersion5)
+		return dest, err
+	})
+}
+
+func benchmarkEncode(b *testing.B, encode func(codec Codec, dest []byte, source interface{}) ([]byte, error)) {
+	listCodec, _ := NewList(datatype.NewList(datatype.Int))
+	listOfVarcharCodec, _ := NewList(datatype.NewList(datatype.Varchar))
+	mapCodec, _ := NewMap(datatype.NewMap(datatype.Varchar, datatype.Int))
+	ints := make([]int32, 100)
+	strings := make([]string, 100)
+	entries := make(map[string]int32, 100)
+	for i := range ints {
+		ints[i] = int32(i)
+		strings[i] = fmt.Sprint("element", i)
+		entries[strings[i]] = int32(i)
+	}
+	for _, bb := range []struct {
+		name   string
+		codec  Codec
+		source interface{}
+	}{
+		{"int", Int, int32(123456)},
+		{"bigint", Bigint, int64(123456)},
+		{"double", Double, 123.456},
+		{"varchar", Varchar, "hello world"},
+		{"uuid", Uuid, primitive.UUID(customerID1)},
+		{"list<int>", listCodec, ints},
+		{"list<varchar>", listOfVarcharCodec, strings},
+		{"map<varchar,int>", mapCodec, entries},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			buf := make([]byte, 0, 64*1024)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := encode(bb.codec, buf[:0], bb.source); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
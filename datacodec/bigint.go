@@ -48,18 +48,28 @@ func (c *bigintCodec) Encode(source interface{}, version primitive.ProtocolVersi
 		dest = writeInt64(val)
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *bigintCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	var val int64
+	if val, wasNil, err = convertToInt64(source); err != nil {
+		return appendEncoded(c, dest, source, version)
+	} else if !wasNil {
+		dest = appendInt64(dest, val)
+	}
+	return dest, wasNil, nil
+}
+
 func (c *bigintCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	var val int64
 	if val, wasNull, err = readInt64(source); err == nil {
 		err = convertFromInt64(val, wasNull, dest)
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -263,6 +273,10 @@ func convertFromInt64(val int64, wasNull bool, dest interface{}) (err error) {
 	return
 }
 
+func appendInt64(dest []byte, val int64) []byte {
+	return append(dest, byte(val>>56), byte(val>>48), byte(val>>40), byte(val>>32), byte(val>>24), byte(val>>16), byte(val>>8), byte(val))
+}
+
 func writeInt64(val int64) (dest []byte) {
 	dest = make([]byte, primitive.LengthOfLong)
 	binary.BigEndian.PutUint64(dest, uint64(val))
@@ -0,0 +1,219 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+	"github.com/datastax/go-cassandra-native-protocol/token"
+)
+
+type unsetValue struct{}
+
+// Unset can be passed to a Binder in place of a Go value to leave the corresponding bound variable unset. Unset values
+// are only supported in protocol version 4 and higher.
+var Unset interface{} = unsetValue{}
+
+// Binder encodes Go values into bound variables of a prepared statement, that is, into primitive.Value instances
+// suitable for message.QueryOptions PositionalValues or NamedValues. A Binder is created from the VariablesMetadata
+// of a message.PreparedResult; it creates one codec per variable upfront, then reuses these codecs for every bound
+// value. A Binder is safe for concurrent use.
+//
+// Values already encoded as *primitive.Value are bound as is. A nil value is bound as a CQL NULL. The special value
+// Unset is bound as an unset value.
+type Binder struct {
+	variables []*message.ColumnMetadata
+	metadata  *message.VariablesMetadata
+	codecs    []Codec
+	version   primitive.ProtocolVersion
+}
+
+// NewBinder creates a new Binder for the variables described by the given metadata, to be encoded with the given
+// protocol version. An error is returned if a codec cannot be created for one of the variables, or if the metadata
+// partition key indices are out of range. Codecs are created with the DefaultRegistry.
+func NewBinder(metadata *message.VariablesMetadata, version primitive.ProtocolVersion) (*Binder, error) {
+	return DefaultRegistry.NewBinder(metadata, version)
+}
+
+// NewBinder creates a new Binder whose codecs are created by this registry; see the package-level NewBinder function.
+func (r *Registry) NewBinder(metadata *message.VariablesMetadata, version primitive.ProtocolVersion) (*Binder, error) {
+	if metadata == nil {
+		return nil, errors.New("cannot create binder: metadata is nil")
+	}
+	codecs := make([]Codec, len(metadata.Columns))
+	for i, variable := range metadata.Columns {
+		if codec, err := r.NewCodec(variable.Type); err != nil {
+			return nil, fmt.Errorf("cannot create binder: %w", errCannotCreateVariableCodec(i, variable.Name, err))
+		} else {
+			codecs[i] = codec
+		}
+	}
+	for _, index := range metadata.PkIndices {
+		if int(index) >= len(metadata.Columns) {
+			return nil, fmt.Errorf("cannot create binder: partition key index out of range: %d", index)
+		}
+	}
+	return &Binder{variables: metadata.Columns, metadata: metadata, codecs: codecs, version: version}, nil
+}
+
+// Variables returns the bound variables specs of this binder.
+func (b *Binder) Variables() []*message.ColumnMetadata {
+	return b.variables
+}
+
+// Bind encodes the given arguments into positional values, in the order of the bound variables. The arguments can be:
+//
+//   - A slice of interface{}: each element is bound to the variable at the same position. If the slice is shorter
+//     than the number of variables, the remaining variables are left unset; this is only possible in protocol version
+//     4 and higher.
+//   - A map with string keys, or a pointer to such a map: each variable is bound to the map entry with the same name.
+//   - A struct, or a pointer to a struct: each variable is bound to the struct field with the same name, using the
+//     same rules as for user-defined types, see the package documentation.
+//
+// With maps and structs, variables without a matching entry or field are left unset; this is only possible in
+// protocol version 4 and higher. When the same name appears more than once in the variables, as can be the case for
+// named variables, all such variables are bound to the same value.
+func (b *Binder) Bind(args interface{}) ([]*primitive.Value, error) {
+	values := make([]*primitive.Value, len(b.variables))
+	err := b.bind(args, func(i int, value *primitive.Value) {
+		values[i] = value
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// BindNamed encodes the given arguments into named values, keyed by bound variable name. The arguments must be a map
+// with string keys or a struct, or a pointer to any of those; see Bind.
+func (b *Binder) BindNamed(args interface{}) (map[string]*primitive.Value, error) {
+	if _, positional := args.([]interface{}); positional {
+		return nil, fmt.Errorf("cannot bind %T: named values require a map or a struct", args)
+	}
+	values := make(map[string]*primitive.Value, len(b.variables))
+	err := b.bind(args, func(i int, value *primitive.Value) {
+		values[b.variables[i].Name] = value
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// PartitionKey returns the contents of the values bound to the partition key variables, in partition key order, as
+// indicated by the metadata PkIndices; see token.PartitionKeyFromValues. The returned slice is nil if the metadata
+// contained no partition key indices. An error is returned if a partition key variable is missing, null or unset.
+func (b *Binder) PartitionKey(values []*primitive.Value) ([][]byte, error) {
+	return token.PartitionKeyFromValues(b.metadata, values)
+}
+
+func (b *Binder) bind(args interface{}, set func(i int, value *primitive.Value)) (err error) {
+	var lookup func(i int, name string) (arg interface{}, found bool, err error)
+	if positional, ok := args.([]interface{}); ok {
+		if len(positional) > len(b.variables) {
+			err = fmt.Errorf("expected at most %d values, got: %d", len(b.variables), len(positional))
+		}
+		lookup = func(i int, _ string) (interface{}, bool, error) {
+			if i < len(positional) {
+				return positional[i], true, nil
+			}
+			return nil, false, nil
+		}
+	} else {
+		lookup, err = newNamedLookup(args)
+	}
+	for i := 0; err == nil && i < len(b.variables); i++ {
+		name := b.variables[i].Name
+		var value *primitive.Value
+		if arg, found, lookupErr := lookup(i, name); lookupErr != nil {
+			err = errCannotBindVariable(i, name, lookupErr)
+		} else if !found {
+			value, err = b.unset(i, name)
+		} else if value, err = b.encode(i, arg); err != nil {
+			err = errCannotBindVariable(i, name, err)
+		}
+		if err == nil {
+			set(i, value)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("cannot bind %T: %w", args, err)
+	}
+	return
+}
+
+func (b *Binder) unset(i int, name string) (*primitive.Value, error) {
+	if !b.version.SupportsUnsetValues() {
+		return nil, fmt.Errorf("missing value for variable %d (%s)", i, name)
+	}
+	return primitive.NewUnsetValue(), nil
+}
+
+func (b *Binder) encode(i int, arg interface{}) (*primitive.Value, error) {
+	switch a := arg.(type) {
+	case unsetValue:
+		if !b.version.SupportsUnsetValues() {
+			return nil, fmt.Errorf("unset values are not supported in %v", b.version)
+		}
+		return primitive.NewUnsetValue(), nil
+	case *primitive.Value:
+		if a == nil {
+			return primitive.NewNullValue(), nil
+		}
+		return a, nil
+	}
+	if encoded, err := b.codecs[i].Encode(arg, b.version); err != nil {
+		return nil, err
+	} else {
+		return primitive.NewValue(encoded), nil
+	}
+}
+
+func newNamedLookup(args interface{}) (func(i int, name string) (interface{}, bool, error), error) {
+	argsValue := reflect.ValueOf(args)
+	if argsValue.Kind() == reflect.Ptr && !argsValue.IsNil() {
+		argsValue = argsValue.Elem()
+	}
+	switch argsValue.Kind() {
+	case reflect.Map:
+		if argsValue.Type().Key().Kind() != reflect.String {
+			return nil, errWrongElementType("map key", typeOfString, argsValue.Type().Key())
+		}
+		return func(_ int, name string) (interface{}, bool, error) {
+			value := argsValue.MapIndex(reflect.ValueOf(name).Convert(argsValue.Type().Key()))
+			if !value.IsValid() {
+				return nil, false, nil
+			}
+			return value.Interface(), true, nil
+		}, nil
+	case reflect.Struct:
+		ext, err := newStructExtractor(argsValue)
+		if err != nil {
+			return nil, err
+		}
+		return func(i int, name string) (interface{}, bool, error) {
+			if findStructFieldByName(argsValue.Type(), name) == nil {
+				return nil, false, nil
+			}
+			value, err := ext.getElem(i, name)
+			return value, true, err
+		}, nil
+	}
+	return nil, ErrSourceTypeNotSupported
+}
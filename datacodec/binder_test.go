@@ -0,0 +1,156 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+var variablesMetadata = &message.VariablesMetadata{
+	PkIndices: []uint16{1, 0},
+	Columns: []*message.ColumnMetadata{
+		{Keyspace: "ks1", Table: "table1", Name: "user_id", Index: 0, Type: datatype.Int},
+		{Keyspace: "ks1", Table: "table1", Name: "name", Index: 1, Type: datatype.Varchar},
+		{Keyspace: "ks1", Table: "table1", Name: "score", Index: 2, Type: datatype.Bigint},
+	},
+}
+
+type boundRow struct {
+	UserId int    `cql:"user_id"`
+	Name   string `cql:"name,omitempty"`
+	Score  int64
+	Other  string
+}
+
+var (
+	boundUserId = primitive.NewValue([]byte{0, 0, 0, 123})
+	boundName   = primitive.NewValue([]byte{a, b, c})
+	boundScore  = primitive.NewValue([]byte{0, 0, 0, 0, 0, 0, 0, 42})
+)
+
+func TestNewBinder(t *testing.T) {
+	binder, err := NewBinder(variablesMetadata, primitive.ProtocolVersion4)
+	require.NoError(t, err)
+	assert.Equal(t, variablesMetadata.Columns, binder.Variables())
+	t.Run("nil metadata", func(t *testing.T) {
+		_, err := NewBinder(nil, primitive.ProtocolVersion4)
+		assert.EqualError(t, err, "cannot create binder: metadata is nil")
+	})
+	t.Run("wrong data type", func(t *testing.T) {
+		_, err := NewBinder(&message.VariablesMetadata{
+			Columns: []*message.ColumnMetadata{{Name: "col1", Type: wrongDataType{}}},
+		}, primitive.ProtocolVersion4)
+		assert.EqualError(t, err, "cannot create binder: cannot create codec for variable 0 (col1): cannot create data codec for CQL type 666")
+	})
+	t.Run("pk index out of range", func(t *testing.T) {
+		_, err := NewBinder(&message.VariablesMetadata{
+			PkIndices: []uint16{1},
+			Columns:   []*message.ColumnMetadata{{Name: "col1", Type: datatype.Int}},
+		}, primitive.ProtocolVersion4)
+		assert.EqualError(t, err, "cannot create binder: partition key index out of range: 1")
+	})
+	t.Run("registry", func(t *testing.T) {
+		registry := NewRegistry()
+		require.NoError(t, registry.Register(upperCaseCodec{datatype.Varchar}))
+		binder, err := registry.NewBinder(variablesMetadata, primitive.ProtocolVersion4)
+		require.NoError(t, err)
+		values, err := binder.Bind([]interface{}{123, "bob", int64(42)})
+		require.NoError(t, err)
+		assert.Equal(t, []*primitive.Value{boundUserId, primitive.NewValue([]byte("UPPER:bob")), boundScore}, values)
+	})
+}
+
+func TestBinder_Bind(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			binder, err := NewBinder(variablesMetadata, version)
+			require.NoError(t, err)
+			unset := primitive.NewUnsetValue()
+			tests := []struct {
+				name     string
+				args     interface{}
+				expected []*primitive.Value
+				err      string
+				errV3    string
+			}{
+				{"positional", []interface{}{123, "abc", 42}, []*primitive.Value{boundUserId, boundName, boundScore}, "", ""},
+				{"positional nil", []interface{}{123, nil, nil}, []*primitive.Value{boundUserId, primitive.NewNullValue(), primitive.NewNullValue()}, "", ""},
+				{"positional encoded", []interface{}{boundUserId, (*primitive.Value)(nil), int64(42)}, []*primitive.Value{boundUserId, primitive.NewNullValue(), boundScore}, "", ""},
+				{"positional unset", []interface{}{123, Unset, 42}, []*primitive.Value{boundUserId, unset, boundScore}, "", "cannot bind []interface {}: cannot bind variable 1 (name): unset values are not supported in " + version.String()},
+				{"positional missing", []interface{}{123}, []*primitive.Value{boundUserId, unset, unset}, "", "cannot bind []interface {}: missing value for variable 1 (name)"},
+				{"positional too many", []interface{}{1, 2, 3, 4}, nil, "cannot bind []interface {}: expected at most 3 values, got: 4", ""},
+				{"positional wrong type", []interface{}{"abc", "abc", 42}, nil, "cannot bind []interface {}: cannot bind variable 0 (user_id): cannot encode string as CQL int", ""},
+				{"map", map[string]interface{}{"user_id": 123, "name": "abc", "score": 42, "other": true}, []*primitive.Value{boundUserId, boundName, boundScore}, "", ""},
+				{"map pointer", &map[string]int{"user_id": 123, "score": 42}, []*primitive.Value{boundUserId, unset, boundScore}, "", "cannot bind *map[string]int: missing value for variable 1 (name)"},
+				{"map wrong key", map[int]interface{}{}, nil, "cannot bind map[int]interface {}: wrong map key, expected string, got: int", ""},
+				{"struct", boundRow{123, "abc", 42, "other"}, []*primitive.Value{boundUserId, boundName, boundScore}, "", ""},
+				{"struct omitempty", &boundRow{123, "", 42, "other"}, []*primitive.Value{boundUserId, primitive.NewNullValue(), boundScore}, "", ""},
+				{"unsupported", 123, nil, "cannot bind int: source type not supported", ""},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					actual, err := binder.Bind(tt.args)
+					if tt.errV3 != "" && !version.SupportsUnsetValues() {
+						assert.Nil(t, actual)
+						assert.EqualError(t, err, tt.errV3)
+					} else if tt.err != "" {
+						assert.Nil(t, actual)
+						assert.Contains(t, err.Error(), tt.err)
+					} else {
+						assert.NoError(t, err)
+						assert.Equal(t, tt.expected, actual)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestBinder_BindNamed(t *testing.T) {
+	binder, err := NewBinder(variablesMetadata, primitive.ProtocolVersion4)
+	require.NoError(t, err)
+	actual, err := binder.BindNamed(map[string]interface{}{"user_id": 123, "name": "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*primitive.Value{"user_id": boundUserId, "name": boundName, "score": primitive.NewUnsetValue()}, actual)
+	actual, err = binder.BindNamed(boundRow{123, "abc", 42, "other"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*primitive.Value{"user_id": boundUserId, "name": boundName, "score": boundScore}, actual)
+	_, err = binder.BindNamed([]interface{}{123})
+	assert.EqualError(t, err, "cannot bind []interface {}: named values require a map or a struct")
+}
+
+func TestBinder_PartitionKey(t *testing.T) {
+	binder, err := NewBinder(variablesMetadata, primitive.ProtocolVersion4)
+	require.NoError(t, err)
+	components, err := binder.PartitionKey([]*primitive.Value{boundUserId, boundName, boundScore})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{boundName.Contents, boundUserId.Contents}, components)
+	_, err = binder.PartitionKey([]*primitive.Value{boundUserId, primitive.NewUnsetValue(), boundScore})
+	assert.EqualError(t, err, "partition key variable 1 (name) is null or unset")
+	_, err = binder.PartitionKey([]*primitive.Value{boundUserId})
+	assert.EqualError(t, err, "partition key variable 1 (name) is missing")
+	noPk, err := NewBinder(&message.VariablesMetadata{Columns: variablesMetadata.Columns}, primitive.ProtocolVersion3)
+	require.NoError(t, err)
+	components, err = noPk.PartitionKey([]*primitive.Value{boundUserId, boundName, boundScore})
+	assert.NoError(t, err)
+	assert.Nil(t, components)
+}
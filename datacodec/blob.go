@@ -43,14 +43,22 @@ func (c *blobCodec) DataType() datatype.DataType {
 
 func (c *blobCodec) Encode(source interface{}, version primitive.ProtocolVersion) (dest []byte, err error) {
 	if dest, err = convertToBytes(source); err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *blobCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	var val []byte
+	if val, err = convertToBytes(source); err != nil {
+		return appendEncoded(c, dest, source, version)
+	}
+	return append(dest, val...), val == nil, nil
+}
+
 func (c *blobCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	if wasNull, err = convertFromBytes(source, dest); err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -36,18 +36,28 @@ func (c *booleanCodec) Encode(source interface{}, version primitive.ProtocolVers
 		dest = writeBool(val)
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *booleanCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	var val bool
+	if val, wasNil, err = convertToBoolean(source); err != nil {
+		return appendEncoded(c, dest, source, version)
+	} else if !wasNil {
+		dest = appendBool(dest, val)
+	}
+	return dest, wasNil, nil
+}
+
 func (c *booleanCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	var val bool
 	if val, wasNull, err = readBool(source); err == nil {
 		err = convertFromBoolean(val, wasNull, dest)
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -238,6 +248,14 @@ func convertFromBoolean(val bool, wasNull bool, dest interface{}) (err error) {
 	return
 }
 
+func appendBool(dest []byte, val bool) []byte {
+	if val {
+		return append(dest, 1)
+	} else {
+		return append(dest, 0)
+	}
+}
+
 func writeBool(val bool) []byte {
 	if val {
 		return []byte{1}
@@ -44,10 +44,34 @@ type Codec interface {
 	DataType() datatype.DataType
 }
 
-// NewCodec creates a new codec for the given data type. For simple CQL types, this function actually returns one of
-// the existing singletons. For complex CQL types, it delegates to one of the constructor functions available:
-// NewList, NewSet, NewMap, NewTuple, NewUserDefined and NewCustom.
+// customCodecs are the codecs for known custom types, keyed by class name.
+var customCodecs = map[string]Codec{
+	datatype.Point.ClassName:      Point,
+	datatype.LineString.ClassName: LineString,
+	datatype.Polygon.ClassName:    Polygon,
+	datatype.DateRange.ClassName:  DateRange,
+}
+
+// customGoTypes are the preferred Go types for known custom types, keyed by class name.
+var customGoTypes = map[string]reflect.Type{
+	datatype.Point.ClassName:      typeOfDsePoint,
+	datatype.LineString.ClassName: typeOfDseLineString,
+	datatype.Polygon.ClassName:    typeOfDsePolygon,
+	datatype.DateRange.ClassName:  typeOfDseDateRange,
+}
+
+// NewCodec creates a new codec for the given data type, using the DefaultRegistry. For simple CQL types, this function
+// actually returns one of the existing singletons. For complex CQL types, it delegates to one of the constructor
+// functions available: NewList, NewSet, NewMap, NewTuple, NewUserDefined, NewVector and NewCustom. Custom types whose
+// class name denotes a CQL vector type are handled by NewVector; DSE geospatial types are handled by the Point,
+// LineString and Polygon codecs. See Registry for how to register codecs for other CQL or Go types.
 func NewCodec(dt datatype.DataType) (Codec, error) {
+	return DefaultRegistry.NewCodec(dt)
+}
+
+// newBuiltinCodec creates a codec for the given data type, ignoring any registered codec for the data type itself;
+// codecs for complex types still use this registry to create the codecs of their elements.
+func (r *Registry) newBuiltinCodec(dt datatype.DataType) (Codec, error) {
 	switch dt.Code() {
 	case primitive.DataTypeCodeAscii:
 		return Ascii, nil
@@ -90,17 +114,22 @@ func NewCodec(dt datatype.DataType) (Codec, error) {
 	case primitive.DataTypeCodeVarint:
 		return Varint, nil
 	case primitive.DataTypeCodeCustom:
+		if vectorType, ok := asVector(dt); ok {
+			return r.newVector(vectorType)
+		} else if codec, found := customCodecs[dt.(*datatype.Custom).ClassName]; found {
+			return codec, nil
+		}
 		return NewCustom(dt.(*datatype.Custom)), nil
 	case primitive.DataTypeCodeList:
-		return NewList(dt.(*datatype.List))
+		return r.newList(dt.(*datatype.List))
 	case primitive.DataTypeCodeSet:
-		return NewSet(dt.(*datatype.Set))
+		return r.newSet(dt.(*datatype.Set))
 	case primitive.DataTypeCodeMap:
-		return NewMap(dt.(*datatype.Map))
+		return r.newMap(dt.(*datatype.Map))
 	case primitive.DataTypeCodeTuple:
-		return NewTuple(dt.(*datatype.Tuple))
+		return r.newTuple(dt.(*datatype.Tuple))
 	case primitive.DataTypeCodeUdt:
-		return NewUserDefined(dt.(*datatype.UserDefined))
+		return r.newUserDefined(dt.(*datatype.UserDefined))
 	}
 	return nil, errCannotCreateCodec(dt)
 }
@@ -122,6 +151,15 @@ func PreferredGoType(dt datatype.DataType) (reflect.Type, error) {
 	case primitive.DataTypeCodeCounter:
 		return typeOfInt64, nil
 	case primitive.DataTypeCodeCustom:
+		if vectorType, ok := asVector(dt); ok {
+			elemType, err := PreferredGoType(vectorType.ElementType)
+			if err != nil {
+				return nil, err
+			}
+			return reflect.SliceOf(elemType), nil
+		} else if goType, found := customGoTypes[dt.(*datatype.Custom).ClassName]; found {
+			return goType, nil
+		}
 		return typeOfByteSlice, nil
 	case primitive.DataTypeCodeDate:
 		return typeOfTime, nil
@@ -185,3 +223,16 @@ func PreferredGoType(dt datatype.DataType) (reflect.Type, error) {
 	}
 	return nil, errCannotFindGoType(dt)
 }
+
+// asVector returns the given data type as a vector type, if it is either a datatype.Vector, or a datatype.Custom whose
+// class name denotes a vector type. Class names are not cached, since they come from the wire and are thus unbounded;
+// class names that are not vector class names are rejected without being parsed.
+func asVector(dt datatype.DataType) (*datatype.Vector, bool) {
+	switch t := dt.(type) {
+	case *datatype.Vector:
+		return t, true
+	case *datatype.Custom:
+		return t.AsVector()
+	}
+	return nil, false
+}
@@ -18,7 +18,6 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"math"
 	"reflect"
 
 	"github.com/datastax/go-cassandra-native-protocol/datatype"
@@ -26,10 +25,14 @@ import (
 )
 
 func NewList(dataType *datatype.List) (Codec, error) {
+	return DefaultRegistry.newList(dataType)
+}
+
+func (r *Registry) newList(dataType *datatype.List) (Codec, error) {
 	if dataType == nil {
 		return nil, ErrNilDataType
 	}
-	codec, err := NewCodec(dataType.ElementType)
+	codec, err := r.NewCodec(dataType.ElementType)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create codec for list elements: %w", err)
 	}
@@ -37,10 +40,14 @@ func NewList(dataType *datatype.List) (Codec, error) {
 }
 
 func NewSet(dataType *datatype.Set) (Codec, error) {
+	return DefaultRegistry.newSet(dataType)
+}
+
+func (r *Registry) newSet(dataType *datatype.Set) (Codec, error) {
 	if dataType == nil {
 		return nil, ErrNilDataType
 	}
-	codec, err := NewCodec(dataType.ElementType)
+	codec, err := r.NewCodec(dataType.ElementType)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create codec for set elements: %w", err)
 	}
@@ -57,14 +64,29 @@ func (c *collectionCodec) DataType() datatype.DataType {
 }
 
 func (c *collectionCodec) Encode(source interface{}, version primitive.ProtocolVersion) (dest []byte, err error) {
-	ext, size, err := c.createExtractor(source)
-	if err == nil && ext != nil {
-		dest, err = writeCollection(ext, c.elementCodec, size, version)
+	dest, _, err = c.AppendEncode(nil, source, version)
+	return
+}
+
+// AppendEncode appends the encoded collection to dest. Slices of the preferred Go type of the element type, e.g.
+// []int32 for list<int>, are encoded without reflection, provided that the element codec is a built-in codec.
+func (c *collectionCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	var handled bool
+	if result, wasNil, handled, err = appendSliceFast(dest, source, c.elementCodec, version); !handled {
+		var ext extractor
+		var size int
+		if ext, size, err = c.createExtractor(source); err == nil {
+			if wasNil = ext == nil; !wasNil {
+				result, err = appendCollection(dest, ext, c.elementCodec, size, version)
+			}
+		}
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		return dest, false, errCannotEncode(source, c.DataType(), version, err)
+	} else if wasNil {
+		return dest, true, nil
 	}
-	return
+	return result, false, nil
 }
 
 func (c *collectionCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
@@ -130,20 +152,27 @@ func (c *collectionCodec) createInjector(dest interface{}, wasNull bool) (inject
 }
 
 func writeCollection(ext extractor, elementCodec Codec, size int, version primitive.ProtocolVersion) ([]byte, error) {
-	buf := &bytes.Buffer{}
-	if err := writeCollectionSize(size, buf, version); err != nil {
+	dest, err := appendCollection(nil, ext, elementCodec, size, version)
+	if err != nil {
 		return nil, err
 	}
+	return dest, nil
+}
+
+func appendCollection(dest []byte, ext extractor, elementCodec Codec, size int, version primitive.ProtocolVersion) ([]byte, error) {
+	start := len(dest)
+	dest, err := appendCollectionSize(dest, size, version)
+	if err != nil {
+		return dest, err
+	}
 	for i := 0; i < size; i++ {
 		if elem, err := ext.getElem(i, i); err != nil {
-			return nil, errCannotExtractElement(i, err)
-		} else if encodedElem, err := elementCodec.Encode(elem, version); err != nil {
-			return nil, errCannotEncodeElement(i, err)
-		} else {
-			_ = primitive.WriteBytes(encodedElem, buf)
+			return dest[:start], errCannotExtractElement(i, err)
+		} else if dest, err = AppendValue(elementCodec, dest, elem, version); err != nil {
+			return dest[:start], errCannotEncodeElement(i, err)
 		}
 	}
-	return buf.Bytes(), nil
+	return dest, nil
 }
 
 func readCollection(source []byte, injectorFactory func(int) (injector, error), elementCodec Codec, version primitive.ProtocolVersion) error {
@@ -172,28 +201,12 @@ func readCollection(source []byte, injectorFactory func(int) (injector, error),
 	return nil
 }
 
-func writeCollectionSize(size int, dest io.Writer, version primitive.ProtocolVersion) (err error) {
-	if version.Uses4BytesCollectionLength() {
-		if size > math.MaxInt32 {
-			err = collectionSizeTooLarge(size, math.MaxInt32)
-		} else if size < 0 {
-			err = collectionSizeNegative(size)
-		} else {
-			err = primitive.WriteInt(int32(size), dest)
-		}
-	} else {
-		if size > math.MaxUint16 {
-			err = collectionSizeTooLarge(size, math.MaxUint16)
-		} else if size < 0 {
-			err = collectionSizeNegative(size)
-		} else {
-			err = primitive.WriteShort(uint16(size), dest)
-		}
-	}
-	if err != nil {
-		err = cannotWriteCollectionSize(err)
+func writeCollectionSize(size int, dest io.Writer, version primitive.ProtocolVersion) error {
+	encoded, err := appendCollectionSize(nil, size, version)
+	if err == nil {
+		_, err = dest.Write(encoded)
 	}
-	return
+	return err
 }
 
 func readCollectionSize(source io.Reader, version primitive.ProtocolVersion) (size int, err error) {
@@ -95,11 +95,24 @@ func (c *dateCodec) Encode(source interface{}, version primitive.ProtocolVersion
 		}
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *dateCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	if !version.SupportsDataType(c.DataType().Code()) {
+		return appendEncoded(c, dest, source, version)
+	}
+	var val int32
+	if val, wasNil, err = convertToInt32Date(source, c.layout); err != nil {
+		return appendEncoded(c, dest, source, version)
+	} else if !wasNil {
+		dest = appendInt32(dest, val-math.MinInt32)
+	}
+	return dest, wasNil, nil
+}
+
 func (c *dateCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	if !version.SupportsDataType(c.DataType().Code()) {
 		wasNull = len(source) == 0
@@ -111,7 +124,7 @@ func (c *dateCodec) Decode(source []byte, dest interface{}, version primitive.Pr
 		}
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -0,0 +1,427 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// DateRangePrecision is the precision of a DseDateRangeBound.
+type DateRangePrecision uint8
+
+const (
+	DateRangePrecisionYear        = DateRangePrecision(0)
+	DateRangePrecisionMonth       = DateRangePrecision(1)
+	DateRangePrecisionDay         = DateRangePrecision(2)
+	DateRangePrecisionHour        = DateRangePrecision(3)
+	DateRangePrecisionMinute      = DateRangePrecision(4)
+	DateRangePrecisionSecond      = DateRangePrecision(5)
+	DateRangePrecisionMillisecond = DateRangePrecision(6)
+)
+
+func (p DateRangePrecision) String() string {
+	switch p {
+	case DateRangePrecisionYear:
+		return "YEAR"
+	case DateRangePrecisionMonth:
+		return "MONTH"
+	case DateRangePrecisionDay:
+		return "DAY"
+	case DateRangePrecisionHour:
+		return "HOUR"
+	case DateRangePrecisionMinute:
+		return "MINUTE"
+	case DateRangePrecisionSecond:
+		return "SECOND"
+	case DateRangePrecisionMillisecond:
+		return "MILLISECOND"
+	}
+	return fmt.Sprintf("DateRangePrecision(%d)", uint8(p))
+}
+
+// DseDateRangeBound is a bound of a DseDateRange. A bound is either unbounded, represented by a star ("*") in the
+// literal syntax, or a timestamp associated with a precision.
+type DseDateRangeBound struct {
+	// Timestamp is the bound's timestamp; it is only significant to the millisecond. It is ignored when Unbounded is
+	// true.
+	Timestamp time.Time
+	// Precision is the bound's precision; it determines how the bound is formatted. It is ignored when Unbounded is
+	// true.
+	Precision DateRangePrecision
+	// Unbounded is true if this bound is open.
+	Unbounded bool
+}
+
+// DseDateRangeUnbounded is the unbounded DseDateRangeBound.
+var DseDateRangeUnbounded = DseDateRangeBound{Unbounded: true}
+
+// DseDateRange is the Go representation of the DSE DateRangeType. A date range is either a single-bound range,
+// in which case Upper is nil, or a range with both lower and upper bounds, each one of them possibly unbounded.
+type DseDateRange struct {
+	Lower DseDateRangeBound
+	Upper *DseDateRangeBound
+}
+
+// DateRange is a codec for the DSE DateRangeType, a custom type. Its preferred Go type is DseDateRange, but it can
+// encode from and decode to string as well, in which case the string is the literal representation of the date
+// range, e.g. "[2017-01 TO 2018-*]"; see ParseDseDateRange.
+var DateRange Codec = &dateRangeCodec{}
+
+type dateRangeCodec struct{}
+
+func (c *dateRangeCodec) DataType() datatype.DataType {
+	return datatype.DateRange
+}
+
+func (c *dateRangeCodec) Encode(source interface{}, version primitive.ProtocolVersion) (dest []byte, err error) {
+	var val DseDateRange
+	var wasNil bool
+	if val, wasNil, err = convertToDseDateRange(source); err == nil && !wasNil {
+		dest, err = writeDateRange(val)
+	}
+	if err != nil {
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
+	}
+	return
+}
+
+func (c *dateRangeCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
+	var val DseDateRange
+	if val, wasNull, err = readDateRange(source); err == nil {
+		err = convertFromDseDateRange(val, wasNull, dest)
+	}
+	if err != nil {
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
+	}
+	return
+}
+
+func convertToDseDateRange(source interface{}) (val DseDateRange, wasNil bool, err error) {
+	switch s := source.(type) {
+	case DseDateRange:
+		val = s
+	case *DseDateRange:
+		if wasNil = s == nil; !wasNil {
+			val = *s
+		}
+	case string:
+		val, err = ParseDseDateRange(s)
+	case *string:
+		if wasNil = s == nil; !wasNil {
+			val, err = ParseDseDateRange(*s)
+		}
+	case nil:
+		wasNil = true
+	default:
+		err = ErrConversionNotSupported
+	}
+	if err != nil {
+		err = errSourceConversionFailed(source, val, err)
+	}
+	return
+}
+
+func convertFromDseDateRange(val DseDateRange, wasNull bool, dest interface{}) (err error) {
+	switch d := dest.(type) {
+	case *interface{}:
+		if d == nil {
+			err = ErrNilDestination
+		} else if wasNull {
+			*d = nil
+		} else {
+			*d = val
+		}
+	case *DseDateRange:
+		if d == nil {
+			err = ErrNilDestination
+		} else if wasNull {
+			*d = DseDateRange{}
+		} else {
+			*d = val
+		}
+	case *string:
+		if d == nil {
+			err = ErrNilDestination
+		} else if wasNull {
+			*d = ""
+		} else {
+			*d = val.String()
+		}
+	default:
+		err = errDestinationInvalid(dest)
+	}
+	if err != nil {
+		err = errDestinationConversionFailed(val, dest, err)
+	}
+	return
+}
+
+// Implementation notes: DSE date ranges are encoded as one byte for the range type, followed by zero, one or two
+// bounds depending on the range type. Each bound is encoded as an 8-byte timestamp in milliseconds since the Epoch,
+// followed by one byte for the bound precision.
+
+const (
+	dateRangeSingle         = byte(0x00)
+	dateRangeClosed         = byte(0x01)
+	dateRangeOpenRangeHigh  = byte(0x02)
+	dateRangeOpenRangeLow   = byte(0x03)
+	dateRangeBothOpen       = byte(0x04)
+	dateRangeSingleDateOpen = byte(0x05)
+)
+
+const dateRangeBoundLength = 9
+
+func writeDateRange(val DseDateRange) (dest []byte, err error) {
+	var bounds []DseDateRangeBound
+	if val.Upper == nil {
+		if val.Lower.Unbounded {
+			dest = []byte{dateRangeSingleDateOpen}
+		} else {
+			dest = []byte{dateRangeSingle}
+			bounds = []DseDateRangeBound{val.Lower}
+		}
+	} else if val.Lower.Unbounded && val.Upper.Unbounded {
+		dest = []byte{dateRangeBothOpen}
+	} else if val.Lower.Unbounded {
+		dest = []byte{dateRangeOpenRangeLow}
+		bounds = []DseDateRangeBound{*val.Upper}
+	} else if val.Upper.Unbounded {
+		dest = []byte{dateRangeOpenRangeHigh}
+		bounds = []DseDateRangeBound{val.Lower}
+	} else {
+		dest = []byte{dateRangeClosed}
+		bounds = []DseDateRangeBound{val.Lower, *val.Upper}
+	}
+	for _, bound := range bounds {
+		if bound.Precision > DateRangePrecisionMillisecond {
+			return nil, errInvalidDateRangePrecision(byte(bound.Precision))
+		}
+		millis, err := ConvertTimeToEpochMillis(bound.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		encoded := make([]byte, dateRangeBoundLength)
+		binary.BigEndian.PutUint64(encoded, uint64(millis))
+		encoded[8] = byte(bound.Precision)
+		dest = append(dest, encoded...)
+	}
+	return dest, nil
+}
+
+func readDateRange(source []byte) (val DseDateRange, wasNull bool, err error) {
+	length := len(source)
+	if wasNull = length == 0; !wasNull {
+		var expected int
+		switch source[0] {
+		case dateRangeSingle, dateRangeOpenRangeHigh, dateRangeOpenRangeLow:
+			expected = 1 + dateRangeBoundLength
+		case dateRangeClosed:
+			expected = 1 + 2*dateRangeBoundLength
+		case dateRangeBothOpen, dateRangeSingleDateOpen:
+			expected = 1
+		default:
+			err = fmt.Errorf("invalid date range type: %d", source[0])
+		}
+		if err == nil && length != expected {
+			err = errWrongFixedLength(expected, length)
+		}
+		if err == nil {
+			switch source[0] {
+			case dateRangeSingle:
+				val.Lower, err = readDateRangeBound(source[1:])
+			case dateRangeClosed:
+				var upper DseDateRangeBound
+				if val.Lower, err = readDateRangeBound(source[1:]); err == nil {
+					upper, err = readDateRangeBound(source[1+dateRangeBoundLength:])
+					val.Upper = &upper
+				}
+			case dateRangeOpenRangeHigh:
+				val.Lower, err = readDateRangeBound(source[1:])
+				val.Upper = &DseDateRangeBound{Unbounded: true}
+			case dateRangeOpenRangeLow:
+				var upper DseDateRangeBound
+				upper, err = readDateRangeBound(source[1:])
+				val.Lower = DseDateRangeUnbounded
+				val.Upper = &upper
+			case dateRangeBothOpen:
+				val.Lower = DseDateRangeUnbounded
+				val.Upper = &DseDateRangeBound{Unbounded: true}
+			case dateRangeSingleDateOpen:
+				val.Lower = DseDateRangeUnbounded
+			}
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("cannot read date range: %w", err)
+	}
+	return
+}
+
+func readDateRangeBound(source []byte) (bound DseDateRangeBound, err error) {
+	millis := int64(binary.BigEndian.Uint64(source))
+	precision := source[8]
+	if DateRangePrecision(precision) > DateRangePrecisionMillisecond {
+		return bound, errInvalidDateRangePrecision(precision)
+	}
+	return DseDateRangeBound{Timestamp: ConvertEpochMillisToTime(millis), Precision: DateRangePrecision(precision)}, nil
+}
+
+func errInvalidDateRangePrecision(precision byte) error {
+	return fmt.Errorf("invalid date range precision: %d", precision)
+}
+
+// Literal conversions
+
+// String returns the literal representation of this bound: either "*" if the bound is unbounded, or its timestamp
+// in UTC, truncated to the bound's precision, e.g. "2017-01" for a bound with month precision. Bounds with hour
+// precision or finer are suffixed with "Z".
+func (b DseDateRangeBound) String() string {
+	if b.Unbounded {
+		return "*"
+	}
+	t := b.Timestamp.UTC()
+	sb := &strings.Builder{}
+	year := t.Year()
+	if year < 0 {
+		sb.WriteString(fmt.Sprintf("-%04d", -year))
+	} else {
+		sb.WriteString(fmt.Sprintf("%04d", year))
+	}
+	if b.Precision >= DateRangePrecisionMonth {
+		sb.WriteString(fmt.Sprintf("-%02d", int(t.Month())))
+	}
+	if b.Precision >= DateRangePrecisionDay {
+		sb.WriteString(fmt.Sprintf("-%02d", t.Day()))
+	}
+	if b.Precision >= DateRangePrecisionHour {
+		sb.WriteString(fmt.Sprintf("T%02d", t.Hour()))
+	}
+	if b.Precision >= DateRangePrecisionMinute {
+		sb.WriteString(fmt.Sprintf(":%02d", t.Minute()))
+	}
+	if b.Precision >= DateRangePrecisionSecond {
+		sb.WriteString(fmt.Sprintf(":%02d", t.Second()))
+	}
+	if b.Precision >= DateRangePrecisionMillisecond {
+		sb.WriteString(fmt.Sprintf(".%03d", t.Nanosecond()/int(time.Millisecond)))
+	}
+	if b.Precision >= DateRangePrecisionHour {
+		sb.WriteString("Z")
+	}
+	return sb.String()
+}
+
+// String returns the literal representation of this date range, e.g. "2017-01-15" for a single-bound range, or
+// "[2017-01 TO *]" for a range with both bounds.
+func (r DseDateRange) String() string {
+	if r.Upper == nil {
+		return r.Lower.String()
+	}
+	return "[" + r.Lower.String() + " TO " + r.Upper.String() + "]"
+}
+
+var dateRangeBoundPattern = regexp.MustCompile(
+	`^(-?\d{4,})(?:-(\d{2})(?:-(\d{2})(?:T(\d{2})(?::(\d{2})(?::(\d{2})(?:\.(\d{1,3}))?)?)?)?)?)?(?:[-T:]\*)?Z?$`,
+)
+
+// ParseDseDateRange parses the given date range literal. The literal is either a single bound, e.g. "2017-01-15", or
+// two bounds separated by " TO " and enclosed in square brackets, e.g. "[2017-01 TO 2018-02-15T10:30Z]". Each bound
+// is either "*" for an unbounded bound, or a UTC timestamp in ISO-8601 format, possibly truncated: the precision of
+// the bound is inferred from its last present field, e.g. month precision for "2017-01". A truncated timestamp may be
+// followed by a wildcard suffix, e.g. "2018-*", which is equivalent to "2018". Lower bounds (and single bounds) are
+// rounded down to the beginning of the period denoted by their precision; upper bounds are rounded up to the end of
+// that period: e.g. "[2017 TO 2018]" ranges from 2017-01-01T00:00:00.000Z to 2018-12-31T23:59:59.999Z.
+func ParseDseDateRange(literal string) (val DseDateRange, err error) {
+	s := strings.TrimSpace(literal)
+	if strings.HasPrefix(s, "[") {
+		if !strings.HasSuffix(s, "]") {
+			err = errors.New("missing closing bracket")
+		} else if parts := strings.Fields(s[1 : len(s)-1]); len(parts) != 3 || strings.ToUpper(parts[1]) != "TO" {
+			err = errors.New("expecting '[<lower> TO <upper>]'")
+		} else if val.Lower, err = parseDateRangeBound(parts[0], false); err == nil {
+			var upper DseDateRangeBound
+			if upper, err = parseDateRangeBound(parts[2], true); err == nil {
+				val.Upper = &upper
+			}
+		}
+	} else {
+		val.Lower, err = parseDateRangeBound(s, false)
+	}
+	if err != nil {
+		return DseDateRange{}, errCannotParseString(literal, err)
+	}
+	return val, nil
+}
+
+func parseDateRangeBound(s string, upper bool) (DseDateRangeBound, error) {
+	if s == "*" {
+		return DseDateRangeUnbounded, nil
+	}
+	matches := dateRangeBoundPattern.FindStringSubmatch(s)
+	if matches == nil {
+		return DseDateRangeBound{}, fmt.Errorf("invalid date range bound: '%s'", s)
+	}
+	fields := []int{0, 1, 1, 0, 0, 0, 0}
+	precision := DateRangePrecisionYear
+	for i, match := range matches[1:] {
+		if match == "" {
+			break
+		}
+		if i == 6 {
+			// milliseconds: right-pad to 3 digits
+			match += strings.Repeat("0", 3-len(match))
+		}
+		fields[i], _ = strconv.Atoi(match)
+		precision = DateRangePrecision(i)
+	}
+	t := time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], fields[6]*int(time.Millisecond), time.UTC)
+	if t.Month() != time.Month(fields[1]) || t.Day() != fields[2] || t.Hour() != fields[3] || t.Minute() != fields[4] || t.Second() != fields[5] {
+		return DseDateRangeBound{}, fmt.Errorf("invalid date range bound: '%s'", s)
+	}
+	if upper {
+		t = roundUpDateRangeBound(t, precision)
+	}
+	return DseDateRangeBound{Timestamp: t, Precision: precision}, nil
+}
+
+// roundUpDateRangeBound returns the last millisecond of the period starting at t and denoted by the given precision.
+func roundUpDateRangeBound(t time.Time, precision DateRangePrecision) time.Time {
+	switch precision {
+	case DateRangePrecisionYear:
+		t = t.AddDate(1, 0, 0)
+	case DateRangePrecisionMonth:
+		t = t.AddDate(0, 1, 0)
+	case DateRangePrecisionDay:
+		t = t.AddDate(0, 0, 1)
+	case DateRangePrecisionHour:
+		t = t.Add(time.Hour)
+	case DateRangePrecisionMinute:
+		t = t.Add(time.Minute)
+	case DateRangePrecisionSecond:
+		t = t.Add(time.Second)
+	default:
+		return t
+	}
+	return t.Add(-time.Millisecond)
+}
@@ -0,0 +1,228 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+var (
+	// 2017-01-01T00:00:00Z = 1483228800000 ms = 0x00000159_57536400
+	dateRangeLower = DseDateRangeBound{Timestamp: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), Precision: DateRangePrecisionMonth}
+	// 2018-12-31T23:59:59.999Z = 1546300799999 ms = 0x00000168_06b5bbff
+	dateRangeUpper = DseDateRangeBound{Timestamp: time.Date(2018, 12, 31, 23, 59, 59, 999_000_000, time.UTC), Precision: DateRangePrecisionYear}
+
+	dateRangeClosedValue     = DseDateRange{Lower: dateRangeLower, Upper: &dateRangeUpper}
+	dateRangeSingleValue     = DseDateRange{Lower: dateRangeLower}
+	dateRangeOpenHighValue   = DseDateRange{Lower: dateRangeLower, Upper: &DseDateRangeUnbounded}
+	dateRangeOpenLowValue    = DseDateRange{Lower: DseDateRangeUnbounded, Upper: &dateRangeUpper}
+	dateRangeBothOpenValue   = DseDateRange{Lower: DseDateRangeUnbounded, Upper: &DseDateRangeUnbounded}
+	dateRangeSingleOpenValue = DseDateRange{Lower: DseDateRangeUnbounded}
+)
+
+var (
+	dateRangeLowerBytes      = []byte{0, 0, 0x01, 0x59, 0x57, 0x53, 0x64, 0, 1}
+	dateRangeUpperBytes      = []byte{0, 0, 0x01, 0x68, 0x06, 0xb5, 0xbb, 0xff, 0}
+	dateRangeClosedBytes     = append(append([]byte{1}, dateRangeLowerBytes...), dateRangeUpperBytes...)
+	dateRangeSingleBytes     = append([]byte{0}, dateRangeLowerBytes...)
+	dateRangeOpenHighBytes   = append([]byte{2}, dateRangeLowerBytes...)
+	dateRangeOpenLowBytes    = append([]byte{3}, dateRangeUpperBytes...)
+	dateRangeBothOpenBytes   = []byte{4}
+	dateRangeSingleOpenBytes = []byte{5}
+)
+
+func Test_dateRangeCodec_DataType(t *testing.T) {
+	assert.Equal(t, datatype.DateRange, DateRange.DataType())
+	codec, err := NewCodec(datatype.NewCustom("org.apache.cassandra.db.marshal.DateRangeType"))
+	require.NoError(t, err)
+	assert.Equal(t, DateRange, codec)
+	goType, err := PreferredGoType(datatype.DateRange)
+	require.NoError(t, err)
+	assert.Equal(t, typeOfDseDateRange, goType)
+}
+
+func Test_dateRangeCodec_Encode(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			tests := []struct {
+				name     string
+				source   interface{}
+				expected []byte
+				err      string
+			}{
+				{"nil", nil, nil, ""},
+				{"nil pointer", (*DseDateRange)(nil), nil, ""},
+				{"closed", dateRangeClosedValue, dateRangeClosedBytes, ""},
+				{"closed pointer", &dateRangeClosedValue, dateRangeClosedBytes, ""},
+				{"single", dateRangeSingleValue, dateRangeSingleBytes, ""},
+				{"open high", dateRangeOpenHighValue, dateRangeOpenHighBytes, ""},
+				{"open low", dateRangeOpenLowValue, dateRangeOpenLowBytes, ""},
+				{"both open", dateRangeBothOpenValue, dateRangeBothOpenBytes, ""},
+				{"single open", dateRangeSingleOpenValue, dateRangeSingleOpenBytes, ""},
+				{"literal", "[2017-01 TO 2018-*]", dateRangeClosedBytes, ""},
+				{"literal pointer", stringPtr("[* TO *]"), dateRangeBothOpenBytes, ""},
+				{"invalid literal", "2017-13", nil, "cannot parse '2017-13': invalid date range bound: '2017-13'"},
+				{"invalid precision", DseDateRange{Lower: DseDateRangeBound{Precision: 7}}, nil, "invalid date range precision: 7"},
+				{"wrong type", 123, nil, "cannot convert from int to datacodec.DseDateRange: conversion not supported"},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					actual, err := DateRange.Encode(tt.source, version)
+					assert.Equal(t, tt.expected, actual)
+					if tt.err == "" {
+						assert.NoError(t, err)
+					} else {
+						assert.Contains(t, err.Error(), tt.err)
+					}
+				})
+			}
+		})
+	}
+}
+
+func Test_dateRangeCodec_Decode(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			tests := []struct {
+				name     string
+				source   []byte
+				expected DseDateRange
+				wasNull  bool
+				err      string
+			}{
+				{"null", nil, DseDateRange{}, true, ""},
+				{"closed", dateRangeClosedBytes, dateRangeClosedValue, false, ""},
+				{"single", dateRangeSingleBytes, dateRangeSingleValue, false, ""},
+				{"open high", dateRangeOpenHighBytes, dateRangeOpenHighValue, false, ""},
+				{"open low", dateRangeOpenLowBytes, dateRangeOpenLowValue, false, ""},
+				{"both open", dateRangeBothOpenBytes, dateRangeBothOpenValue, false, ""},
+				{"single open", dateRangeSingleOpenBytes, dateRangeSingleOpenValue, false, ""},
+				{"invalid type", []byte{6}, DseDateRange{}, false, "cannot read date range: invalid date range type: 6"},
+				{"wrong length", dateRangeClosedBytes[:10], DseDateRange{}, false, "cannot read date range: expected 19 bytes but got: 10"},
+				{"invalid precision", []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 9}, DseDateRange{}, false, "cannot read date range: invalid date range precision: 9"},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					var dest DseDateRange
+					wasNull, err := DateRange.Decode(tt.source, &dest, version)
+					assert.Equal(t, tt.wasNull, wasNull)
+					if tt.err == "" {
+						assert.NoError(t, err)
+						assert.Equal(t, tt.expected, dest)
+					} else {
+						assert.Contains(t, err.Error(), tt.err)
+					}
+				})
+			}
+			t.Run("string", func(t *testing.T) {
+				var dest string
+				wasNull, err := DateRange.Decode(dateRangeClosedBytes, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, "[2017-01 TO 2018]", dest)
+			})
+			t.Run("interface", func(t *testing.T) {
+				var dest interface{}
+				wasNull, err := DateRange.Decode(dateRangeOpenLowBytes, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, dateRangeOpenLowValue, dest)
+			})
+			t.Run("wrong destination", func(t *testing.T) {
+				var dest int
+				_, err := DateRange.Decode(dateRangeClosedBytes, &dest, version)
+				assert.Contains(t, err.Error(), "cannot convert from datacodec.DseDateRange to *int: conversion not supported")
+			})
+		})
+	}
+}
+
+func TestParseDseDateRange(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, min, sec, millis int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, millis*int(time.Millisecond), time.UTC)
+	}
+	bound := func(t time.Time, precision DateRangePrecision) *DseDateRangeBound {
+		return &DseDateRangeBound{Timestamp: t, Precision: precision}
+	}
+	tests := []struct {
+		name      string
+		literal   string
+		expected  DseDateRange
+		formatted string
+	}{
+		{"year", "2017", DseDateRange{Lower: *bound(utc(2017, 1, 1, 0, 0, 0, 0), DateRangePrecisionYear)}, "2017"},
+		{"month", "2017-02", DseDateRange{Lower: *bound(utc(2017, 2, 1, 0, 0, 0, 0), DateRangePrecisionMonth)}, "2017-02"},
+		{"day", "2017-02-15", DseDateRange{Lower: *bound(utc(2017, 2, 15, 0, 0, 0, 0), DateRangePrecisionDay)}, "2017-02-15"},
+		{"hour", "2017-02-15T10", DseDateRange{Lower: *bound(utc(2017, 2, 15, 10, 0, 0, 0), DateRangePrecisionHour)}, "2017-02-15T10Z"},
+		{"minute", "2017-02-15T10:30Z", DseDateRange{Lower: *bound(utc(2017, 2, 15, 10, 30, 0, 0), DateRangePrecisionMinute)}, "2017-02-15T10:30Z"},
+		{"second", "2017-02-15T10:30:45Z", DseDateRange{Lower: *bound(utc(2017, 2, 15, 10, 30, 45, 0), DateRangePrecisionSecond)}, "2017-02-15T10:30:45Z"},
+		{"millisecond", "2017-02-15T10:30:45.1Z", DseDateRange{Lower: *bound(utc(2017, 2, 15, 10, 30, 45, 100), DateRangePrecisionMillisecond)}, "2017-02-15T10:30:45.100Z"},
+		{"wildcard", "2017-*", DseDateRange{Lower: *bound(utc(2017, 1, 1, 0, 0, 0, 0), DateRangePrecisionYear)}, "2017"},
+		{"single open", "*", DseDateRange{Lower: DseDateRangeUnbounded}, "*"},
+		{"closed", "[2017-01 TO 2018-*]", dateRangeClosedValue, "[2017-01 TO 2018]"},
+		{
+			"closed upper rounded up",
+			"[2017-01-15 TO 2017-02]",
+			DseDateRange{Lower: *bound(utc(2017, 1, 15, 0, 0, 0, 0), DateRangePrecisionDay), Upper: bound(utc(2017, 2, 28, 23, 59, 59, 999), DateRangePrecisionMonth)},
+			"[2017-01-15 TO 2017-02]",
+		},
+		{
+			"closed upper minute",
+			"[2017-01-15T10:30 TO 2017-01-15T10:45]",
+			DseDateRange{Lower: *bound(utc(2017, 1, 15, 10, 30, 0, 0), DateRangePrecisionMinute), Upper: bound(utc(2017, 1, 15, 10, 45, 59, 999), DateRangePrecisionMinute)},
+			"[2017-01-15T10:30Z TO 2017-01-15T10:45Z]",
+		},
+		{"open high", "[2017-01 TO *]", dateRangeOpenHighValue, "[2017-01 TO *]"},
+		{"open low", "[* TO 2018]", dateRangeOpenLowValue, "[* TO 2018]"},
+		{"both open", "[* TO *]", dateRangeBothOpenValue, "[* TO *]"},
+		{"lowercase to", "[*  to  *]", dateRangeBothOpenValue, "[* TO *]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseDseDateRange(tt.literal)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.formatted, actual.String())
+			reparsed, err := ParseDseDateRange(tt.formatted)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, reparsed)
+		})
+	}
+	errorTests := []struct {
+		name    string
+		literal string
+		err     string
+	}{
+		{"empty", "", "cannot parse '': invalid date range bound: ''"},
+		{"invalid month", "2017-13", "cannot parse '2017-13': invalid date range bound: '2017-13'"},
+		{"invalid day", "2017-02-30", "cannot parse '2017-02-30': invalid date range bound: '2017-02-30'"},
+		{"missing bracket", "[2017 TO 2018", "cannot parse '[2017 TO 2018': missing closing bracket"},
+		{"missing TO", "[2017 2018]", "cannot parse '[2017 2018]': expecting '[<lower> TO <upper>]'"},
+		{"garbage", "[2017 TO foo]", "cannot parse '[2017 TO foo]': invalid date range bound: 'foo'"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDseDateRange(tt.literal)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
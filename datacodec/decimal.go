@@ -53,7 +53,7 @@ func (c *decimalCodec) Encode(source interface{}, version primitive.ProtocolVers
 		dest = writeDecimal(val)
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
@@ -64,7 +64,7 @@ func (c *decimalCodec) Decode(source []byte, dest interface{}, version primitive
 		err = convertFromDecimal(val, wasNull, dest)
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
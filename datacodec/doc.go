@@ -44,6 +44,14 @@
 //  - NewMap
 //  - NewTuple
 //  - NewUserDefined
+//  - NewVector
+//
+// Registering custom codecs
+//
+// NewCodec and the constructor functions above use the DefaultRegistry. Callers can register their own codecs with a
+// Registry, either for a CQL type, e.g. to provide a codec for a custom type class name, or for a CQL type and a Go
+// type, e.g. to map a custom Go type to uuid. Registered codecs are also used for the elements of lists, sets, maps,
+// tuples, user-defined types and vectors created by the same registry.
 //
 // Using a codec
 //
@@ -62,11 +70,14 @@
 //  date                  | time.Time, *time.Time                           | time at start of day (in UTC); clock part set to zero
 //                        | int[64-8], *int[64-8], uint[64-8], *uint[64-8]  | days since Unix epoch
 //                        | string, *string                                 | parsed according to layout, default is "2006-01-02"
+//  daterange (DSE)       | DseDateRange, *DseDateRange                     |
+//                        | string, *string                                 | formatted and parsed as literal, e.g. "[2017-01 TO 2018-*]"
 //  decimal               | CqlDecimal, *CqlDecimal                         |
 //  double                | float64, *float64                               |
 //                        | float32, *float32                               |
 //                        | *big.Float                                      |
 //  duration              | CqlDuration, *CqlDuration                       |
+//                        | string, *string                                 | formatted and parsed as duration literal, e.g. "1h30m" or "PT1H30M"
 //  float                 | float32, *float32                               |
 //                        | float64, *float64                               |
 //  inet                  | net.IP, *net.IP                                 |
@@ -78,6 +89,12 @@
 //  list, set             | any compatible slice                            | element types must match
 //                        | any compatible array                            | element types must match
 //  map                   | any compatible map                              | key and value types must match
+//  point (DSE)           | DsePoint, *DsePoint                             |
+//                        | string, *string                                 | formatted and parsed as WKT, e.g. "POINT (1 2)"
+//  linestring (DSE)      | DseLineString, *DseLineString                   |
+//                        | string, *string                                 | formatted and parsed as WKT, e.g. "LINESTRING (1 2, 3 4)"
+//  polygon (DSE)         | DsePolygon, *DsePolygon                         |
+//                        | string, *string                                 | formatted and parsed as WKT, e.g. "POLYGON ((0 0, 1 0, 0 1, 0 0))"
 //  smallint              | int16, *int16                                   |
 //                        | int[64-8], *int[64-8], uint[64-8], *uint[64-8]  |
 //                        | string, *string                                 | formatted and parsed as base 10 number
@@ -93,7 +110,7 @@
 //                        | string, *string                                 | formatted and parsed as base 10 number
 //  tuple                 | any compatible slice                            | slice size and elements must match
 //                        | any compatible array                            | array size and elements must match
-//                        | any compatible struct                           | fields must be exported and are marshaled in order of declaration
+//                        | any compatible struct                           | fields must be exported and are marshaled in order of declaration (2)
 //  user-defined type     | any compatible map                              | map key must be string
 //                        | any compatible struct                           | fields must be exported; field names match case-insensitively (2)
 //                        | any compatible slice                            | slice size and elements must match
@@ -101,27 +118,49 @@
 //  uuid, timeuuid        | primitive.UUID, *primitive.UUID                 |
 //                        | [16]byte, []byte                                | raw UUID bytes, length must be 16 bytes
 //                        | string, *string                                 | hex representation, parsed with primitive.ParseUuid
+//                        | time.Time, *time.Time                           | timeuuid only; encoded as primitive.MinTimeUuid, decoded from UUID timestamp
 //  varchar, text, ascii  | string, *string                                 |
 //                        | []byte, *[]byte, []rune, *[]rune                |
 //  varint                | big.Int, *big.Int                               |
 //                        | int[64-8], *int[64-8], uint[64-8], *uint[64-8]  |
 //                        | string, *string                                 | formatted and parsed as base 10 number
+//  vector                | any compatible slice                            | slice size must match dimensions; no nil elements
+//                        | any compatible array                            | array size must match dimensions; no nil elements
 //
 // (1) types listed on the first line are the preferred types for each CQL type. Note that non-pointer types are only
 // accepted when encoding, never when decoding.
-// (2) when mapping structs to user-defined types, the "cassandra" field tag can be used to override the corresponding
-// field name.
+// (2) struct fields can be annotated with the "cql" field tag. When mapping structs to user-defined types, the tag
+// value overrides the corresponding field name, e.g. `cql:"field_name"`; the name must then match exactly. The tag
+// value "-" excludes the field from the mapping, and the "omitempty" option, e.g. `cql:"field_name,omitempty"` or
+// `cql:",omitempty"`, causes zero values to be encoded as CQL NULLs. Fields of embedded structs without a tag are
+// flattened when mapping to user-defined types, as if they were declared in the outer struct, following the rules of
+// encoding/json for conflicting field names; when mapping to tuples, embedded structs are mapped to a single tuple
+// element, and unexported fields count as tuple elements, but cannot be accessed. The legacy "cassandra" field tag is
+// also accepted, but is ignored if a "cql" tag is present.
 //
 // In addition to the accepted types above, all codecs also accept interface{} when encoding and *interface{} when
 // decoding. When encoding an interface{} value, the actual runtime value stored in the variable must be of an
 // accepted type. When decoding to *interface{}, the codec will use the preferred type to decode, then store its value
 // in the target variable; if the decoded value was NULL, the target will be set to nil.
 //
+// Codecs for simple CQL types (that is, all types except collections, tuples, user-defined types and vectors) also
+// fall back to standard Go interfaces when the source or destination type is not accepted: when encoding, to
+// driver.Valuer, encoding.TextMarshaler and, for blob and custom types only, encoding.BinaryMarshaler; when decoding,
+// to sql.Scanner, encoding.TextUnmarshaler and, for blob and custom types only, encoding.BinaryUnmarshaler. Text
+// marshalers exchange strings with the codec, and thus can be used with any CQL type accepting strings, e.g. varchar
+// or uuid; scanners receive driver values, e.g. int64 for CQL int and []byte for CQL uuid.
+//
 // Encoding data
 //
 // Sources can be passed by value or by reference, unless specified otherwise in the table above. Nils are encoded as
 // CQL NULLs; encoding such a value is generally a no-op and returns a nil []byte.
 //
+// When encoding large amounts of data, the AppendEncode and AppendValue functions can be used to encode values into a
+// caller-provided buffer instead of allocating a new slice for each value. Codecs for simple CQL types, lists, sets
+// and maps implement the AppendEncoder interface; lists and sets of the preferred Go type of their elements, e.g.
+// []int32 for list<int>, and maps with string keys and values of the preferred Go type, e.g. map[string]int32 for
+// map<varchar,int>, are also encoded without reflection.
+//
 // Decoding data
 //
 // Destination values must be passed by reference, see examples below. This is also valid for slices and maps, and is
@@ -161,4 +200,15 @@
 //  } else {
 // 	  fmt.Println("CQL value was:", value)
 //  }
+//
+// CQL literals and JSON
+//
+// The FormatLiteral function formats an encoded value of any CQL type as a CQL literal, e.g. 'it''s' for a varchar,
+// 0xcafe for a blob, or {'a': [1, 2]} for a map<varchar,list<int>>; this is useful e.g. for logging, or to generate
+// CQL statements from captured values. Conversely, the ParseLiteral function parses a CQL literal into an encoded
+// value of the given CQL type.
+//
+// Similarly, the MarshalJSON and UnmarshalJSON functions convert encoded values to and from JSON, using the same format
+// as Cassandra's SELECT JSON and INSERT JSON statements; the RowsToJSON function converts all the rows of a
+// message.RowsResult to JSON objects, as a SELECT JSON statement would return them.
 package datacodec
@@ -40,18 +40,28 @@ func (c *doubleCodec) Encode(source interface{}, version primitive.ProtocolVersi
 		dest = writeFloat64(val)
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *doubleCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	var val float64
+	if val, wasNil, err = convertToFloat64(source); err != nil {
+		return appendEncoded(c, dest, source, version)
+	} else if !wasNil {
+		dest = appendFloat64(dest, val)
+	}
+	return dest, wasNil, nil
+}
+
 func (c *doubleCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	var val float64
 	if val, wasNull, err = readFloat64(source); err == nil {
 		err = convertFromFloat64(val, wasNull, dest)
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -131,6 +141,10 @@ func convertFromFloat64(val float64, wasNull bool, dest interface{}) (err error)
 
 const lengthOfDouble = primitive.LengthOfLong
 
+func appendFloat64(dest []byte, val float64) []byte {
+	return appendInt64(dest, int64(math.Float64bits(val)))
+}
+
 func writeFloat64(val float64) (dest []byte) {
 	dest = make([]byte, lengthOfDouble)
 	binary.BigEndian.PutUint64(dest, math.Float64bits(val))
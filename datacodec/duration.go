@@ -16,8 +16,12 @@ package datacodec
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/datastax/go-cassandra-native-protocol/datatype"
@@ -35,7 +39,7 @@ type CqlDuration struct {
 
 // Duration is a codec for the CQL duration type, introduced in protocol v5. There is no built-in representation of
 // arbitrary-precision duration values in Go's standard library. This is why this codec can only encode from and decode
-// to CqlDuration.
+// to CqlDuration, or to strings, using the formats accepted by ParseCqlDuration and returned by CqlDuration.String.
 var Duration Codec = &durationCodec{}
 
 type durationCodec struct {
@@ -56,7 +60,7 @@ func (c *durationCodec) Encode(source interface{}, version primitive.ProtocolVer
 		}
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
@@ -72,7 +76,7 @@ func (c *durationCodec) Decode(source []byte, dest interface{}, version primitiv
 		}
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -85,6 +89,12 @@ func convertToDuration(source interface{}) (val CqlDuration, wasNil bool, err er
 		if wasNil = s == nil; !wasNil {
 			val = *s
 		}
+	case string:
+		val, err = ParseCqlDuration(s)
+	case *string:
+		if wasNil = s == nil; !wasNil {
+			val, err = ParseCqlDuration(*s)
+		}
 	case nil:
 		wasNil = true
 	default:
@@ -114,6 +124,14 @@ func convertFromDuration(val CqlDuration, wasNull bool, dest interface{}) (err e
 		} else {
 			*d = val
 		}
+	case *string:
+		if d == nil {
+			err = ErrNilDestination
+		} else if wasNull {
+			*d = ""
+		} else {
+			*d, err = val.formatUnits()
+		}
 	default:
 		err = errDestinationInvalid(dest)
 	}
@@ -176,3 +194,345 @@ func readDuration(source []byte) (val CqlDuration, wasNull bool, err error) {
 	}
 	return
 }
+
+// ParseCqlDuration parses the given duration literal. Three syntaxes are accepted, as in CQL:
+//
+//   - units, e.g. 1y2mo3w4d5h6m7s8ms9us10ns: each quantity is followed by its unit, case-insensitive; units must appear
+//     in decreasing order of magnitude, at most once each; µs is accepted as an alias for us;
+//   - ISO 8601 format with designators, e.g. P1Y2M3DT4H5M6S, or P3W for weeks; seconds can have a fractional part of
+//     up to 9 digits, e.g. PT1.5S;
+//   - ISO 8601 alternative format, e.g. P0001-02-03T04:05:06.
+//
+// All syntaxes accept a leading minus sign to denote a negative duration. An error is returned if the number of months
+// or days does not fit in 32 bits, or if the number of nanoseconds does not fit in 64 bits.
+func ParseCqlDuration(s string) (CqlDuration, error) {
+	literal := s
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+	b := &durationBuilder{}
+	var err error
+	switch {
+	case s == "":
+		err = errors.New("empty duration")
+	case s[0] != 'P':
+		err = b.parseUnits(s)
+	case strings.HasSuffix(s, "W"):
+		err = b.parseISO8601Weeks(s)
+	case strings.ContainsRune(s, ':'):
+		err = b.parseISO8601Alternative(s)
+	default:
+		err = b.parseISO8601(s)
+	}
+	if err != nil {
+		return CqlDuration{}, fmt.Errorf("cannot parse duration %q: %w", literal, err)
+	}
+	d := CqlDuration{Months: int32(b.months), Days: int32(b.days), Nanos: time.Duration(b.nanos)}
+	if negative {
+		d = CqlDuration{Months: -d.Months, Days: -d.Days, Nanos: -d.Nanos}
+	}
+	return d, nil
+}
+
+// String returns the duration formatted with units, e.g. 1y2mo3d4h5m6s7ms8us9ns, or -1d for negative durations; the
+// zero duration is formatted as 0s. This is the canonical format used by Cassandra. If the duration is invalid, that
+// is, if its components do not all have the same sign, a description of the invalid duration is returned instead.
+func (d CqlDuration) String() string {
+	s, err := d.formatUnits()
+	if err != nil {
+		return fmt.Sprintf("invalid duration (%d months, %d days, %d nanoseconds)", d.Months, d.Days, d.Nanos)
+	}
+	return s
+}
+
+// ISO8601 returns the duration formatted in ISO 8601 format with designators, e.g. P1Y2M3DT4H5M6.000000007S; the zero
+// duration is formatted as PT0S. An error is returned if the components of the duration do not all have the same sign.
+func (d CqlDuration) ISO8601() (string, error) {
+	negative, months, days, nanos, err := d.abs()
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if negative {
+		sb.WriteByte('-')
+	}
+	sb.WriteByte('P')
+	writeDurationComponent(&sb, months/12, "Y")
+	writeDurationComponent(&sb, months%12, "M")
+	writeDurationComponent(&sb, days, "D")
+	if nanos != 0 || months == 0 && days == 0 {
+		sb.WriteByte('T')
+		writeDurationComponent(&sb, nanos/uint64(time.Hour), "H")
+		writeDurationComponent(&sb, nanos/uint64(time.Minute)%60, "M")
+		seconds, fraction := nanos/uint64(time.Second)%60, nanos%uint64(time.Second)
+		if seconds != 0 || fraction != 0 || nanos == 0 {
+			sb.WriteString(strconv.FormatUint(seconds, 10))
+			if fraction != 0 {
+				sb.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", fraction), "0"))
+			}
+			sb.WriteByte('S')
+		}
+	}
+	return sb.String(), nil
+}
+
+// ISO8601Alternative returns the duration formatted in ISO 8601 alternative format, e.g. P0001-02-03T04:05:06. An
+// error is returned if the components of the duration do not all have the same sign, or if the duration cannot be
+// represented in this format, that is, if it has more than 9999 years, 99 days or 99 hours, or a fractional number of
+// seconds.
+func (d CqlDuration) ISO8601Alternative() (string, error) {
+	negative, months, days, nanos, err := d.abs()
+	if err != nil {
+		return "", err
+	}
+	hours := nanos / uint64(time.Hour)
+	if months/12 > 9999 || days > 99 || hours > 99 || nanos%uint64(time.Second) != 0 {
+		return "", fmt.Errorf("duration %v cannot be represented in ISO 8601 alternative format", d)
+	}
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+	return fmt.Sprintf(
+		"%sP%04d-%02d-%02dT%02d:%02d:%02d",
+		sign,
+		months/12,
+		months%12,
+		days,
+		hours,
+		nanos/uint64(time.Minute)%60,
+		nanos/uint64(time.Second)%60,
+	), nil
+}
+
+func (d CqlDuration) formatUnits() (string, error) {
+	negative, months, days, nanos, err := d.abs()
+	if err != nil {
+		return "", err
+	} else if months == 0 && days == 0 && nanos == 0 {
+		return "0s", nil
+	}
+	var sb strings.Builder
+	if negative {
+		sb.WriteByte('-')
+	}
+	writeDurationComponent(&sb, months/12, "y")
+	writeDurationComponent(&sb, months%12, "mo")
+	writeDurationComponent(&sb, days, "d")
+	for _, unit := range durationUnits[4:] {
+		writeDurationComponent(&sb, nanos/uint64(unit.nanos), unit.symbol)
+		nanos %= uint64(unit.nanos)
+	}
+	return sb.String(), nil
+}
+
+// abs returns the absolute values of the duration components, and whether the duration is negative. An error is
+// returned if the components do not all have the same sign.
+func (d CqlDuration) abs() (negative bool, months, days, nanos uint64, err error) {
+	positive := d.Months > 0 || d.Days > 0 || d.Nanos > 0
+	negative = d.Months < 0 || d.Days < 0 || d.Nanos < 0
+	if positive && negative {
+		return false, 0, 0, 0, fmt.Errorf(
+			"invalid duration: months (%d), days (%d) and nanoseconds (%d) must have the same sign",
+			d.Months, d.Days, d.Nanos,
+		)
+	} else if negative {
+		// note: negating math.MinInt64 overflows, but converting the result to uint64 yields the right value
+		return true, uint64(-int64(d.Months)), uint64(-int64(d.Days)), uint64(-int64(d.Nanos)), nil
+	}
+	return false, uint64(d.Months), uint64(d.Days), uint64(d.Nanos), nil
+}
+
+func writeDurationComponent(sb *strings.Builder, value uint64, designator string) {
+	if value != 0 {
+		sb.WriteString(strconv.FormatUint(value, 10))
+		sb.WriteString(designator)
+	}
+}
+
+// durationUnits are the units accepted by ParseCqlDuration, in decreasing order of magnitude.
+var durationUnits = []struct {
+	symbol string
+	months int64
+	days   int64
+	nanos  int64
+}{
+	{symbol: "y", months: 12},
+	{symbol: "mo", months: 1},
+	{symbol: "w", days: 7},
+	{symbol: "d", days: 1},
+	{symbol: "h", nanos: int64(time.Hour)},
+	{symbol: "m", nanos: int64(time.Minute)},
+	{symbol: "s", nanos: int64(time.Second)},
+	{symbol: "ms", nanos: int64(time.Millisecond)},
+	{symbol: "us", nanos: int64(time.Microsecond)},
+	{symbol: "ns", nanos: 1},
+}
+
+// durationBuilder accumulates the absolute values of the components of a duration being parsed, and checks for
+// overflows.
+type durationBuilder struct {
+	months int64
+	days   int64
+	nanos  int64
+}
+
+// add adds the given quantity of the unit at the given index in durationUnits.
+func (b *durationBuilder) add(quantity string, unitIndex int) error {
+	value, err := strconv.ParseInt(quantity, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid quantity %q", quantity)
+	}
+	unit := durationUnits[unitIndex]
+	switch {
+	case unit.months != 0:
+		b.months, err = addDurationComponent(b.months, value, unit.months, math.MaxInt32, "months")
+	case unit.days != 0:
+		b.days, err = addDurationComponent(b.days, value, unit.days, math.MaxInt32, "days")
+	default:
+		b.nanos, err = addDurationComponent(b.nanos, value, unit.nanos, math.MaxInt64, "nanoseconds")
+	}
+	return err
+}
+
+func addDurationComponent(current, value, factor, max int64, name string) (int64, error) {
+	if value > (max-current)/factor {
+		return 0, fmt.Errorf("number of %s overflows, max is %d", name, max)
+	}
+	return current + value*factor, nil
+}
+
+func (b *durationBuilder) parseUnits(s string) error {
+	last := -1
+	for s != "" {
+		i := 0
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		j := i
+		for j < len(s) && !isDigit(s[j]) {
+			j++
+		}
+		if i == 0 {
+			return fmt.Errorf("expecting quantity, got %q", s)
+		} else if i == j {
+			return fmt.Errorf("missing unit after quantity %s", s[:i])
+		}
+		symbol := strings.ToLower(s[i:j])
+		if symbol == "µs" {
+			symbol = "us"
+		}
+		index := -1
+		for k, unit := range durationUnits {
+			if unit.symbol == symbol {
+				index = k
+				break
+			}
+		}
+		if index == -1 {
+			return fmt.Errorf("unknown unit %q", s[i:j])
+		} else if index <= last {
+			return fmt.Errorf("unit %q must appear before unit %q", durationUnits[index].symbol, durationUnits[last].symbol)
+		} else if err := b.add(s[:i], index); err != nil {
+			return err
+		}
+		last = index
+		s = s[j:]
+	}
+	return nil
+}
+
+func (b *durationBuilder) parseISO8601(s string) error {
+	datePart, timePart := s[1:], ""
+	if t := strings.IndexByte(datePart, 'T'); t >= 0 {
+		datePart, timePart = datePart[:t], datePart[t+1:]
+		if timePart == "" {
+			return errors.New("ISO 8601 duration has no time components after T")
+		}
+	}
+	if datePart == "" && timePart == "" {
+		return errors.New("ISO 8601 duration has no components")
+	} else if err := b.parseISO8601Components(datePart, "YMD", []int{0, 1, 3}, false); err != nil {
+		return err
+	}
+	return b.parseISO8601Components(timePart, "HMS", []int{4, 5, 6}, true)
+}
+
+// parseISO8601Components parses the date or time components of an ISO 8601 duration. The designators must appear in
+// the given order; unitIndices are the indices in durationUnits of the units denoted by each designator.
+func (b *durationBuilder) parseISO8601Components(s string, designators string, unitIndices []int, fractionalSeconds bool) error {
+	last := -1
+	for s != "" {
+		i := 0
+		for i < len(s) && (isDigit(s[i]) || fractionalSeconds && s[i] == '.') {
+			i++
+		}
+		if i == 0 || i == len(s) {
+			return fmt.Errorf("invalid ISO 8601 duration component %q", s)
+		}
+		k := strings.IndexByte(designators, s[i])
+		if k <= last {
+			return fmt.Errorf("unexpected ISO 8601 designator '%c'", s[i])
+		}
+		quantity := s[:i]
+		if dot := strings.IndexByte(quantity, '.'); dot >= 0 {
+			digits := len(quantity) - dot - 1
+			if s[i] != 'S' || dot == 0 || digits == 0 || digits > 9 {
+				return fmt.Errorf("invalid ISO 8601 duration component %q", s[:i+1])
+			}
+			fraction := quantity[dot+1:] + strings.Repeat("0", 9-digits)
+			if err := b.add(fraction, len(durationUnits)-1); err != nil {
+				return err
+			}
+			quantity = quantity[:dot]
+		}
+		if err := b.add(quantity, unitIndices[k]); err != nil {
+			return err
+		}
+		last = k
+		s = s[i+1:]
+	}
+	return nil
+}
+
+func (b *durationBuilder) parseISO8601Weeks(s string) error {
+	quantity := s[1 : len(s)-1]
+	if quantity == "" || strings.IndexFunc(quantity, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return fmt.Errorf("invalid ISO 8601 week duration %q", s)
+	}
+	return b.add(quantity, 2)
+}
+
+func (b *durationBuilder) parseISO8601Alternative(s string) error {
+	// P0001-02-03T04:05:06
+	const layout = "P0000-00-00T00:00:00"
+	if len(s) != len(layout) {
+		return fmt.Errorf("invalid ISO 8601 alternative duration %q", s)
+	}
+	for i := 0; i < len(layout); i++ {
+		if layout[i] == '0' && !isDigit(s[i]) || layout[i] != '0' && s[i] != layout[i] {
+			return fmt.Errorf("invalid ISO 8601 alternative duration %q", s)
+		}
+	}
+	for _, component := range []struct {
+		quantity  string
+		unitIndex int
+	}{
+		{s[1:5], 0},
+		{s[6:8], 1},
+		{s[9:11], 3},
+		{s[12:14], 4},
+		{s[15:17], 5},
+		{s[18:20], 6},
+	} {
+		if err := b.add(component.quantity, component.unitIndex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
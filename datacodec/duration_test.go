@@ -56,6 +56,10 @@ func Test_durationCodec_Encode(t *testing.T) {
 				{"nil pointer", cqlDurationNilPtr(), nil, ""},
 				{"non nil", cqlDurationPos, cqlDurationPosBytes, ""},
 				{"non nil pointer", &cqlDurationPos, cqlDurationPosBytes, ""},
+				{"string", "1mo2d3ns", cqlDurationPosBytes, ""},
+				{"string ISO 8601", "P1M2DT0.000000003S", cqlDurationPosBytes, ""},
+				{"string negative", "-1mo2d3ns", cqlDurationNegBytes, ""},
+				{"string invalid", "1x", nil, fmt.Sprintf("cannot encode string as CQL duration with %v: cannot convert from string to datacodec.CqlDuration: cannot parse duration \"1x\": unknown unit \"x\"", version)},
 				{"conversion failed", 123, nil, fmt.Sprintf("cannot encode int as CQL duration with %v: cannot convert from int to datacodec.CqlDuration: conversion not supported", version)},
 			}
 			for _, tt := range tests {
@@ -103,6 +107,7 @@ func Test_durationCodec_Decode(t *testing.T) {
 				{"null", nil, new(CqlDuration), new(CqlDuration), true, ""},
 				{"non null", cqlDurationPosBytes, new(CqlDuration), &cqlDurationPos, false, ""},
 				{"non null interface", cqlDurationPosBytes, new(interface{}), interfacePtr(cqlDurationPos), false, ""},
+				{"non null string", cqlDurationPosBytes, new(string), stringPtr("1mo2d3ns"), false, ""},
 				{"read failed", []byte{1}, new(CqlDuration), new(CqlDuration), false, fmt.Sprintf("cannot decode CQL duration as *datacodec.CqlDuration with %v: cannot read datacodec.CqlDuration: cannot read duration days: cannot read [vint]: cannot read [unsigned vint]: EOF", version)},
 				{"conversion failed", cqlDurationPosBytes, new(float64), new(float64), false, fmt.Sprintf("cannot decode CQL duration as *float64 with %v: cannot convert from datacodec.CqlDuration to *float64: conversion not supported", version)},
 			}
@@ -152,6 +157,10 @@ func Test_convertToDuration(t *testing.T) {
 		{"from CqlDuration", cqlDurationPos, cqlDurationPos, false, ""},
 		{"from *CqlDuration", &cqlDurationPos, cqlDurationPos, false, ""},
 		{"from *CqlDuration nil", cqlDurationNilPtr(), cqlDurationZero, true, ""},
+		{"from string", "1mo2d3ns", cqlDurationPos, false, ""},
+		{"from *string", stringPtr("-1mo2d3ns"), cqlDurationNeg, false, ""},
+		{"from *string nil", stringNilPtr(), cqlDurationZero, true, ""},
+		{"from string invalid", "1mo2", cqlDurationZero, false, "cannot parse duration \"1mo2\": missing unit after quantity 2"},
 		{"from untyped nil", nil, cqlDurationZero, true, ""},
 		{"from unsupported value type", 123, cqlDurationZero, false, "cannot convert from int to datacodec.CqlDuration: conversion not supported"},
 		{"from unsupported pointer type", intPtr(123), cqlDurationZero, false, "cannot convert from *int to datacodec.CqlDuration: conversion not supported"},
@@ -182,6 +191,10 @@ func Test_convertFromDuration(t *testing.T) {
 		{"to *CqlDuration nil source", cqlDurationZero, true, new(CqlDuration), new(CqlDuration), ""},
 		{"to *CqlDuration empty source", cqlDurationZero, false, new(CqlDuration), new(CqlDuration), ""},
 		{"to *CqlDuration non nil", cqlDurationPos, false, new(CqlDuration), &cqlDurationPos, ""},
+		{"to *string nil source", cqlDurationZero, true, new(string), new(string), ""},
+		{"to *string zero", cqlDurationZero, false, new(string), stringPtr("0s"), ""},
+		{"to *string non nil", cqlDurationNeg, false, new(string), stringPtr("-1mo2d3ns"), ""},
+		{"to *string mixed signs", CqlDuration{1, -1, 0}, false, new(string), new(string), "cannot convert from datacodec.CqlDuration to *string"},
 		{"to untyped nil", cqlDurationPos, false, nil, nil, "cannot convert from datacodec.CqlDuration to <nil>: destination is nil"},
 		{"to non pointer", cqlDurationPos, false, CqlDuration{}, CqlDuration{}, "cannot convert from datacodec.CqlDuration to datacodec.CqlDuration: destination is not pointer"},
 		{"to unsupported pointer type", cqlDurationPos, false, new(float64), new(float64), "cannot convert from datacodec.CqlDuration to *float64: conversion not supported"},
@@ -242,3 +255,126 @@ func Test_readDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCqlDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected CqlDuration
+		err      string
+	}{
+		{"units", "1y2mo3w4d5h6m7s8ms9us10ns", CqlDuration{14, 25, 5*3600e9 + 6*60e9 + 7e9 + 8e6 + 9e3 + 10}, ""},
+		{"units upper case", "1Y2MO3D", CqlDuration{14, 3, 0}, ""},
+		{"units micro sign", "12µs", CqlDuration{0, 0, 12000}, ""},
+		{"units negative", "-1mo2d3ns", cqlDurationNeg, ""},
+		{"units zero", "0s", cqlDurationZero, ""},
+		{"ISO 8601", "P1Y2M3DT4H5M6.000000007S", CqlDuration{14, 3, 4*3600e9 + 5*60e9 + 6e9 + 7}, ""},
+		{"ISO 8601 time only", "PT1H30M", CqlDuration{0, 0, 90 * 60e9}, ""},
+		{"ISO 8601 negative", "-P1M2DT0.000000003S", cqlDurationNeg, ""},
+		{"ISO 8601 weeks", "P3W", CqlDuration{0, 21, 0}, ""},
+		{"ISO 8601 alternative", "P0001-02-03T04:05:06", CqlDuration{14, 3, 4*3600e9 + 5*60e9 + 6e9}, ""},
+		{"ISO 8601 alternative negative", "-P0001-02-03T04:05:06", CqlDuration{-14, -3, -(4*3600e9 + 5*60e9 + 6e9)}, ""},
+		{"max", "2147483647mo2147483647d9223372036854775807ns", cqlDurationMax, ""},
+		{"empty", "", cqlDurationZero, "cannot parse duration \"\": empty duration"},
+		{"minus only", "-", cqlDurationZero, "cannot parse duration \"-\": empty duration"},
+		{"unknown unit", "1x", cqlDurationZero, "cannot parse duration \"1x\": unknown unit \"x\""},
+		{"missing unit", "1h2", cqlDurationZero, "cannot parse duration \"1h2\": missing unit after quantity 2"},
+		{"wrong order", "1s2h", cqlDurationZero, "cannot parse duration \"1s2h\": unit \"h\" must appear before unit \"s\""},
+		{"repeated unit", "1h2h", cqlDurationZero, "cannot parse duration \"1h2h\": unit \"h\" must appear before unit \"h\""},
+		{"months overflow", "2147483648mo", cqlDurationZero, "cannot parse duration \"2147483648mo\": number of months overflows, max is 2147483647"},
+		{"years overflow", "178956971y", cqlDurationZero, "number of months overflows"},
+		{"days overflow", "306783379w1d", cqlDurationZero, "number of days overflows"},
+		{"nanos overflow", "2562048h", cqlDurationZero, "number of nanoseconds overflows"},
+		{"ISO 8601 no components", "P", cqlDurationZero, "ISO 8601 duration has no components"},
+		{"ISO 8601 empty time", "P1DT", cqlDurationZero, "ISO 8601 duration has no time components after T"},
+		{"ISO 8601 wrong designator", "P1H", cqlDurationZero, "unexpected ISO 8601 designator 'H'"},
+		{"ISO 8601 weeks invalid", "P1DW", cqlDurationZero, "invalid ISO 8601 week duration"},
+		{"ISO 8601 alternative invalid", "P0001-02-03T04:05", cqlDurationZero, "invalid ISO 8601 alternative duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseCqlDuration(tt.input)
+			assert.Equal(t, tt.expected, actual)
+			assertErrorMessage(t, tt.err, err)
+		})
+	}
+}
+
+func TestCqlDuration_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    CqlDuration
+		expected string
+	}{
+		{"zero", cqlDurationZero, "0s"},
+		{"positive", cqlDurationPos, "1mo2d3ns"},
+		{"negative", cqlDurationNeg, "-1mo2d3ns"},
+		{"all units", CqlDuration{14, 3, 4*3600e9 + 5*60e9 + 6e9 + 7e6 + 8e3 + 9}, "1y2mo3d4h5m6s7ms8us9ns"},
+		{"max", cqlDurationMax, "178956970y7mo2147483647d2562047h47m16s854ms775us807ns"},
+		{"min", cqlDurationMin, "-178956970y8mo2147483648d2562047h47m16s854ms775us808ns"},
+		{"mixed signs", CqlDuration{1, -1, 0}, "invalid duration (1 months, -1 days, 0 nanoseconds)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.input.String())
+		})
+	}
+}
+
+func TestCqlDuration_ISO8601(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    CqlDuration
+		expected string
+		err      string
+	}{
+		{"zero", cqlDurationZero, "PT0S", ""},
+		{"positive", cqlDurationPos, "P1M2DT0.000000003S", ""},
+		{"negative", cqlDurationNeg, "-P1M2DT0.000000003S", ""},
+		{"date only", CqlDuration{14, 3, 0}, "P1Y2M3D", ""},
+		{"time only", CqlDuration{0, 0, 90 * 60e9}, "PT1H30M", ""},
+		{"fractional seconds", CqlDuration{0, 0, 1500e6}, "PT1.5S", ""},
+		{"mixed signs", CqlDuration{1, -1, 0}, "", "must have the same sign"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.input.ISO8601()
+			assert.Equal(t, tt.expected, actual)
+			assertErrorMessage(t, tt.err, err)
+			if err == nil {
+				parsed, err := ParseCqlDuration(actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.input, parsed)
+			}
+		})
+	}
+}
+
+func TestCqlDuration_ISO8601Alternative(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    CqlDuration
+		expected string
+		err      string
+	}{
+		{"zero", cqlDurationZero, "P0000-00-00T00:00:00", ""},
+		{"positive", CqlDuration{14, 3, 4*3600e9 + 5*60e9 + 6e9}, "P0001-02-03T04:05:06", ""},
+		{"negative", CqlDuration{-14, -3, -(4*3600e9 + 5*60e9 + 6e9)}, "-P0001-02-03T04:05:06", ""},
+		{"fractional seconds", cqlDurationPos, "", "cannot be represented in ISO 8601 alternative format"},
+		{"too many days", CqlDuration{0, 100, 0}, "", "cannot be represented in ISO 8601 alternative format"},
+		{"too many hours", CqlDuration{0, 0, 100 * 3600e9}, "", "cannot be represented in ISO 8601 alternative format"},
+		{"too many years", CqlDuration{10000 * 12, 0, 0}, "", "cannot be represented in ISO 8601 alternative format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.input.ISO8601Alternative()
+			assert.Equal(t, tt.expected, actual)
+			assertErrorMessage(t, tt.err, err)
+			if err == nil {
+				parsed, err := ParseCqlDuration(actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.input, parsed)
+			}
+		})
+	}
+}
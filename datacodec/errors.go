@@ -17,6 +17,7 @@ package datacodec
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 
 	"github.com/datastax/go-cassandra-native-protocol/datatype"
@@ -32,6 +33,8 @@ var ErrDestinationTypeNotSupported = errors.New("destination type not supported"
 
 var ErrPointerTypeExpected = errors.New("destination is not pointer")
 
+var ErrVectorNullElement = errors.New("vector elements cannot be null")
+
 func errCannotEncode(source interface{}, dataType datatype.DataType, version primitive.ProtocolVersion, err error) error {
 	return fmt.Errorf("cannot encode %T as CQL %s with %v: %w", source, dataType, version, err)
 }
@@ -219,6 +222,14 @@ func collectionSizeNegative(size int) error {
 	return fmt.Errorf("expected collection size >= 0, got: %d", size)
 }
 
+func errVectorDimensionsInvalid(dimensions int) error {
+	return fmt.Errorf("expected vector dimensions > 0, got: %d", dimensions)
+}
+
+func errWrongVectorSize(expected, actual int) error {
+	return fmt.Errorf("expected vector of %d elements, got: %d", expected, actual)
+}
+
 func errCannotCreateCodec(dt datatype.DataType) error {
 	return fmt.Errorf("cannot create data codec for CQL type %v", dt)
 }
@@ -236,3 +247,55 @@ func errDestinationInvalid(dest interface{}) error {
 		return ErrConversionNotSupported
 	}
 }
+
+func errCannotCreateColumnCodec(i int, name string, err error) error {
+	return fmt.Errorf("cannot create codec for column %d (%s): %w", i, name, err)
+}
+
+func errCannotCreateColumn(i int, name string, err error) error {
+	return fmt.Errorf("cannot create zero column %d (%s): %w", i, name, err)
+}
+
+func errCannotDecodeColumn(i int, name string, err error) error {
+	return fmt.Errorf("cannot decode column %d (%s): %w", i, name, err)
+}
+
+func errCannotInjectColumn(i int, name string, err error) error {
+	return fmt.Errorf("cannot inject column %d (%s): %w", i, name, err)
+}
+
+func errCannotCreateVariableCodec(i int, name string, err error) error {
+	return fmt.Errorf("cannot create codec for variable %d (%s): %w", i, name, err)
+}
+
+func errCannotBindVariable(i int, name string, err error) error {
+	return fmt.Errorf("cannot bind variable %d (%s): %w", i, name, err)
+}
+
+func errCannotRegisterCodec(codec Codec, err error) error {
+	return fmt.Errorf("cannot register codec for CQL type %v: %w", codec.DataType(), err)
+}
+
+func errValueTooLong(length int) error {
+	return fmt.Errorf("encoded value too long (%d bytes, max is %d)", length, math.MaxInt32)
+}
+
+func errCannotConvertElement(verb string, i int, err error) error {
+	return fmt.Errorf("cannot %s element %d: %w", verb, i, err)
+}
+
+func errCannotConvertMapKey(verb string, i int, err error) error {
+	return fmt.Errorf("cannot %s entry %d key: %w", verb, i, err)
+}
+
+func errCannotConvertMapValue(verb string, i int, err error) error {
+	return fmt.Errorf("cannot %s entry %d value: %w", verb, i, err)
+}
+
+func errCannotConvertUdtField(verb string, i int, name string, err error) error {
+	return fmt.Errorf("cannot %s field %d (%s): %w", verb, i, name, err)
+}
+
+func errCannotMarshalColumn(i int, name string, err error) error {
+	return fmt.Errorf("cannot marshal column %d (%s): %w", i, name, err)
+}
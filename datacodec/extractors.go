@@ -80,27 +80,40 @@ func (e *sliceExtractor) getElem(index int, _ interface{}) (interface{}, error)
 	return e.source.Index(index).Interface(), nil
 }
 
+// getKey returns the map key of the field at the given index among the fields mapped by name, so that keys are
+// consistent with the fields that getElem can locate.
 func (e *structExtractor) getKey(index int) interface{} {
-	fieldName := e.source.Type().Field(index).Name
-	field, _ := e.source.Type().FieldByName(fieldName)
-	tag := field.Tag.Get("cassandra")
-	if tag != "" {
-		return tag
+	fields := structFieldsOf(e.source.Type()).byName
+	if index < 0 || index >= len(fields) {
+		return nil
 	}
-	return strings.ToLower(fieldName)
+	field := fields[index]
+	if field.tagged {
+		return field.name
+	}
+	return strings.ToLower(field.name)
 }
 
 func (e *structExtractor) getElem(_ int, key interface{}) (interface{}, error) {
-	var field reflect.Value
+	var field *structField
 	if name, ok := key.(string); ok {
-		field = locateFieldByName(e.source, name)
+		field = findStructFieldByName(e.source.Type(), name)
 	} else if index, ok := key.(int); ok {
-		field = locateFieldByIndex(e.source, index)
+		field = findStructFieldByIndex(e.source.Type(), index)
+	}
+	if field == nil {
+		return nil, errStructFieldInvalid(e.source, key)
 	}
-	if !field.IsValid() || !field.CanInterface() {
+	value := field.valueOf(e.source, false)
+	if !value.IsValid() {
+		// field promoted from a nil embedded struct pointer
+		return nil, nil
+	} else if !value.CanInterface() {
 		return nil, errStructFieldInvalid(e.source, key)
+	} else if field.omitEmpty && value.IsZero() {
+		return nil, nil
 	}
-	return field.Interface(), nil
+	return value.Interface(), nil
 }
 
 func (e *mapExtractor) getKey(index int) interface{} {
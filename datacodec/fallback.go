@@ -0,0 +1,155 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// encodeFallback is invoked by codecs for simple CQL types when the given source could not be encoded. If the error is
+// due to an unsupported source type, and the source implements one of the standard interfaces below, then the source
+// is converted to a value of a supported type, which is then encoded by the codec:
+//
+//   - driver.Valuer: the source is converted to the driver value it returns;
+//   - encoding.BinaryMarshaler, for blob and custom types only: the source is converted to []byte;
+//   - encoding.TextMarshaler: the source is converted to a string.
+//
+// In all other cases, the original error is returned.
+func encodeFallback(codec Codec, source interface{}, version primitive.ProtocolVersion, err error) ([]byte, error) {
+	if !errors.Is(err, ErrConversionNotSupported) {
+		return nil, err
+	}
+	fallback, found, fallbackErr := convertToFallbackSource(source, isBinary(codec))
+	if !found {
+		return nil, err
+	} else if fallbackErr != nil {
+		return nil, errCannotEncode(source, codec.DataType(), version, fallbackErr)
+	}
+	return codec.Encode(fallback, version)
+}
+
+// decodeFallback is invoked by codecs for simple CQL types when the given source could not be decoded. If the error
+// is due to an unsupported destination type, and the destination is a non-nil pointer that implements one of the
+// standard interfaces below, then the source is decoded into an intermediary value of a supported type, which is then
+// passed to the destination:
+//
+//   - sql.Scanner: the source is decoded into the codec's preferred Go type, then converted to a driver value if
+//     possible, e.g. int32 is converted to int64 and [16]byte to []byte;
+//   - encoding.BinaryUnmarshaler, for blob and custom types only: the source is decoded into []byte;
+//   - encoding.TextUnmarshaler: the source is decoded into a string.
+//
+// If the source was NULL, sql.Scanner receives nil, and the other destinations are set to their zero value. In all
+// other cases, the original error is returned.
+func decodeFallback(codec Codec, source []byte, dest interface{}, version primitive.ProtocolVersion, err error) error {
+	if !errors.Is(err, ErrConversionNotSupported) {
+		return err
+	}
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		return err
+	}
+	var fallbackErr error
+	if scanner, ok := dest.(sql.Scanner); ok {
+		var value interface{}
+		if _, fallbackErr = codec.Decode(source, &value, version); fallbackErr == nil {
+			fallbackErr = scanner.Scan(convertToDriverValue(value))
+		}
+	} else if unmarshaler, ok := dest.(encoding.BinaryUnmarshaler); ok && isBinary(codec) {
+		var value []byte
+		var wasNull bool
+		if wasNull, fallbackErr = codec.Decode(source, &value, version); fallbackErr == nil {
+			if wasNull {
+				destValue.Elem().Set(reflect.Zero(destValue.Elem().Type()))
+			} else {
+				fallbackErr = unmarshaler.UnmarshalBinary(value)
+			}
+		}
+	} else if unmarshaler, ok := dest.(encoding.TextUnmarshaler); ok {
+		var value string
+		var wasNull bool
+		if wasNull, fallbackErr = codec.Decode(source, &value, version); fallbackErr == nil {
+			if wasNull {
+				destValue.Elem().Set(reflect.Zero(destValue.Elem().Type()))
+			} else {
+				fallbackErr = unmarshaler.UnmarshalText([]byte(value))
+			}
+		}
+	} else {
+		return err
+	}
+	if fallbackErr != nil {
+		return errCannotDecode(dest, codec.DataType(), version, fallbackErr)
+	}
+	return nil
+}
+
+// convertToFallbackSource converts the given source to a value of a type supported by all codecs for simple CQL types,
+// if the source implements one of the standard interfaces driver.Valuer, encoding.BinaryMarshaler (only if binary is
+// true) or encoding.TextMarshaler. Return parameter found is false if the source does not implement any of these
+// interfaces. Nil pointers are converted to nil.
+func convertToFallbackSource(source interface{}, binary bool) (fallback interface{}, found bool, err error) {
+	valuer, isValuer := source.(driver.Valuer)
+	binaryMarshaler, isBinaryMarshaler := source.(encoding.BinaryMarshaler)
+	textMarshaler, isTextMarshaler := source.(encoding.TextMarshaler)
+	isBinaryMarshaler = isBinaryMarshaler && binary
+	if found = isValuer || isBinaryMarshaler || isTextMarshaler; !found {
+		return
+	} else if sourceValue := reflect.ValueOf(source); sourceValue.Kind() == reflect.Ptr && sourceValue.IsNil() {
+		return
+	}
+	switch {
+	case isValuer:
+		if fallback, err = valuer.Value(); err == nil && !driver.IsValue(fallback) {
+			err = fmt.Errorf("driver.Valuer returned a non-driver value of type %T", fallback)
+		}
+	case isBinaryMarshaler:
+		fallback, err = binaryMarshaler.MarshalBinary()
+	default:
+		var text []byte
+		if text, err = textMarshaler.MarshalText(); err == nil {
+			fallback = string(text)
+		}
+	}
+	return
+}
+
+// convertToDriverValue converts the given decoded value to one of the value types supported by sql.Scanner, if
+// possible; e.g. integers are converted to int64, and byte arrays to []byte. Other values are returned unchanged.
+func convertToDriverValue(value interface{}) interface{} {
+	if driver.IsValue(value) {
+		return value
+	} else if converted, err := driver.DefaultParameterConverter.ConvertValue(value); err == nil {
+		return converted
+	} else if v := reflect.ValueOf(value); v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8 {
+		converted := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(converted), v)
+		return converted
+	}
+	return value
+}
+
+// isBinary returns true if the given codec encodes CQL blobs or custom types, for which encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler are supported.
+func isBinary(codec Codec) bool {
+	code := codec.DataType().Code()
+	return code == primitive.DataTypeCodeBlob || code == primitive.DataTypeCodeCustom
+}
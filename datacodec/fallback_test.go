@@ -0,0 +1,238 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// textID implements encoding.TextMarshaler and encoding.TextUnmarshaler.
+type textID struct {
+	prefix string
+	id     string
+}
+
+func (t textID) MarshalText() ([]byte, error) {
+	if t.prefix == "" {
+		return nil, errors.New("empty prefix")
+	}
+	return []byte(t.prefix + ":" + t.id), nil
+}
+
+func (t *textID) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid text ID: %s", text)
+	}
+	t.prefix, t.id = parts[0], parts[1]
+	return nil
+}
+
+// binaryID implements both binary and text marshaling interfaces.
+type binaryID struct {
+	id uint16
+}
+
+func (b binaryID) MarshalBinary() ([]byte, error) {
+	return []byte{byte(b.id >> 8), byte(b.id)}, nil
+}
+
+func (b *binaryID) UnmarshalBinary(data []byte) error {
+	if len(data) != 2 {
+		return errors.New("invalid binary ID")
+	}
+	b.id = uint16(data[0])<<8 | uint16(data[1])
+	return nil
+}
+
+func (b binaryID) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprint(b.id)), nil
+}
+
+func (b *binaryID) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscan(string(text), &b.id)
+	return err
+}
+
+// cents implements driver.Valuer and sql.Scanner.
+type cents struct {
+	value int64
+	valid bool
+}
+
+func (c cents) Value() (driver.Value, error) {
+	if !c.valid {
+		return nil, nil
+	}
+	return c.value, nil
+}
+
+func (c *cents) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case nil:
+		*c = cents{}
+	case int64:
+		*c = cents{s, true}
+	default:
+		return fmt.Errorf("cannot scan %T", src)
+	}
+	return nil
+}
+
+// rawScanner records the value it received.
+type rawScanner struct {
+	src interface{}
+}
+
+func (r *rawScanner) Scan(src interface{}) error {
+	r.src = src
+	return nil
+}
+
+// badValuer returns a value that is not a valid driver value.
+type badValuer struct{}
+
+func (b badValuer) Value() (driver.Value, error) {
+	return int32(1), nil
+}
+
+func TestFallback_Encode(t *testing.T) {
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		codec    Codec
+		source   interface{}
+		expected []byte
+		err      string
+	}{
+		{"varchar TextMarshaler", Varchar, textID{"customer", "42"}, []byte("customer:42"), ""},
+		{"varchar TextMarshaler pointer", Varchar, &textID{"customer", "42"}, []byte("customer:42"), ""},
+		{"varchar TextMarshaler nil pointer", Varchar, (*textID)(nil), nil, ""},
+		{"varchar TextMarshaler error", Varchar, textID{}, nil, "cannot encode datacodec.textID as CQL varchar with ProtocolVersion OSS 5: empty prefix"},
+		{"varchar time.Time", Varchar, now, []byte("2021-01-02T03:04:05Z"), ""},
+		{"int TextMarshaler", Int, binaryID{258}, []byte{0, 0, 1, 2}, ""},
+		{"blob BinaryMarshaler", Blob, binaryID{258}, []byte{1, 2}, ""},
+		{"blob TextMarshaler", Blob, textID{"a", "b"}, []byte("a:b"), ""},
+		{"bigint Valuer", Bigint, cents{123, true}, []byte{0, 0, 0, 0, 0, 0, 0, 123}, ""},
+		{"bigint Valuer null", Bigint, cents{}, nil, ""},
+		{"int Valuer", Int, &cents{123, true}, []byte{0, 0, 0, 123}, ""},
+		{"int Valuer out of range", Int, cents{1 << 40, true}, nil, "cannot encode int64 as CQL int with ProtocolVersion OSS 5: cannot convert from int64 to int32: value out of range: 1099511627776"},
+		{"varchar Valuer wrong type", Varchar, cents{123, true}, nil, "cannot encode int64 as CQL varchar with ProtocolVersion OSS 5: cannot convert from int64 to []uint8: conversion not supported"},
+		{"int invalid Valuer", Int, badValuer{}, nil, "cannot encode datacodec.badValuer as CQL int with ProtocolVersion OSS 5: driver.Valuer returned a non-driver value of type int32"},
+		{"no fallback", Int, struct{}{}, nil, "cannot encode struct {} as CQL int with ProtocolVersion OSS 5: cannot convert from struct {} to int32: conversion not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.codec.Encode(tt.source, primitive.ProtocolVersion5)
+			assert.Equal(t, tt.expected, actual)
+			assertErrorMessage(t, tt.err, err)
+		})
+	}
+}
+
+func TestFallback_Decode(t *testing.T) {
+	t.Run("varchar TextUnmarshaler", func(t *testing.T) {
+		var dest textID
+		wasNull, err := Varchar.Decode([]byte("customer:42"), &dest, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.False(t, wasNull)
+		assert.Equal(t, textID{"customer", "42"}, dest)
+		dest = textID{"customer", "42"}
+		wasNull, err = Varchar.Decode(nil, &dest, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.True(t, wasNull)
+		assert.Equal(t, textID{}, dest)
+		_, err = Varchar.Decode([]byte("customer"), &dest, primitive.ProtocolVersion5)
+		assert.EqualError(t, err, "cannot decode CQL varchar as *datacodec.textID with ProtocolVersion OSS 5: invalid text ID: customer")
+	})
+	t.Run("int TextUnmarshaler", func(t *testing.T) {
+		var dest binaryID
+		_, err := Int.Decode([]byte{0, 0, 1, 2}, &dest, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, binaryID{258}, dest)
+	})
+	t.Run("blob BinaryUnmarshaler", func(t *testing.T) {
+		var dest binaryID
+		_, err := Blob.Decode([]byte{1, 2}, &dest, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, binaryID{258}, dest)
+	})
+	t.Run("bigint Scanner", func(t *testing.T) {
+		var dest cents
+		wasNull, err := Bigint.Decode([]byte{0, 0, 0, 0, 0, 0, 0, 123}, &dest, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.False(t, wasNull)
+		assert.Equal(t, cents{123, true}, dest)
+		wasNull, err = Bigint.Decode(nil, &dest, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.True(t, wasNull)
+		assert.Equal(t, cents{}, dest)
+	})
+	t.Run("int Scanner", func(t *testing.T) {
+		var dest cents
+		_, err := Int.Decode([]byte{0, 0, 0, 123}, &dest, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, cents{123, true}, dest)
+	})
+	t.Run("Scanner error", func(t *testing.T) {
+		var dest cents
+		_, err := Varchar.Decode([]byte("abc"), &dest, primitive.ProtocolVersion5)
+		assert.EqualError(t, err, "cannot decode CQL varchar as *datacodec.cents with ProtocolVersion OSS 5: cannot scan string")
+	})
+	t.Run("Scanner driver values", func(t *testing.T) {
+		var dest rawScanner
+		_, err := Uuid.Decode(customerIDBytes, &dest, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, customerIDBytes, dest.src)
+		_, err = Float.Decode([]byte{0x3f, 0x80, 0, 0}, &dest, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, dest.src)
+		_, err = Timestamp.Decode([]byte{0, 0, 0, 0, 0, 0, 0, 0}, &dest, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, time.Unix(0, 0).UTC(), dest.src)
+	})
+	t.Run("nil destination", func(t *testing.T) {
+		_, err := Bigint.Decode([]byte{0, 0, 0, 0, 0, 0, 0, 123}, (*cents)(nil), primitive.ProtocolVersion5)
+		assert.EqualError(t, err, "cannot decode CQL bigint as *datacodec.cents with ProtocolVersion OSS 5: cannot convert from int64 to *datacodec.cents: conversion not supported")
+	})
+	t.Run("no fallback", func(t *testing.T) {
+		var dest struct{}
+		_, err := Int.Decode([]byte{0, 0, 0, 123}, &dest, primitive.ProtocolVersion5)
+		assert.EqualError(t, err, "cannot decode CQL int as *struct {} with ProtocolVersion OSS 5: cannot convert from int32 to *struct {}: conversion not supported")
+	})
+}
+
+func TestFallback_Collections(t *testing.T) {
+	codec, err := NewCodec(datatype.NewList(datatype.Varchar))
+	require.NoError(t, err)
+	source := []textID{{"a", "1"}, {"b", "2"}}
+	encoded, err := codec.Encode(source, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	var dest []textID
+	_, err = codec.Decode(encoded, &dest, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	assert.Equal(t, source, dest)
+}
@@ -39,18 +39,28 @@ func (c *floatCodec) Encode(source interface{}, version primitive.ProtocolVersio
 		dest = writeFloat32(val)
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *floatCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	var val float32
+	if val, wasNil, err = convertToFloat32(source); err != nil {
+		return appendEncoded(c, dest, source, version)
+	} else if !wasNil {
+		dest = appendFloat32(dest, val)
+	}
+	return dest, wasNil, nil
+}
+
 func (c *floatCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	var val float32
 	if val, wasNull, err = readFloat32(source); err == nil {
 		err = convertFromFloat32(val, wasNull, dest)
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -117,6 +127,10 @@ func convertFromFloat32(val float32, wasNull bool, dest interface{}) (err error)
 
 const lengthOfFloat = primitive.LengthOfInt
 
+func appendFloat32(dest []byte, val float32) []byte {
+	return appendInt32(dest, int32(math.Float32bits(val)))
+}
+
 func writeFloat32(val float32) (dest []byte) {
 	dest = make([]byte, lengthOfFloat)
 	binary.BigEndian.PutUint32(dest, math.Float32bits(val))
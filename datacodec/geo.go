@@ -0,0 +1,592 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// DsePoint is the Go representation of the DSE PointType.
+type DsePoint struct {
+	X float64
+	Y float64
+}
+
+// DseLineString is the Go representation of the DSE LineStringType.
+type DseLineString struct {
+	Points []DsePoint
+}
+
+// DsePolygon is the Go representation of the DSE PolygonType. The first ring is the exterior ring; subsequent rings,
+// if any, are interior rings.
+type DsePolygon struct {
+	Rings [][]DsePoint
+}
+
+// Point is a codec for the DSE PointType, a custom type whose values are encoded in Well-Known Binary (WKB) format.
+// Its preferred Go type is DsePoint, but it can encode from and decode to string as well, in which case the string is
+// the Well-Known Text (WKT) representation of the point, e.g. "POINT (1 2)".
+var Point Codec = &geoCodec{dataType: datatype.Point, geometryType: wkbPoint}
+
+// LineString is a codec for the DSE LineStringType, a custom type whose values are encoded in Well-Known Binary (WKB)
+// format. Its preferred Go type is DseLineString, but it can encode from and decode to string as well, in which case
+// the string is the Well-Known Text (WKT) representation of the line string, e.g. "LINESTRING (1 2, 3 4)".
+var LineString Codec = &geoCodec{dataType: datatype.LineString, geometryType: wkbLineString}
+
+// Polygon is a codec for the DSE PolygonType, a custom type whose values are encoded in Well-Known Binary (WKB)
+// format. Its preferred Go type is DsePolygon, but it can encode from and decode to string as well, in which case the
+// string is the Well-Known Text (WKT) representation of the polygon, e.g. "POLYGON ((0 0, 1 0, 1 1, 0 0))".
+var Polygon Codec = &geoCodec{dataType: datatype.Polygon, geometryType: wkbPolygon}
+
+// WKB geometry types, see https://en.wikipedia.org/wiki/Well-known_text_representation_of_geometry.
+const (
+	wkbPoint      = uint32(1)
+	wkbLineString = uint32(2)
+	wkbPolygon    = uint32(3)
+)
+
+// WKB byte order markers.
+const (
+	wkbBigEndian    = byte(0)
+	wkbLittleEndian = byte(1)
+)
+
+type geoCodec struct {
+	dataType     *datatype.Custom
+	geometryType uint32
+}
+
+func (c *geoCodec) DataType() datatype.DataType {
+	return c.dataType
+}
+
+func (c *geoCodec) Encode(source interface{}, version primitive.ProtocolVersion) (dest []byte, err error) {
+	var val interface{}
+	if val, err = c.convertToGeometry(source); err == nil && val != nil {
+		dest = writeGeometry(val)
+	}
+	if err != nil {
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
+	}
+	return
+}
+
+func (c *geoCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
+	var val interface{}
+	if val, wasNull, err = readGeometry(source, c.geometryType); err == nil {
+		err = c.convertFromGeometry(val, wasNull, dest)
+	}
+	if err != nil {
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
+	}
+	return
+}
+
+// convertToGeometry returns either a DsePoint, a DseLineString or a DsePolygon, or nil if the source was nil.
+func (c *geoCodec) convertToGeometry(source interface{}) (val interface{}, err error) {
+	switch s := source.(type) {
+	case DsePoint, DseLineString, DsePolygon:
+		val = s
+	case *DsePoint:
+		if s != nil {
+			val = *s
+		}
+	case *DseLineString:
+		if s != nil {
+			val = *s
+		}
+	case *DsePolygon:
+		if s != nil {
+			val = *s
+		}
+	case string:
+		val, err = parseWkt(s, c.geometryType)
+	case *string:
+		if s != nil {
+			val, err = parseWkt(*s, c.geometryType)
+		}
+	case nil:
+	default:
+		err = ErrConversionNotSupported
+	}
+	if err == nil && val != nil && geometryTypeOf(val) != c.geometryType {
+		err = ErrConversionNotSupported
+	}
+	if err != nil {
+		err = errSourceConversionFailed(source, zeroGeometry(c.geometryType), err)
+	}
+	return
+}
+
+func (c *geoCodec) convertFromGeometry(val interface{}, wasNull bool, dest interface{}) (err error) {
+	switch d := dest.(type) {
+	case *interface{}:
+		if d == nil {
+			err = ErrNilDestination
+		} else if wasNull {
+			*d = nil
+		} else {
+			*d = val
+		}
+	case *string:
+		if d == nil {
+			err = ErrNilDestination
+		} else if wasNull {
+			*d = ""
+		} else {
+			*d = val.(fmt.Stringer).String()
+		}
+	case *DsePoint:
+		if d == nil {
+			err = ErrNilDestination
+		} else if c.geometryType != wkbPoint {
+			err = ErrConversionNotSupported
+		} else if wasNull {
+			*d = DsePoint{}
+		} else {
+			*d = val.(DsePoint)
+		}
+	case *DseLineString:
+		if d == nil {
+			err = ErrNilDestination
+		} else if c.geometryType != wkbLineString {
+			err = ErrConversionNotSupported
+		} else if wasNull {
+			*d = DseLineString{}
+		} else {
+			*d = val.(DseLineString)
+		}
+	case *DsePolygon:
+		if d == nil {
+			err = ErrNilDestination
+		} else if c.geometryType != wkbPolygon {
+			err = ErrConversionNotSupported
+		} else if wasNull {
+			*d = DsePolygon{}
+		} else {
+			*d = val.(DsePolygon)
+		}
+	default:
+		err = errDestinationInvalid(dest)
+	}
+	if err != nil {
+		err = errDestinationConversionFailed(zeroGeometry(c.geometryType), dest, err)
+	}
+	return
+}
+
+func zeroGeometry(geometryType uint32) interface{} {
+	switch geometryType {
+	case wkbPoint:
+		return DsePoint{}
+	case wkbLineString:
+		return DseLineString{}
+	default:
+		return DsePolygon{}
+	}
+}
+
+func geometryTypeOf(val interface{}) uint32 {
+	switch val.(type) {
+	case DsePoint:
+		return wkbPoint
+	case DseLineString:
+		return wkbLineString
+	case DsePolygon:
+		return wkbPolygon
+	}
+	return 0
+}
+
+// Implementation notes: DSE geospatial values are encoded in Well-Known Binary (WKB) format: one byte for the byte
+// order (0 for big-endian, 1 for little-endian), a 4-byte geometry type, followed by the geometry coordinates. Points
+// are encoded as two 8-byte floating point numbers; line strings as a 4-byte number of points followed by the points;
+// polygons as a 4-byte number of rings, each ring being encoded as a line string. DSE itself encodes in little-endian
+// order, but accepts both orders; this codec does the same.
+
+func writeGeometry(val interface{}) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(wkbLittleEndian)
+	order := binary.LittleEndian
+	_ = binary.Write(buf, order, geometryTypeOf(val))
+	switch v := val.(type) {
+	case DsePoint:
+		writeWkbPoint(v, buf, order)
+	case DseLineString:
+		writeWkbPoints(v.Points, buf, order)
+	case DsePolygon:
+		_ = binary.Write(buf, order, uint32(len(v.Rings)))
+		for _, ring := range v.Rings {
+			writeWkbPoints(ring, buf, order)
+		}
+	}
+	return buf.Bytes()
+}
+
+func writeWkbPoints(points []DsePoint, buf *bytes.Buffer, order binary.ByteOrder) {
+	_ = binary.Write(buf, order, uint32(len(points)))
+	for _, point := range points {
+		writeWkbPoint(point, buf, order)
+	}
+}
+
+func writeWkbPoint(point DsePoint, buf *bytes.Buffer, order binary.ByteOrder) {
+	_ = binary.Write(buf, order, point.X)
+	_ = binary.Write(buf, order, point.Y)
+}
+
+func readGeometry(source []byte, geometryType uint32) (val interface{}, wasNull bool, err error) {
+	length := len(source)
+	if wasNull = length == 0; !wasNull {
+		reader := &wkbReader{source: source}
+		if val, err = reader.readGeometry(geometryType); err == nil && reader.pos != length {
+			err = errBytesRemaining(length, length-reader.pos)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("cannot read WKB geometry: %w", err)
+	}
+	return
+}
+
+type wkbReader struct {
+	source []byte
+	pos    int
+	order  binary.ByteOrder
+}
+
+func (r *wkbReader) readGeometry(geometryType uint32) (interface{}, error) {
+	if len(r.source) < 1 {
+		return nil, errWrongMinimumLength(1, len(r.source))
+	}
+	switch r.source[0] {
+	case wkbBigEndian:
+		r.order = binary.BigEndian
+	case wkbLittleEndian:
+		r.order = binary.LittleEndian
+	default:
+		return nil, fmt.Errorf("invalid byte order: %d", r.source[0])
+	}
+	r.pos++
+	if actual, err := r.readUint32(); err != nil {
+		return nil, err
+	} else if actual != geometryType {
+		return nil, fmt.Errorf("expected geometry type %d, got: %d", geometryType, actual)
+	}
+	switch geometryType {
+	case wkbPoint:
+		return r.readPoint()
+	case wkbLineString:
+		points, err := r.readPoints()
+		return DseLineString{Points: points}, err
+	default:
+		numRings, err := r.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		polygon := DsePolygon{}
+		for i := uint32(0); i < numRings; i++ {
+			ring, err := r.readPoints()
+			if err != nil {
+				return nil, err
+			}
+			polygon.Rings = append(polygon.Rings, ring)
+		}
+		return polygon, nil
+	}
+}
+
+func (r *wkbReader) readPoints() ([]DsePoint, error) {
+	numPoints, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	} else if remaining := len(r.source) - r.pos; uint64(numPoints)*16 > uint64(remaining) {
+		return nil, errWrongMinimumLength(int(numPoints)*16, remaining)
+	}
+	points := make([]DsePoint, numPoints)
+	for i := range points {
+		if points[i], err = r.readPoint(); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func (r *wkbReader) readPoint() (point DsePoint, err error) {
+	if point.X, err = r.readFloat64(); err == nil {
+		point.Y, err = r.readFloat64()
+	}
+	return
+}
+
+func (r *wkbReader) readUint32() (uint32, error) {
+	if remaining := len(r.source) - r.pos; remaining < 4 {
+		return 0, errWrongMinimumLength(4, remaining)
+	}
+	val := r.order.Uint32(r.source[r.pos:])
+	r.pos += 4
+	return val, nil
+}
+
+func (r *wkbReader) readFloat64() (float64, error) {
+	if remaining := len(r.source) - r.pos; remaining < 8 {
+		return 0, errWrongMinimumLength(8, remaining)
+	}
+	val := math.Float64frombits(r.order.Uint64(r.source[r.pos:]))
+	r.pos += 8
+	return val, nil
+}
+
+// Well-Known Text (WKT) conversions
+
+func (p DsePoint) String() string {
+	sb := &strings.Builder{}
+	sb.WriteString("POINT (")
+	writeWktPoint(p, sb)
+	sb.WriteString(")")
+	return sb.String()
+}
+
+func (l DseLineString) String() string {
+	if len(l.Points) == 0 {
+		return "LINESTRING EMPTY"
+	}
+	sb := &strings.Builder{}
+	sb.WriteString("LINESTRING ")
+	writeWktPoints(l.Points, sb)
+	return sb.String()
+}
+
+func (p DsePolygon) String() string {
+	if len(p.Rings) == 0 {
+		return "POLYGON EMPTY"
+	}
+	sb := &strings.Builder{}
+	sb.WriteString("POLYGON (")
+	for i, ring := range p.Rings {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeWktPoints(ring, sb)
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+func writeWktPoints(points []DsePoint, sb *strings.Builder) {
+	sb.WriteString("(")
+	for i, point := range points {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeWktPoint(point, sb)
+	}
+	sb.WriteString(")")
+}
+
+func writeWktPoint(p DsePoint, sb *strings.Builder) {
+	sb.WriteString(strconv.FormatFloat(p.X, 'g', -1, 64))
+	sb.WriteString(" ")
+	sb.WriteString(strconv.FormatFloat(p.Y, 'g', -1, 64))
+}
+
+// ParseDsePoint parses the given Well-Known Text (WKT) representation of a point, e.g. "POINT (1 2)".
+func ParseDsePoint(wkt string) (DsePoint, error) {
+	if val, err := parseWkt(wkt, wkbPoint); err != nil {
+		return DsePoint{}, err
+	} else {
+		return val.(DsePoint), nil
+	}
+}
+
+// ParseDseLineString parses the given Well-Known Text (WKT) representation of a line string, e.g.
+// "LINESTRING (1 2, 3 4)".
+func ParseDseLineString(wkt string) (DseLineString, error) {
+	if val, err := parseWkt(wkt, wkbLineString); err != nil {
+		return DseLineString{}, err
+	} else {
+		return val.(DseLineString), nil
+	}
+}
+
+// ParseDsePolygon parses the given Well-Known Text (WKT) representation of a polygon, e.g.
+// "POLYGON ((0 0, 1 0, 1 1, 0 0))".
+func ParseDsePolygon(wkt string) (DsePolygon, error) {
+	if val, err := parseWkt(wkt, wkbPolygon); err != nil {
+		return DsePolygon{}, err
+	} else {
+		return val.(DsePolygon), nil
+	}
+}
+
+var errWktEmptyPoint = errors.New("empty points are not supported")
+
+func parseWkt(wkt string, geometryType uint32) (val interface{}, err error) {
+	p := &wktParser{input: wkt}
+	var keyword string
+	switch geometryType {
+	case wkbPoint:
+		keyword = "POINT"
+	case wkbLineString:
+		keyword = "LINESTRING"
+	default:
+		keyword = "POLYGON"
+	}
+	if err = p.expectKeyword(keyword); err == nil {
+		empty := p.acceptKeyword("EMPTY")
+		switch geometryType {
+		case wkbPoint:
+			if empty {
+				err = errWktEmptyPoint
+			} else if err = p.expect('('); err == nil {
+				var point DsePoint
+				if point, err = p.parsePoint(); err == nil {
+					if err = p.expect(')'); err == nil {
+						val = point
+					}
+				}
+			}
+		case wkbLineString:
+			lineString := DseLineString{}
+			if !empty {
+				lineString.Points, err = p.parsePoints()
+			}
+			val = lineString
+		default:
+			polygon := DsePolygon{}
+			if !empty {
+				polygon.Rings, err = p.parseRings()
+			}
+			val = polygon
+		}
+	}
+	if err == nil {
+		p.skipSpaces()
+		if p.pos != len(p.input) {
+			err = fmt.Errorf("unexpected trailing input at position %d", p.pos)
+		}
+	}
+	if err != nil {
+		return nil, errCannotParseString(wkt, err)
+	}
+	return val, nil
+}
+
+type wktParser struct {
+	input string
+	pos   int
+}
+
+func (p *wktParser) parseRings() ([][]DsePoint, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	var rings [][]DsePoint
+	for {
+		ring, err := p.parsePoints()
+		if err != nil {
+			return nil, err
+		}
+		rings = append(rings, ring)
+		if !p.accept(',') {
+			break
+		}
+	}
+	return rings, p.expect(')')
+}
+
+func (p *wktParser) parsePoints() ([]DsePoint, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	var points []DsePoint
+	for {
+		point, err := p.parsePoint()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+		if !p.accept(',') {
+			break
+		}
+	}
+	return points, p.expect(')')
+}
+
+func (p *wktParser) parsePoint() (point DsePoint, err error) {
+	if point.X, err = p.parseNumber(); err == nil {
+		point.Y, err = p.parseNumber()
+	}
+	return
+}
+
+func (p *wktParser) parseNumber() (float64, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte("0123456789+-.eE", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, fmt.Errorf("expecting number at position %d", start)
+	}
+	return strconv.ParseFloat(p.input[start:p.pos], 64)
+}
+
+func (p *wktParser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return fmt.Errorf("expecting %v at position %d", keyword, p.pos)
+	}
+	return nil
+}
+
+func (p *wktParser) acceptKeyword(keyword string) bool {
+	p.skipSpaces()
+	if end := p.pos + len(keyword); end <= len(p.input) && strings.EqualFold(p.input[p.pos:end], keyword) {
+		p.pos = end
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) expect(c byte) error {
+	if !p.accept(c) {
+		return fmt.Errorf("expecting '%c' at position %d", c, p.pos)
+	}
+	return nil
+}
+
+func (p *wktParser) accept(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) skipSpaces() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t' || p.input[p.pos] == '\n' || p.input[p.pos] == '\r') {
+		p.pos++
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+var (
+	pointOneTwo = DsePoint{X: 1, Y: 2}
+	lineString  = DseLineString{Points: []DsePoint{{X: 1, Y: 2}, {X: 3, Y: 4}}}
+	polygon     = DsePolygon{Rings: [][]DsePoint{{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 0}}}}
+)
+
+var (
+	pointOneTwoLE = []byte{
+		1,          // little endian
+		1, 0, 0, 0, // point
+		0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // 1.0
+		0, 0, 0, 0, 0, 0, 0, 0x40, // 2.0
+	}
+	pointOneTwoBE = []byte{
+		0,          // big endian
+		0, 0, 0, 1, // point
+		0x3f, 0xf0, 0, 0, 0, 0, 0, 0, // 1.0
+		0x40, 0, 0, 0, 0, 0, 0, 0, // 2.0
+	}
+	lineStringLE = []byte{
+		1,          // little endian
+		2, 0, 0, 0, // line string
+		2, 0, 0, 0, // num points
+		0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // 1.0
+		0, 0, 0, 0, 0, 0, 0, 0x40, // 2.0
+		0, 0, 0, 0, 0, 0, 0x08, 0x40, // 3.0
+		0, 0, 0, 0, 0, 0, 0x10, 0x40, // 4.0
+	}
+	lineStringBE = []byte{
+		0,          // big endian
+		0, 0, 0, 2, // line string
+		0, 0, 0, 2, // num points
+		0x3f, 0xf0, 0, 0, 0, 0, 0, 0, // 1.0
+		0x40, 0, 0, 0, 0, 0, 0, 0, // 2.0
+		0x40, 0x08, 0, 0, 0, 0, 0, 0, // 3.0
+		0x40, 0x10, 0, 0, 0, 0, 0, 0, // 4.0
+	}
+	polygonLE = []byte{
+		1,          // little endian
+		3, 0, 0, 0, // polygon
+		1, 0, 0, 0, // num rings
+		4, 0, 0, 0, // num points
+		0, 0, 0, 0, 0, 0, 0, 0, // 0.0
+		0, 0, 0, 0, 0, 0, 0, 0, // 0.0
+		0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // 1.0
+		0, 0, 0, 0, 0, 0, 0, 0, // 0.0
+		0, 0, 0, 0, 0, 0, 0, 0, // 0.0
+		0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // 1.0
+		0, 0, 0, 0, 0, 0, 0, 0, // 0.0
+		0, 0, 0, 0, 0, 0, 0, 0, // 0.0
+	}
+)
+
+func Test_geoCodec_DataType(t *testing.T) {
+	assert.Equal(t, datatype.Point, Point.DataType())
+	assert.Equal(t, datatype.LineString, LineString.DataType())
+	assert.Equal(t, datatype.Polygon, Polygon.DataType())
+	for _, codec := range []Codec{Point, LineString, Polygon} {
+		actual, err := NewCodec(datatype.NewCustom(codec.DataType().(*datatype.Custom).ClassName))
+		require.NoError(t, err)
+		assert.Equal(t, codec, actual)
+	}
+	goType, err := PreferredGoType(datatype.Point)
+	require.NoError(t, err)
+	assert.Equal(t, typeOfDsePoint, goType)
+}
+
+func Test_geoCodec_Encode(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			tests := []struct {
+				name     string
+				codec    Codec
+				source   interface{}
+				expected []byte
+				err      string
+			}{
+				{"nil", Point, nil, nil, ""},
+				{"nil pointer", Point, (*DsePoint)(nil), nil, ""},
+				{"point", Point, pointOneTwo, pointOneTwoLE, ""},
+				{"point pointer", Point, &pointOneTwo, pointOneTwoLE, ""},
+				{"point wkt", Point, "POINT (1 2)", pointOneTwoLE, ""},
+				{"line string", LineString, lineString, lineStringLE, ""},
+				{"line string wkt", LineString, "linestring(1 2,3 4)", lineStringLE, ""},
+				{"polygon", Polygon, polygon, polygonLE, ""},
+				{"polygon wkt", Polygon, stringPtr("POLYGON ((0 0, 1 0, 0 1, 0 0))"), polygonLE, ""},
+				{"wrong geometry", Point, lineString, nil, "cannot convert from datacodec.DseLineString to datacodec.DsePoint: conversion not supported"},
+				{"wrong wkt", Point, "LINESTRING (1 2)", nil, "cannot parse 'LINESTRING (1 2)': expecting POINT at position 0"},
+				{"empty point", Point, "POINT EMPTY", nil, "cannot parse 'POINT EMPTY': empty points are not supported"},
+				{"wrong type", Point, 123, nil, "conversion not supported"},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					actual, err := tt.codec.Encode(tt.source, version)
+					assert.Equal(t, tt.expected, actual)
+					if tt.err == "" {
+						assert.NoError(t, err)
+					} else {
+						assert.Contains(t, err.Error(), tt.err)
+					}
+				})
+			}
+		})
+	}
+}
+
+func Test_geoCodec_Decode(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			t.Run("point little endian", func(t *testing.T) {
+				var dest DsePoint
+				wasNull, err := Point.Decode(pointOneTwoLE, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, pointOneTwo, dest)
+			})
+			t.Run("point big endian", func(t *testing.T) {
+				var dest DsePoint
+				wasNull, err := Point.Decode(pointOneTwoBE, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, pointOneTwo, dest)
+			})
+			t.Run("point wkt", func(t *testing.T) {
+				var dest string
+				wasNull, err := Point.Decode(pointOneTwoLE, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, "POINT (1 2)", dest)
+			})
+			t.Run("line string big endian", func(t *testing.T) {
+				var dest DseLineString
+				wasNull, err := LineString.Decode(lineStringBE, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, lineString, dest)
+			})
+			t.Run("polygon interface", func(t *testing.T) {
+				var dest interface{}
+				wasNull, err := Polygon.Decode(polygonLE, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, polygon, dest)
+			})
+			t.Run("null", func(t *testing.T) {
+				dest := pointOneTwo
+				wasNull, err := Point.Decode(nil, &dest, version)
+				assert.NoError(t, err)
+				assert.True(t, wasNull)
+				assert.Equal(t, DsePoint{}, dest)
+			})
+			t.Run("wrong geometry type", func(t *testing.T) {
+				var dest DsePoint
+				_, err := Point.Decode(lineStringLE, &dest, version)
+				assert.Contains(t, err.Error(), "expected geometry type 1, got: 2")
+			})
+			t.Run("wrong destination", func(t *testing.T) {
+				var dest DseLineString
+				_, err := Point.Decode(pointOneTwoLE, &dest, version)
+				assert.Contains(t, err.Error(), "cannot convert from datacodec.DsePoint to *datacodec.DseLineString: conversion not supported")
+			})
+			t.Run("invalid byte order", func(t *testing.T) {
+				var dest DsePoint
+				_, err := Point.Decode([]byte{2, 1, 0, 0, 0}, &dest, version)
+				assert.Contains(t, err.Error(), "invalid byte order: 2")
+			})
+			t.Run("truncated", func(t *testing.T) {
+				var dest DseLineString
+				_, err := LineString.Decode(lineStringLE[:20], &dest, version)
+				assert.Contains(t, err.Error(), "expected at least 32 bytes but got: 11")
+			})
+			t.Run("bytes remaining", func(t *testing.T) {
+				var dest DsePoint
+				_, err := Point.Decode(append(pointOneTwoLE, 0), &dest, version)
+				assert.Contains(t, err.Error(), "source was not fully read: bytes total: 22, read: 21, remaining: 1")
+			})
+		})
+	}
+}
+
+func TestDseGeometryWkt(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{ String() string }
+		expected string
+		parse    func(string) (interface{}, error)
+	}{
+		{"point", DsePoint{X: -1.5, Y: 2e10}, "POINT (-1.5 2e+10)", func(s string) (interface{}, error) { return ParseDsePoint(s) }},
+		{"line string", lineString, "LINESTRING (1 2, 3 4)", func(s string) (interface{}, error) { return ParseDseLineString(s) }},
+		{"line string empty", DseLineString{}, "LINESTRING EMPTY", func(s string) (interface{}, error) { return ParseDseLineString(s) }},
+		{"polygon", polygon, "POLYGON ((0 0, 1 0, 0 1, 0 0))", func(s string) (interface{}, error) { return ParseDsePolygon(s) }},
+		{
+			"polygon with hole",
+			DsePolygon{Rings: [][]DsePoint{
+				{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}, {X: 0, Y: 0}},
+				{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 1}},
+			}},
+			"POLYGON ((0 0, 10 0, 0 10, 0 0), (1 1, 2 1, 1 2, 1 1))",
+			func(s string) (interface{}, error) { return ParseDsePolygon(s) },
+		},
+		{"polygon empty", DsePolygon{}, "POLYGON EMPTY", func(s string) (interface{}, error) { return ParseDsePolygon(s) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.value.String())
+			actual, err := tt.parse(tt.expected)
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, actual)
+		})
+	}
+	t.Run("trailing input", func(t *testing.T) {
+		_, err := ParseDsePoint("POINT (1 2) foo")
+		assert.EqualError(t, err, "cannot parse 'POINT (1 2) foo': unexpected trailing input at position 12")
+	})
+	t.Run("missing coordinate", func(t *testing.T) {
+		_, err := ParseDsePoint("POINT (1)")
+		assert.EqualError(t, err, "cannot parse 'POINT (1)': expecting number at position 8")
+	})
+}
@@ -38,7 +38,7 @@ func (c *inetCodec) Encode(source interface{}, version primitive.ProtocolVersion
 		dest, err = writeInet(val)
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
@@ -49,7 +49,7 @@ func (c *inetCodec) Decode(source []byte, dest interface{}, version primitive.Pr
 		err = convertFromIP(val, wasNull, dest)
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -39,18 +39,28 @@ func (c *intCodec) Encode(source interface{}, version primitive.ProtocolVersion)
 		dest = writeInt32(val)
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *intCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	var val int32
+	if val, wasNil, err = convertToInt32(source); err != nil {
+		return appendEncoded(c, dest, source, version)
+	} else if !wasNil {
+		dest = appendInt32(dest, val)
+	}
+	return dest, wasNil, nil
+}
+
 func (c *intCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	var val int32
 	if val, wasNull, err = readInt32(source); err == nil {
 		err = convertFromInt32(val, wasNull, dest)
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -241,6 +251,10 @@ func convertFromInt32(val int32, wasNull bool, dest interface{}) (err error) {
 	return
 }
 
+func appendInt32(dest []byte, val int32) []byte {
+	return append(dest, byte(val>>24), byte(val>>16), byte(val>>8), byte(val))
+}
+
 func writeInt32(val int32) (dest []byte) {
 	dest = make([]byte, primitive.LengthOfInt)
 	binary.BigEndian.PutUint32(dest, uint32(val))
@@ -0,0 +1,510 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// timestampJSONLayout is the layout used by Cassandra to format timestamps in JSON; timestamps are always formatted in
+// UTC.
+const timestampJSONLayout = "2006-01-02 15:04:05.000Z0700"
+
+// MarshalJSON converts the given encoded value of the given CQL type to JSON, using the same format as Cassandra's
+// SELECT JSON statements:
+//
+//   - NULLs are converted to null;
+//   - numeric CQL types are converted to JSON numbers, except NaN and infinite floats and doubles, which are converted
+//     to null, since JSON does not support these values;
+//   - booleans are converted to JSON booleans;
+//   - all other simple CQL types are converted to JSON strings: blobs are formatted as hexadecimal strings, e.g.
+//     "0xcafe", timestamps are formatted in UTC, e.g. "2021-01-01 12:34:56.789Z", dates, times, durations, uuids and
+//     inet addresses are formatted as in CQL literals, e.g. "2021-01-01", "12:34:56.789000000", "1h30m";
+//   - lists, sets, tuples and vectors are converted to JSON arrays;
+//   - maps are converted to JSON objects; keys that are not JSON strings are converted to their JSON representation,
+//     then to a JSON string, e.g. the int key 1 becomes "1";
+//   - user-defined types are converted to JSON objects; field names that are not in lower case are double-quoted, as
+//     they would be in CQL, e.g. the field name MyField becomes "\"MyField\"".
+//
+// Values are decoded with the codecs of the DefaultRegistry; see Registry.ToJSON.
+func MarshalJSON(column message.Column, dt datatype.DataType, version primitive.ProtocolVersion) ([]byte, error) {
+	return DefaultRegistry.ToJSON(column, dt, version)
+}
+
+// ToJSON converts the given encoded value of the given CQL type to JSON, see the package-level MarshalJSON
+// function. Values of simple CQL types are decoded with the codecs created by this registry; codecs registered for
+// such types must decode values to one of the Go types produced by the built-in codecs, or to a fmt.Stringer, in
+// which case the value is converted to a JSON string.
+func (r *Registry) ToJSON(
+	column message.Column,
+	dt datatype.DataType,
+	version primitive.ProtocolVersion,
+) ([]byte, error) {
+	if dt == nil {
+		return nil, ErrNilDataType
+	}
+	buf := &bytes.Buffer{}
+	w := &decodeWalker{registry: r, version: version, formatter: &jsonFormatter{buf: buf}, verb: "marshal"}
+	if err := w.walk(column, dt); err != nil {
+		return nil, fmt.Errorf("cannot marshal CQL %s to JSON: %w", dt, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON converts the given JSON value to an encoded value of the given CQL type, using the same rules as
+// Cassandra's INSERT JSON statements. This function accepts all the JSON values produced by MarshalJSON, plus a few
+// alternative forms:
+//
+//   - numeric CQL types and booleans can also be JSON strings, e.g. "1" or "true";
+//   - timestamps can also be JSON numbers (milliseconds since the Unix epoch), or strings in any of the formats
+//     accepted by ParseLiteral;
+//   - tuples can have fewer elements than the tuple type, in which case the missing elements are NULL;
+//   - user-defined type fields can be omitted, in which case they are NULL; field names that are not double-quoted are
+//     case-insensitive.
+//
+// The JSON null is converted to a nil column. Values are encoded with the codecs of the DefaultRegistry; see
+// Registry.FromJSON.
+func UnmarshalJSON(data []byte, dt datatype.DataType, version primitive.ProtocolVersion) (message.Column, error) {
+	return DefaultRegistry.FromJSON(data, dt, version)
+}
+
+// FromJSON converts the given JSON value to an encoded value of the given CQL type, see the package-level
+// UnmarshalJSON function. Values of simple CQL types are encoded with the codecs created by this registry; codecs
+// registered for such types must accept the Go types accepted by the built-in codecs, e.g. []byte for custom types.
+func (r *Registry) FromJSON(
+	data []byte,
+	dt datatype.DataType,
+	version primitive.ProtocolVersion,
+) (message.Column, error) {
+	if dt == nil {
+		return nil, ErrNilDataType
+	}
+	value, err := decodeJSON(data)
+	var column []byte
+	var null bool
+	if err == nil {
+		w := &encodeWalker{registry: r, version: version, parser: &jsonParser{value}}
+		column, null, err = w.walk(nil, dt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal CQL %s from JSON: %w", dt, err)
+	} else if null {
+		return nil, nil
+	} else if column == nil {
+		// non-null empty value, e.g. an empty string
+		column = []byte{}
+	}
+	return column, nil
+}
+
+// RowsToJSON converts all the rows of the given result to JSON objects, as they would be returned by a SELECT JSON
+// statement: each row is converted to a JSON object with one entry per column, in the order of the result columns.
+// Column names are formatted as user-defined type field names, see MarshalJSON. An error is returned if the result
+// metadata has no column specs. Values are decoded with the codecs of the DefaultRegistry.
+func RowsToJSON(result *message.RowsResult, version primitive.ProtocolVersion) ([]json.RawMessage, error) {
+	return DefaultRegistry.RowsToJSON(result, version)
+}
+
+// RowsToJSON converts all the rows of the given result to JSON objects, see the package-level RowsToJSON function.
+// Values are decoded with the codecs created by this registry, see Registry.ToJSON.
+func (r *Registry) RowsToJSON(
+	result *message.RowsResult,
+	version primitive.ProtocolVersion,
+) ([]json.RawMessage, error) {
+	if result == nil || result.Metadata == nil || len(result.Metadata.Columns) == 0 {
+		return nil, errors.New("cannot convert rows to JSON: result has no column specs")
+	}
+	columns := result.Metadata.Columns
+	rows := make([]json.RawMessage, len(result.Data))
+	for i, row := range result.Data {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf(
+				"cannot convert row %d to JSON: expected row of %d columns, got: %d", i, len(columns), len(row))
+		}
+		buf := &bytes.Buffer{}
+		w := &decodeWalker{registry: r, version: version, formatter: &jsonFormatter{buf: buf}, verb: "marshal"}
+		buf.WriteByte('{')
+		for j, column := range columns {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			writeJSONFieldName(buf, column.Name)
+			buf.WriteString(": ")
+			if err := w.walk(row[j], column.Type); err != nil {
+				return nil, fmt.Errorf("cannot convert row %d to JSON: %w", i, errCannotMarshalColumn(j, column.Name, err))
+			}
+		}
+		buf.WriteByte('}')
+		rows[i] = buf.Bytes()
+	}
+	return rows, nil
+}
+
+// jsonFormatter writes the values visited by a decodeWalker as JSON.
+type jsonFormatter struct {
+	buf *bytes.Buffer
+	// keyStarts holds the offsets in buf of the map keys being written; keys can themselves contain maps.
+	keyStarts []int
+}
+
+func (f *jsonFormatter) writeNull() {
+	f.buf.WriteString("null")
+}
+
+func (f *jsonFormatter) writeSimple(value interface{}, dt datatype.DataType) error {
+	switch v := value.(type) {
+	case string:
+		writeJSONString(f.buf, v)
+	case int64:
+		f.buf.WriteString(strconv.FormatInt(v, 10))
+	case int32:
+		f.buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int16:
+		f.buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int8:
+		f.buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case *big.Int:
+		f.buf.WriteString(v.String())
+	case bool:
+		f.buf.WriteString(strconv.FormatBool(v))
+	case float64:
+		writeJSONFloat(f.buf, v, 64)
+	case float32:
+		writeJSONFloat(f.buf, float64(v), 32)
+	case CqlDecimal:
+		f.buf.WriteString(formatDecimalLiteral(v))
+	case []byte:
+		writeJSONString(f.buf, "0x"+hex.EncodeToString(v))
+	case time.Time:
+		if dt.Code() == primitive.DataTypeCodeDate {
+			writeJSONString(f.buf, v.Format(DateLayoutDefault))
+		} else {
+			writeJSONString(f.buf, v.UTC().Format(timestampJSONLayout))
+		}
+	case time.Duration:
+		writeJSONString(f.buf, formatTimeLiteral(v))
+	case primitive.UUID:
+		writeJSONString(f.buf, v.String())
+	case net.IP:
+		writeJSONString(f.buf, v.String())
+	case CqlDuration:
+		literal, err := v.formatUnits()
+		if err != nil {
+			return err
+		}
+		writeJSONString(f.buf, literal)
+	case fmt.Stringer:
+		// DSE geospatial types and date ranges
+		writeJSONString(f.buf, v.String())
+	default:
+		return fmt.Errorf("cannot marshal value of type %T", value)
+	}
+	return nil
+}
+
+func (f *jsonFormatter) open(kind containerKind) {
+	if kind == containerMap || kind == containerUdt {
+		f.buf.WriteByte('{')
+	} else {
+		f.buf.WriteByte('[')
+	}
+}
+
+func (f *jsonFormatter) close(kind containerKind) {
+	if kind == containerMap || kind == containerUdt {
+		f.buf.WriteByte('}')
+	} else {
+		f.buf.WriteByte(']')
+	}
+}
+
+func (f *jsonFormatter) writeSeparator() {
+	f.buf.WriteString(", ")
+}
+
+func (f *jsonFormatter) startMapKey() {
+	f.keyStarts = append(f.keyStarts, f.buf.Len())
+}
+
+// endMapKey converts the map key just written to a JSON string, if it is not one already.
+func (f *jsonFormatter) endMapKey() {
+	start := f.keyStarts[len(f.keyStarts)-1]
+	f.keyStarts = f.keyStarts[:len(f.keyStarts)-1]
+	if key := f.buf.Bytes()[start:]; len(key) == 0 || key[0] != '"' {
+		s := string(key)
+		f.buf.Truncate(start)
+		writeJSONString(f.buf, s)
+	}
+	f.buf.WriteString(": ")
+}
+
+func (f *jsonFormatter) writeFieldName(name string) {
+	writeJSONFieldName(f.buf, name)
+	f.buf.WriteString(": ")
+}
+
+// writeJSONString writes the given string as a JSON string. Contrary to json.Marshal, HTML characters are not
+// escaped. Invalid UTF-8 sequences are replaced with the Unicode replacement character.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	const hexDigits = "0123456789abcdef"
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r < 0x20:
+			buf.WriteString(`\u00`)
+			buf.WriteByte(hexDigits[r>>4])
+			buf.WriteByte(hexDigits[r&0xF])
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// writeJSONFieldName writes the given user-defined type field name or column name as a JSON string. Names that are
+// not in lower case are double-quoted first, as Cassandra does.
+func writeJSONFieldName(buf *bytes.Buffer, name string) {
+	if name != strings.ToLower(name) {
+		name = `"` + name + `"`
+	}
+	writeJSONString(buf, name)
+}
+
+func writeJSONFloat(buf *bytes.Buffer, f float64, bitSize int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		buf.WriteString("null")
+	} else {
+		buf.WriteString(strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+}
+
+// decodeJSON decodes the given JSON value; JSON numbers are decoded as json.Number, to avoid any loss of precision.
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	} else if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected trailing data")
+	}
+	return value, nil
+}
+
+// jsonParser reads JSON values, as decoded by decodeJSON, on behalf of an encodeWalker.
+type jsonParser struct {
+	// value is the JSON value to parse next.
+	value interface{}
+}
+
+func (p *jsonParser) parseNull() (bool, error) {
+	return p.value == nil, nil
+}
+
+func (p *jsonParser) parseSimple(dt datatype.DataType, encode func(value interface{}) error) error {
+	// JSON values are converted the same way as CQL literals: JSON strings are converted as quoted literals for
+	// types that require them, e.g. varchar, and as unquoted literals for other types, e.g. int or uuid.
+	var converted interface{}
+	var err error
+	switch v := p.value.(type) {
+	case string:
+		converted, err = convertLiteralToken(v, jsonStringIsQuoted(dt), dt)
+	case json.Number:
+		converted, err = convertLiteralToken(v.String(), false, dt)
+	case bool:
+		converted, err = convertLiteralToken(strconv.FormatBool(v), false, dt)
+	default:
+		err = fmt.Errorf("unexpected JSON %s", jsonTypeName(p.value))
+	}
+	if err == nil {
+		err = encode(converted)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s value: %w", dt, err)
+	}
+	return nil
+}
+
+func (p *jsonParser) parseElements(kind containerKind, maxSize int, parseElement func(i int) error) error {
+	elements, ok := p.value.([]interface{})
+	if !ok {
+		return fmt.Errorf("expecting JSON array, got JSON %s", jsonTypeName(p.value))
+	} else if maxSize >= 0 && len(elements) > maxSize {
+		return fmt.Errorf("too many %s elements, expecting %d, got: %d", kind, maxSize, len(elements))
+	}
+	for i, element := range elements {
+		p.value = element
+		if err := parseElement(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *jsonParser) parseEntries(mapType *datatype.Map, parseKey, parseValue func(i int) error) error {
+	entries, ok := p.value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expecting JSON object, got JSON %s", jsonTypeName(p.value))
+	}
+	// sort keys to produce a deterministic output
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	stringKeys := marshalsToJSONString(mapType.KeyType)
+	for i, key := range keys {
+		// keys of types that are not marshaled as JSON strings are JSON values themselves, e.g. "1" or "[1, 2]"
+		p.value = key
+		if !stringKeys {
+			var err error
+			if p.value, err = decodeJSON([]byte(key)); err != nil {
+				return errCannotEncodeMapKey(i, err)
+			}
+		}
+		if err := parseKey(i); err != nil {
+			return err
+		}
+		p.value = entries[key]
+		if err := parseValue(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *jsonParser) parseFields(udtType *datatype.UserDefined, parseField func(index int) error) error {
+	entries, ok := p.value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expecting JSON object, got JSON %s", jsonTypeName(p.value))
+	}
+	fields := make([]interface{}, len(udtType.FieldNames))
+	found := make([]bool, len(udtType.FieldNames))
+	for key, field := range entries {
+		// double-quoted names are case-sensitive, other names are case-insensitive
+		name := strings.ToLower(key)
+		if len(key) >= 2 && strings.HasPrefix(key, `"`) && strings.HasSuffix(key, `"`) {
+			name = key[1 : len(key)-1]
+		}
+		index := -1
+		for i, fieldName := range udtType.FieldNames {
+			if fieldName == name {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return fmt.Errorf("unknown field %s", key)
+		}
+		fields[index], found[index] = field, true
+	}
+	// fields are parsed in the order of the user-defined type, to report errors deterministically
+	for index, field := range fields {
+		if found[index] {
+			p.value = field
+			if err := parseField(index); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonStringIsQuoted returns true if JSON strings should be converted as quoted CQL literals for the given type,
+// that is, if the CQL literals of that type are always quoted.
+func jsonStringIsQuoted(dt datatype.DataType) bool {
+	switch dt.Code() {
+	case primitive.DataTypeCodeAscii,
+		primitive.DataTypeCodeVarchar,
+		primitive.DataTypeCodeInet,
+		primitive.DataTypeCodeTimestamp,
+		primitive.DataTypeCodeDate,
+		primitive.DataTypeCodeTime:
+		return true
+	case primitive.DataTypeCodeCustom:
+		_, found := customCodecs[dt.(*datatype.Custom).ClassName]
+		return found
+	}
+	return false
+}
+
+// marshalsToJSONString returns true if values of the given type are marshaled as JSON strings.
+func marshalsToJSONString(dt datatype.DataType) bool {
+	if _, ok := asVector(dt); ok {
+		return false
+	}
+	switch dt.Code() {
+	case primitive.DataTypeCodeBigint,
+		primitive.DataTypeCodeCounter,
+		primitive.DataTypeCodeInt,
+		primitive.DataTypeCodeSmallint,
+		primitive.DataTypeCodeTinyint,
+		primitive.DataTypeCodeVarint,
+		primitive.DataTypeCodeFloat,
+		primitive.DataTypeCodeDouble,
+		primitive.DataTypeCodeDecimal,
+		primitive.DataTypeCodeBoolean,
+		primitive.DataTypeCodeList,
+		primitive.DataTypeCodeSet,
+		primitive.DataTypeCodeMap,
+		primitive.DataTypeCodeTuple,
+		primitive.DataTypeCodeUdt:
+		return false
+	}
+	return true
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,269 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// jsonTestMapBytes is the encoded map<varchar,int> {'a': 1, 'b': 2}; Go maps would be encoded in random order.
+var jsonTestMapBytes = message.Column{
+	0, 0, 0, 2,
+	0, 0, 0, 1, 'a', 0, 0, 0, 4, 0, 0, 0, 1,
+	0, 0, 0, 1, 'b', 0, 0, 0, 4, 0, 0, 0, 2,
+}
+
+func TestMarshalAndUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		dt    datatype.DataType
+		value interface{}
+		json  string
+	}{
+		{"null", datatype.Int, nil, "null"},
+		{"varchar", datatype.Varchar, "hello", `"hello"`},
+		{"varchar escaped", datatype.Varchar, "a\"b\\c\n<\x01>", `"a\"b\\c\n<\u0001>"`},
+		{"varchar empty", datatype.Varchar, "", `""`},
+		{"ascii", datatype.Ascii, "abc", `"abc"`},
+		{"bigint", datatype.Bigint, int64(math.MaxInt64), "9223372036854775807"},
+		{"counter", datatype.Counter, int64(-1), "-1"},
+		{"int", datatype.Int, int32(42), "42"},
+		{"smallint", datatype.Smallint, int16(-1), "-1"},
+		{"tinyint", datatype.Tinyint, int8(127), "127"},
+		{"varint", datatype.Varint, literalDecimal, "-123456789012345678901234567890"},
+		{"boolean", datatype.Boolean, false, "false"},
+		{"double", datatype.Double, -1.25, "-1.25"},
+		{"float", datatype.Float, float32(1e20), "1e+20"},
+		{"decimal", datatype.Decimal, CqlDecimal{Unscaled: big.NewInt(31415), Scale: 4}, "3.1415"},
+		{"blob", datatype.Blob, []byte{0xca, 0xfe}, `"0xcafe"`},
+		{"uuid", datatype.Uuid, literalUuid, `"c0d1d21e-bb01-4196-86db-bc317bc1796a"`},
+		{"timeuuid", datatype.Timeuuid, literalUuid, `"c0d1d21e-bb01-4196-86db-bc317bc1796a"`},
+		{"inet", datatype.Inet, net.ParseIP("10.0.0.1"), `"10.0.0.1"`},
+		{"timestamp", datatype.Timestamp, literalTimestamp, `"2021-03-04 12:34:56.789Z"`},
+		{"date", datatype.Date, literalDate, `"2021-03-04"`},
+		{"time", datatype.Time, literalTimeOfDay, `"12:34:56.789000000"`},
+		{"duration", datatype.Duration, CqlDuration{Months: 1, Nanos: 90 * time.Minute}, `"1mo1h30m"`},
+		{"point", datatype.Point, DsePoint{X: 1, Y: 2}, `"POINT (1 2)"`},
+		{"list", datatype.NewList(datatype.Int), []int32{1, 2}, "[1, 2]"},
+		{"list empty", datatype.NewList(datatype.Int), []int32{}, "[]"},
+		{"set", datatype.NewSet(datatype.Varchar), []string{"a"}, `["a"]`},
+		{"map string keys", datatype.NewMap(datatype.Varchar, datatype.Int), jsonTestMapBytes, `{"a": 1, "b": 2}`},
+		{"map int keys", datatype.NewMap(datatype.Int, datatype.Boolean), map[int32]bool{1: true}, `{"1": true}`},
+		{"map uuid keys", datatype.NewMap(datatype.Uuid, datatype.Int), map[primitive.UUID]int32{literalUuid: 1}, `{"c0d1d21e-bb01-4196-86db-bc317bc1796a": 1}`},
+		{"map tuple keys", datatype.NewMap(datatype.NewTuple(datatype.Int, datatype.Varchar), datatype.Int), map[[2]interface{}]int32{{int32(1), "a"}: 1}, `{"[1, \"a\"]": 1}`},
+		{"tuple", literalTupleType, []interface{}{1, "a", nil}, `[1, "a", null]`},
+		{"udt", literalUdtType, map[string]interface{}{"street": "Main St", "zip_code": 123, "Country": "FR"}, `{"street": "Main St", "zip_code": 123, "\"Country\"": "FR"}`},
+		{"vector", datatype.NewVector(datatype.Float, 2), []float32{1, 2.5}, "[1, 2.5]"},
+		{"nested", datatype.NewMap(datatype.Varchar, datatype.NewList(datatype.Timestamp)), map[string][]time.Time{"a": {literalTimestamp}}, `{"a": ["2021-03-04 12:34:56.789Z"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := encodeLiteralTestValue(t, tt.dt, tt.value)
+			marshaled, err := MarshalJSON(encoded, tt.dt, primitive.ProtocolVersion5)
+			require.NoError(t, err)
+			assert.Equal(t, tt.json, string(marshaled))
+			assert.True(t, json.Valid(marshaled))
+			unmarshaled, err := UnmarshalJSON(marshaled, tt.dt, primitive.ProtocolVersion5)
+			require.NoError(t, err)
+			assert.Equal(t, encoded, []byte(unmarshaled))
+		})
+	}
+}
+
+func TestMarshalJSON_NaNAndInfinity(t *testing.T) {
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		marshaled, err := MarshalJSON(writeFloat64(f), datatype.Double, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, "null", string(marshaled))
+	}
+}
+
+func TestMarshalJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source []byte
+		dt     datatype.DataType
+		err    string
+	}{
+		{"nil data type", []byte{1}, nil, "data type is nil"},
+		{"wrong length", []byte{1, 2}, datatype.Int, "cannot marshal CQL int to JSON: cannot decode CQL int as *interface {}"},
+		{"element invalid", []byte{0, 0, 0, 1, 0, 0, 0, 1, 1}, datatype.NewList(datatype.Int), "cannot marshal CQL list<int> to JSON: cannot marshal element 0"},
+		{"map key invalid", []byte{0, 0, 0, 1, 0, 0, 0, 1, 1, 0, 0, 0, 1, 1}, datatype.NewMap(datatype.Int, datatype.Tinyint), "cannot marshal entry 0 key"},
+		{"udt field missing", []byte{0, 0, 0, 1, 'a'}, literalUdtType, "cannot read field 1 (zip_code)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marshaled, err := MarshalJSON(tt.source, tt.dt, primitive.ProtocolVersion5)
+			assert.Nil(t, marshaled)
+			assertErrorMessage(t, tt.err, err)
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		dt       datatype.DataType
+		expected interface{}
+	}{
+		{"null", " null ", datatype.NewList(datatype.Int), nil},
+		{"int as string", `"42"`, datatype.Int, int32(42)},
+		{"boolean as string", `"TRUE"`, datatype.Boolean, true},
+		{"double as string", `"NaN"`, datatype.Double, math.NaN()},
+		{"decimal exponent", "1.5e3", datatype.Decimal, CqlDecimal{Unscaled: big.NewInt(15), Scale: -2}},
+		{"varint huge", "-123456789012345678901234567890", datatype.Varint, literalDecimal},
+		{"timestamp millis", "1614861296789", datatype.Timestamp, literalTimestamp},
+		{"timestamp ISO", `"2021-03-04T12:34:56.789Z"`, datatype.Timestamp, literalTimestamp},
+		{"duration weeks", `"1w"`, datatype.Duration, CqlDuration{Days: 7}},
+		{"tuple missing elements", "[1]", literalTupleType, []interface{}{int32(1), nil, nil}},
+		{"udt case-insensitive", `{"STREET": "Main St"}`, literalUdtType, map[string]interface{}{"street": "Main St"}},
+		{"udt case-sensitive", `{"\"Country\"": "FR"}`, literalUdtType, map[string]interface{}{"Country": "FR"}},
+		{"map keys sorted", `{"b": 2, "a": 1}`, datatype.NewMap(datatype.Varchar, datatype.Int), jsonTestMapBytes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unmarshaled, err := UnmarshalJSON([]byte(tt.json), tt.dt, primitive.ProtocolVersion5)
+			require.NoError(t, err)
+			var expected []byte
+			if tt.expected != nil {
+				expected = encodeLiteralTestValue(t, tt.dt, tt.expected)
+			}
+			assert.Equal(t, expected, []byte(unmarshaled))
+		})
+	}
+}
+
+func TestUnmarshalJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		dt   datatype.DataType
+		err  string
+	}{
+		{"nil data type", "1", nil, "data type is nil"},
+		{"invalid JSON", "[1,", datatype.NewList(datatype.Int), "cannot unmarshal CQL list<int> from JSON: unexpected EOF"},
+		{"trailing data", "1 2", datatype.Int, "unexpected trailing data"},
+		{"varchar as number", "1", datatype.Varchar, "invalid varchar value: expecting string literal"},
+		{"int as boolean", "true", datatype.Int, "invalid int value: cannot encode string as CQL int"},
+		{"int as array", "[1]", datatype.Int, "invalid int value: unexpected JSON array"},
+		{"int out of range", "2147483648", datatype.Int, "invalid int value"},
+		{"blob without prefix", `"cafe"`, datatype.Blob, "expecting blob literal"},
+		{"list as object", "{}", datatype.NewList(datatype.Int), "expecting JSON array, got JSON object"},
+		{"list element invalid", `[1, "a"]`, datatype.NewList(datatype.Int), "cannot encode element 1: invalid int value"},
+		{"map as array", "[]", datatype.NewMap(datatype.Int, datatype.Int), "expecting JSON object, got JSON array"},
+		{"map key invalid", `{"a": 1}`, datatype.NewMap(datatype.Int, datatype.Int), "cannot encode entry 0 key"},
+		{"tuple too many", "[1, \"a\", true, 1]", literalTupleType, "too many tuple elements, expecting 3, got: 4"},
+		{"udt unknown field", `{"city": "Paris"}`, literalUdtType, "unknown field city"},
+		{"udt field invalid", `{"zip_code": "abc"}`, literalUdtType, "cannot encode field 1 (zip_code): invalid int value"},
+		{"vector wrong size", "[1]", datatype.NewVector(datatype.Int, 2), "expected vector of 2 elements, got: 1"},
+		{"vector null element", "[1, null]", datatype.NewVector(datatype.Int, 2), "cannot encode element 1: vector elements cannot be null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unmarshaled, err := UnmarshalJSON([]byte(tt.json), tt.dt, primitive.ProtocolVersion5)
+			assert.Nil(t, unmarshaled)
+			assertErrorMessage(t, tt.err, err)
+		})
+	}
+}
+
+func TestRegistry_ToAndFromJSON(t *testing.T) {
+	count := 0
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(countingCodec{Int, &count}))
+	listType := datatype.NewList(datatype.Int)
+	encoded := encodeLiteralTestValue(t, listType, []int32{1, 2})
+	marshaled, err := registry.ToJSON(encoded, listType, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	assert.Equal(t, "[1, 2]", string(marshaled))
+	assert.Equal(t, 2, count)
+	unmarshaled, err := registry.FromJSON(marshaled, listType, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, []byte(unmarshaled))
+	assert.Equal(t, 4, count)
+	result := &message.RowsResult{
+		Metadata: &message.RowsMetadata{
+			ColumnCount: 1,
+			Columns:     []*message.ColumnMetadata{{Name: "id", Type: datatype.Int}},
+		},
+		Data: message.RowSet{{writeInt32(1)}},
+	}
+	rows, err := registry.RowsToJSON(result, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id": 1}`)}, rows)
+	assert.Equal(t, 5, count)
+}
+
+func TestRowsToJSON(t *testing.T) {
+	result := &message.RowsResult{
+		Metadata: &message.RowsMetadata{
+			ColumnCount: 3,
+			Columns: []*message.ColumnMetadata{
+				{Keyspace: "ks1", Table: "table1", Name: "id", Index: 0, Type: datatype.Int},
+				{Keyspace: "ks1", Table: "table1", Name: "Name", Index: 1, Type: datatype.Varchar},
+				{Keyspace: "ks1", Table: "table1", Name: "tags", Index: 2, Type: datatype.NewSet(datatype.Varchar)},
+			},
+		},
+		Data: message.RowSet{
+			{writeInt32(1), []byte("alice"), encodeLiteralTestValue(t, datatype.NewSet(datatype.Varchar), []string{"a", "b"})},
+			{writeInt32(2), nil, nil},
+		},
+	}
+	rows, err := RowsToJSON(result, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, `{"id": 1, "\"Name\"": "alice", "tags": ["a", "b"]}`, string(rows[0]))
+	assert.Equal(t, `{"id": 2, "\"Name\"": null, "tags": null}`, string(rows[1]))
+	marshaled, err := json.Marshal(rows)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"id":1,"\"Name\"":"alice","tags":["a","b"]},{"id":2,"\"Name\"":null,"tags":null}]`, string(marshaled))
+}
+
+func TestRowsToJSON_Errors(t *testing.T) {
+	metadata := &message.RowsMetadata{
+		ColumnCount: 1,
+		Columns:     []*message.ColumnMetadata{{Name: "id", Type: datatype.Int}},
+	}
+	tests := []struct {
+		name   string
+		result *message.RowsResult
+		err    string
+	}{
+		{"nil", nil, "cannot convert rows to JSON: result has no column specs"},
+		{"no column specs", &message.RowsResult{Metadata: &message.RowsMetadata{ColumnCount: 1}}, "cannot convert rows to JSON: result has no column specs"},
+		{"wrong row length", &message.RowsResult{Metadata: metadata, Data: message.RowSet{{}}}, "cannot convert row 0 to JSON: expected row of 1 columns, got: 0"},
+		{"invalid column", &message.RowsResult{Metadata: metadata, Data: message.RowSet{{{1}}}}, "cannot convert row 0 to JSON: cannot marshal column 0 (id): cannot decode CQL int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := RowsToJSON(tt.result, primitive.ProtocolVersion5)
+			assert.Nil(t, rows)
+			assertErrorMessage(t, tt.err, err)
+		})
+	}
+}
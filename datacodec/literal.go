@@ -0,0 +1,614 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/internal/lexer"
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// timestampLiteralLayout is the layout used to format CQL timestamp literals; timestamps are always formatted in UTC.
+const timestampLiteralLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// timestampLiteralLayouts are the layouts accepted when parsing CQL timestamp literals. Fractional seconds are accepted
+// after the seconds field by all layouts containing seconds. Timestamps without time zone are assumed to be in UTC.
+var timestampLiteralLayouts = []string{
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04Z0700",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04Z07:00",
+	"2006-01-02 15:04Z0700",
+	"2006-01-02 15:04",
+	"2006-01-02Z07:00",
+	"2006-01-02Z0700",
+	"2006-01-02",
+}
+
+// FormatLiteral formats the given encoded value of the given CQL type as a CQL literal, that is, as it would appear in
+// a CQL statement. NULL values are formatted as NULL; strings, inet addresses, timestamps, dates and times are quoted
+// with single quotes; blobs are formatted as hexadecimal literals, e.g. 0xcafe; durations are formatted as unit
+// literals, e.g. 1y2mo3d4h5m6s; lists and vectors are formatted as [...], sets as {...}, maps as {k: v, ...}, tuples
+// as (...) and user-defined types as {field: value, ...}. Timestamps are formatted in UTC with millisecond precision,
+// e.g. '2021-01-01T12:34:56.789Z'. Values of unknown custom types are formatted as hexadecimal literals.
+//
+// Values are decoded with the codecs of the DefaultRegistry; see Registry.FormatLiteral.
+func FormatLiteral(column message.Column, dt datatype.DataType, version primitive.ProtocolVersion) (string, error) {
+	return DefaultRegistry.FormatLiteral(column, dt, version)
+}
+
+// FormatLiteral formats the given encoded value of the given CQL type as a CQL literal, see the package-level
+// FormatLiteral function. Values of simple CQL types are decoded with the codecs created by this registry; codecs
+// registered for such types must decode values to one of the Go types produced by the built-in codecs, or to a
+// fmt.Stringer, in which case the value is formatted as a quoted string.
+func (r *Registry) FormatLiteral(
+	column message.Column,
+	dt datatype.DataType,
+	version primitive.ProtocolVersion,
+) (string, error) {
+	if dt == nil {
+		return "", ErrNilDataType
+	}
+	sb := &strings.Builder{}
+	w := &decodeWalker{registry: r, version: version, formatter: &literalFormatter{sb}, verb: "format"}
+	if err := w.walk(column, dt); err != nil {
+		return "", fmt.Errorf("cannot format CQL %s literal: %w", dt, err)
+	}
+	return sb.String(), nil
+}
+
+// ParseLiteral parses the given CQL literal as a value of the given CQL type, and returns the encoded value. The
+// literal NULL, case-insensitive, is encoded as a nil column. This function accepts all the literals produced by
+// FormatLiteral, plus a few alternative forms accepted by Cassandra:
+//
+//   - strings can also be enclosed in double dollar signs, e.g. $$it's$$;
+//   - timestamps can also be integers (milliseconds since the Unix epoch), or strings with a space instead of the 'T'
+//     separator, with or without seconds and with or without a time zone (UTC is assumed if absent);
+//   - dates can also be integers (days since the Unix epoch, shifted by 2^31, as in the encoded form);
+//   - times can also be integers (nanoseconds since midnight);
+//   - uuids can also be quoted;
+//   - durations can also use the w (weeks) unit, or the ISO 8601 formats accepted by ParseCqlDuration, e.g. P1DT2H
+//     or P0001-02-03T04:05:06;
+//   - tuples can have fewer elements than the tuple type, in which case the missing elements are NULL;
+//   - user-defined type fields can appear in any order or be omitted, in which case they are NULL.
+//
+// Values are encoded with the codecs of the DefaultRegistry; see Registry.ParseLiteral.
+func ParseLiteral(literal string, dt datatype.DataType, version primitive.ProtocolVersion) (message.Column, error) {
+	return DefaultRegistry.ParseLiteral(literal, dt, version)
+}
+
+// ParseLiteral parses the given CQL literal as a value of the given CQL type, see the package-level ParseLiteral
+// function. Values of simple CQL types are encoded with the codecs created by this registry; codecs registered for
+// such types must accept the Go types accepted by the built-in codecs, e.g. []byte for custom types.
+func (r *Registry) ParseLiteral(
+	literal string,
+	dt datatype.DataType,
+	version primitive.ProtocolVersion,
+) (message.Column, error) {
+	if dt == nil {
+		return nil, ErrNilDataType
+	}
+	p := &literalParser{Lexer: lexer.Lexer{Input: literal}}
+	w := &encodeWalker{registry: r, version: version, parser: p}
+	column, null, err := w.walk(nil, dt)
+	if err == nil {
+		p.SkipSpaces()
+		if !p.EOF() {
+			err = p.Errorf("unexpected trailing input")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse CQL %s literal %q: %w", dt, literal, err)
+	} else if null {
+		return nil, nil
+	} else if column == nil {
+		// non-null empty value, e.g. an empty string
+		column = []byte{}
+	}
+	return column, nil
+}
+
+// literalFormatter writes the values visited by a decodeWalker as CQL literals.
+type literalFormatter struct {
+	sb *strings.Builder
+}
+
+func (f *literalFormatter) writeNull() {
+	f.sb.WriteString("NULL")
+}
+
+func (f *literalFormatter) writeSimple(value interface{}, dt datatype.DataType) error {
+	switch v := value.(type) {
+	case string:
+		writeQuotedLiteral(f.sb, v, '\'')
+	case int64:
+		f.sb.WriteString(strconv.FormatInt(v, 10))
+	case int32:
+		f.sb.WriteString(strconv.FormatInt(int64(v), 10))
+	case int16:
+		f.sb.WriteString(strconv.FormatInt(int64(v), 10))
+	case int8:
+		f.sb.WriteString(strconv.FormatInt(int64(v), 10))
+	case *big.Int:
+		f.sb.WriteString(v.String())
+	case bool:
+		f.sb.WriteString(strconv.FormatBool(v))
+	case float64:
+		f.sb.WriteString(formatFloatLiteral(v, 64))
+	case float32:
+		f.sb.WriteString(formatFloatLiteral(float64(v), 32))
+	case CqlDecimal:
+		f.sb.WriteString(formatDecimalLiteral(v))
+	case []byte:
+		f.sb.WriteString("0x")
+		f.sb.WriteString(hex.EncodeToString(v))
+	case time.Time:
+		if dt.Code() == primitive.DataTypeCodeDate {
+			writeQuotedLiteral(f.sb, v.Format(DateLayoutDefault), '\'')
+		} else {
+			writeQuotedLiteral(f.sb, v.UTC().Format(timestampLiteralLayout), '\'')
+		}
+	case time.Duration:
+		writeQuotedLiteral(f.sb, formatTimeLiteral(v), '\'')
+	case primitive.UUID:
+		f.sb.WriteString(v.String())
+	case net.IP:
+		writeQuotedLiteral(f.sb, v.String(), '\'')
+	case CqlDuration:
+		literal, err := v.formatUnits()
+		if err != nil {
+			return err
+		}
+		f.sb.WriteString(literal)
+	case fmt.Stringer:
+		// DSE geospatial types and date ranges
+		writeQuotedLiteral(f.sb, v.String(), '\'')
+	default:
+		return fmt.Errorf("cannot format value of type %T", value)
+	}
+	return nil
+}
+
+func (f *literalFormatter) open(kind containerKind) {
+	open, _ := literalDelimiters(kind)
+	f.sb.WriteByte(open)
+}
+
+func (f *literalFormatter) close(kind containerKind) {
+	_, close := literalDelimiters(kind)
+	f.sb.WriteByte(close)
+}
+
+func (f *literalFormatter) writeSeparator() {
+	f.sb.WriteString(", ")
+}
+
+func (f *literalFormatter) startMapKey() {
+}
+
+func (f *literalFormatter) endMapKey() {
+	f.sb.WriteString(": ")
+}
+
+func (f *literalFormatter) writeFieldName(name string) {
+	formatIdentifier(f.sb, name)
+	f.sb.WriteString(": ")
+}
+
+// literalDelimiters returns the characters enclosing the CQL literals of containers of the given kind.
+func literalDelimiters(kind containerKind) (open, close byte) {
+	switch kind {
+	case containerList, containerVector:
+		return '[', ']'
+	case containerTuple:
+		return '(', ')'
+	}
+	return '{', '}'
+}
+
+// writeQuotedLiteral writes the given string enclosed in the given quote character, doubling any occurrence of the
+// quote character in the string.
+func writeQuotedLiteral(sb *strings.Builder, s string, quote byte) {
+	sb.WriteByte(quote)
+	for i := 0; i < len(s); i++ {
+		if s[i] == quote {
+			sb.WriteByte(quote)
+		}
+		sb.WriteByte(s[i])
+	}
+	sb.WriteByte(quote)
+}
+
+// formatIdentifier writes the given identifier unquoted if it is a valid unquoted CQL identifier in lower case, and
+// double-quoted otherwise.
+func formatIdentifier(sb *strings.Builder, identifier string) {
+	unquoted := identifier != "" && identifier[0] >= 'a' && identifier[0] <= 'z'
+	for i := 1; unquoted && i < len(identifier); i++ {
+		c := identifier[i]
+		unquoted = c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_'
+	}
+	if unquoted {
+		sb.WriteString(identifier)
+	} else {
+		writeQuotedLiteral(sb, identifier, '"')
+	}
+}
+
+func formatFloatLiteral(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+func formatDecimalLiteral(d CqlDecimal) string {
+	if d.Unscaled == nil {
+		return "0"
+	}
+	digits := new(big.Int).Abs(d.Unscaled).String()
+	var sb strings.Builder
+	if d.Unscaled.Sign() < 0 {
+		sb.WriteByte('-')
+	}
+	if d.Scale <= 0 {
+		sb.WriteString(digits)
+		if d.Scale < 0 && d.Unscaled.Sign() != 0 {
+			sb.WriteString("E+")
+			sb.WriteString(strconv.Itoa(-int(d.Scale)))
+		}
+		return sb.String()
+	}
+	scale := int(d.Scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	sb.WriteString(digits[:len(digits)-scale])
+	sb.WriteByte('.')
+	sb.WriteString(digits[len(digits)-scale:])
+	return sb.String()
+}
+
+func formatTimeLiteral(d time.Duration) string {
+	nanos := int64(d)
+	return fmt.Sprintf(
+		"%02d:%02d:%02d.%09d",
+		nanos/int64(time.Hour),
+		nanos/int64(time.Minute)%60,
+		nanos/int64(time.Second)%60,
+		nanos%int64(time.Second),
+	)
+}
+
+// literalParser is a simple recursive-descent parser for CQL literals, driven by an encodeWalker.
+type literalParser struct {
+	lexer.Lexer
+}
+
+func (p *literalParser) parseNull() (bool, error) {
+	p.SkipSpaces()
+	if p.EOF() {
+		return false, p.Errorf("expecting literal, got end of input")
+	}
+	return p.acceptNull(), nil
+}
+
+func (p *literalParser) parseSimple(dt datatype.DataType, encode func(value interface{}) error) error {
+	start := p.Pos
+	token, quoted, err := p.parseToken()
+	if err != nil {
+		return err
+	}
+	if !quoted && dt.Code() == primitive.DataTypeCodeDuration {
+		token = p.continueDurationToken(token)
+	}
+	value, err := convertLiteralToken(token, quoted, dt)
+	if err == nil {
+		err = encode(value)
+	}
+	if err != nil {
+		p.Pos = start
+		return p.Errorf("invalid %s literal: %w", dt, err)
+	}
+	return nil
+}
+
+func (p *literalParser) parseElements(kind containerKind, maxSize int, parseElement func(i int) error) error {
+	open, close := literalDelimiters(kind)
+	i := 0
+	return p.parseSequence(open, close, func() error {
+		if maxSize >= 0 && i >= maxSize {
+			return p.Errorf("too many %s elements, expecting %d", kind, maxSize)
+		}
+		err := parseElement(i)
+		i++
+		return err
+	})
+}
+
+func (p *literalParser) parseEntries(_ *datatype.Map, parseKey, parseValue func(i int) error) error {
+	i := 0
+	return p.parseSequence('{', '}', func() error {
+		if err := parseKey(i); err != nil {
+			return err
+		} else if err = p.Expect(':'); err != nil {
+			return err
+		}
+		err := parseValue(i)
+		i++
+		return err
+	})
+}
+
+func (p *literalParser) parseFields(udtType *datatype.UserDefined, parseField func(index int) error) error {
+	seen := make([]bool, len(udtType.FieldNames))
+	return p.parseSequence('{', '}', func() error {
+		start := p.Pos
+		name, _, err := p.ParseIdentifier()
+		if err != nil {
+			return err
+		}
+		index := -1
+		for i, fieldName := range udtType.FieldNames {
+			if fieldName == name {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			p.Pos = start
+			return p.Errorf("unknown field %s", name)
+		} else if seen[index] {
+			p.Pos = start
+			return p.Errorf("duplicate field %s", name)
+		} else if err = p.Expect(':'); err != nil {
+			return err
+		}
+		seen[index] = true
+		return parseField(index)
+	})
+}
+
+// parseSequence parses a comma-separated sequence of items enclosed in the given open and close characters, invoking
+// parseItem for each item.
+func (p *literalParser) parseSequence(open, close byte, parseItem func() error) error {
+	if err := p.Expect(open); err != nil {
+		return err
+	} else if p.Accept(close) {
+		return nil
+	}
+	for {
+		p.SkipSpaces()
+		if err := parseItem(); err != nil {
+			return err
+		} else if p.Accept(',') {
+			continue
+		}
+		return p.Expect(close)
+	}
+}
+
+// parseToken parses a quoted string or an unquoted token, e.g. a number, a uuid or a blob literal. Unquoted tokens
+// end at the first whitespace or delimiter character.
+func (p *literalParser) parseToken() (token string, quoted bool, err error) {
+	p.SkipSpaces()
+	if p.EOF() {
+		return "", false, p.Errorf("expecting literal, got end of input")
+	} else if p.Peek() == '\'' {
+		token, err = p.ParseQuoted('\'')
+		return token, true, err
+	} else if strings.HasPrefix(p.Input[p.Pos:], "$$") {
+		end := strings.Index(p.Input[p.Pos+2:], "$$")
+		if end < 0 {
+			return "", false, p.Errorf("unterminated string literal")
+		}
+		token = p.Input[p.Pos+2 : p.Pos+2+end]
+		p.Pos += end + 4
+		return token, true, nil
+	}
+	start := p.Pos
+	for !p.EOF() && !lexer.IsSpace(p.Peek()) && !strings.ContainsRune(",:[](){}'\"", rune(p.Peek())) {
+		p.Pos++
+	}
+	if start == p.Pos {
+		return "", false, p.Errorf("expecting literal, got '%c'", p.Peek())
+	}
+	return p.Input[start:p.Pos], false, nil
+}
+
+// continueDurationToken extends the given duration token when it is the date part of an ISO 8601 duration in the
+// alternative format, e.g. P0001-02-03T04:05:06, since colons otherwise terminate unquoted tokens.
+func (p *literalParser) continueDurationToken(token string) string {
+	if !strings.HasPrefix(strings.TrimPrefix(token, "-"), "P") || !strings.Contains(token, "T") {
+		return token
+	}
+	start := p.Pos - len(token)
+	for !p.EOF() && (p.Peek() == ':' || isDigit(p.Peek())) {
+		p.Pos++
+	}
+	return p.Input[start:p.Pos]
+}
+
+// acceptNull consumes the NULL keyword, case-insensitive, if it is the next token.
+func (p *literalParser) acceptNull() bool {
+	end := p.Pos + len("null")
+	if end <= len(p.Input) && strings.EqualFold(p.Input[p.Pos:end], "null") &&
+		(end == len(p.Input) || !lexer.IsIdentifierChar(p.Input[end])) {
+		p.Pos = end
+		return true
+	}
+	return false
+}
+
+// convertLiteralToken converts the given literal token to a Go value accepted by the built-in codec for the given
+// type.
+func convertLiteralToken(token string, quoted bool, dt datatype.DataType) (interface{}, error) {
+	switch dt.Code() {
+	case primitive.DataTypeCodeAscii, primitive.DataTypeCodeVarchar, primitive.DataTypeCodeInet:
+		if !quoted {
+			return nil, errors.New("expecting string literal")
+		}
+		return token, nil
+	case primitive.DataTypeCodeUuid, primitive.DataTypeCodeTimeuuid:
+		return token, nil
+	case primitive.DataTypeCodeCustom:
+		if _, found := customCodecs[dt.(*datatype.Custom).ClassName]; found {
+			if !quoted {
+				return nil, errors.New("expecting string literal")
+			}
+			return token, nil
+		}
+	case primitive.DataTypeCodeTimestamp:
+		if quoted {
+			return parseTimestampLiteral(token)
+		}
+	case primitive.DataTypeCodeDate:
+		if quoted {
+			return time.Parse(DateLayoutDefault, token)
+		}
+	case primitive.DataTypeCodeTime:
+		if quoted {
+			return parseTimeLiteral(token)
+		}
+	case primitive.DataTypeCodeDuration:
+		if quoted {
+			return nil, errors.New("unexpected string literal")
+		}
+		return ParseCqlDuration(token)
+	}
+	if quoted {
+		return nil, errors.New("unexpected string literal")
+	}
+	switch dt.Code() {
+	case primitive.DataTypeCodeBigint,
+		primitive.DataTypeCodeCounter,
+		primitive.DataTypeCodeInt,
+		primitive.DataTypeCodeSmallint,
+		primitive.DataTypeCodeTinyint,
+		primitive.DataTypeCodeVarint:
+		// integer codecs parse strings as base 10 numbers
+		return token, nil
+	case primitive.DataTypeCodeBoolean:
+		if strings.EqualFold(token, "true") {
+			return true, nil
+		} else if strings.EqualFold(token, "false") {
+			return false, nil
+		}
+		return nil, errors.New("expecting true or false")
+	case primitive.DataTypeCodeDouble:
+		return strconv.ParseFloat(token, 64)
+	case primitive.DataTypeCodeFloat:
+		f, err := strconv.ParseFloat(token, 32)
+		return float32(f), err
+	case primitive.DataTypeCodeDecimal:
+		return parseDecimalLiteral(token)
+	case primitive.DataTypeCodeBlob, primitive.DataTypeCodeCustom:
+		if !strings.HasPrefix(token, "0x") && !strings.HasPrefix(token, "0X") {
+			return nil, errors.New("expecting blob literal")
+		}
+		return hex.DecodeString(token[2:])
+	case primitive.DataTypeCodeTimestamp:
+		return strconv.ParseInt(token, 10, 64)
+	case primitive.DataTypeCodeDate:
+		// raw days, with the epoch at 2^31
+		days, err := strconv.ParseUint(token, 10, 32)
+		return int64(days) + math.MinInt32, err
+	case primitive.DataTypeCodeTime:
+		return strconv.ParseInt(token, 10, 64)
+	}
+	return nil, errCannotCreateCodec(dt)
+}
+
+func parseTimestampLiteral(s string) (t time.Time, err error) {
+	for _, layout := range timestampLiteralLayouts {
+		if t, err = time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse timestamp: %q", s)
+}
+
+// parseTimeLiteral parses a time literal of the form hh:mm:ss[.fffffffff].
+func parseTimeLiteral(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("cannot parse time: %q", s)
+	}
+	fraction := ""
+	if i := strings.IndexByte(parts[2], '.'); i >= 0 {
+		parts[2], fraction = parts[2][:i], parts[2][i+1:]
+		if len(fraction) == 0 || len(fraction) > 9 {
+			return 0, fmt.Errorf("cannot parse time: %q", s)
+		}
+		fraction += strings.Repeat("0", 9-len(fraction))
+	} else {
+		fraction = "0"
+	}
+	hours, err1 := strconv.ParseUint(parts[0], 10, 8)
+	minutes, err2 := strconv.ParseUint(parts[1], 10, 8)
+	seconds, err3 := strconv.ParseUint(parts[2], 10, 8)
+	nanos, err4 := strconv.ParseUint(fraction, 10, 32)
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil || hours > 23 || minutes > 59 || seconds > 59 {
+		return 0, fmt.Errorf("cannot parse time: %q", s)
+	}
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(nanos), nil
+}
+
+// parseDecimalLiteral parses an integer or floating-point literal, e.g. -123, 1.23 or 1.23E-4, as a decimal, without
+// loss of precision.
+func parseDecimalLiteral(s string) (CqlDecimal, error) {
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		if exponent, err = strconv.ParseInt(s[i+1:], 10, 32); err != nil {
+			return CqlDecimal{}, fmt.Errorf("cannot parse decimal: %q", s)
+		}
+		mantissa = s[:i]
+	}
+	scale := int64(0)
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		scale = int64(len(mantissa) - i - 1)
+		mantissa = mantissa[:i] + mantissa[i+1:]
+	}
+	unscaled, ok := new(big.Int).SetString(mantissa, 10)
+	if scale -= exponent; !ok || scale < math.MinInt32 || scale > math.MaxInt32 {
+		return CqlDecimal{}, fmt.Errorf("cannot parse decimal: %q", s)
+	}
+	return CqlDecimal{Unscaled: unscaled, Scale: int32(scale)}, nil
+}
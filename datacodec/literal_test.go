@@ -0,0 +1,271 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"math"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+var (
+	literalUdtType = &datatype.UserDefined{
+		Keyspace:   "ks1",
+		Name:       "address",
+		FieldNames: []string{"street", "zip_code", "Country"},
+		FieldTypes: []datatype.DataType{datatype.Varchar, datatype.Int, datatype.Varchar},
+	}
+	literalTupleType  = datatype.NewTuple(datatype.Int, datatype.Varchar, datatype.Boolean)
+	literalUuid       = primitive.UUID{0xc0, 0xd1, 0xd2, 0x1e, 0xbb, 0x01, 0x41, 0x96, 0x86, 0xdb, 0xbc, 0x31, 0x7b, 0xc1, 0x79, 0x6a}
+	literalTimestamp  = time.Date(2021, 3, 4, 12, 34, 56, 789000000, time.UTC)
+	literalDate       = time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)
+	literalTimeOfDay  = 12*time.Hour + 34*time.Minute + 56*time.Second + 789*time.Millisecond
+	literalDecimal, _ = new(big.Int).SetString("-123456789012345678901234567890", 10)
+)
+
+func encodeLiteralTestValue(t *testing.T, dt datatype.DataType, value interface{}) []byte {
+	if column, ok := value.(message.Column); ok {
+		// already encoded, e.g. maps with a deterministic entry order
+		return column
+	}
+	codec, err := NewCodec(dt)
+	require.NoError(t, err)
+	encoded, err := codec.Encode(value, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	return encoded
+}
+
+func TestFormatAndParseLiteral(t *testing.T) {
+	tests := []struct {
+		name    string
+		dt      datatype.DataType
+		value   interface{}
+		literal string
+	}{
+		{"null", datatype.Int, nil, "NULL"},
+		{"varchar", datatype.Varchar, "hello", "'hello'"},
+		{"varchar with quotes", datatype.Varchar, "it's 'quoted'", "'it''s ''quoted'''"},
+		{"varchar empty", datatype.Varchar, "", "''"},
+		{"ascii", datatype.Ascii, "abc", "'abc'"},
+		{"bigint", datatype.Bigint, int64(math.MinInt64), "-9223372036854775808"},
+		{"counter", datatype.Counter, int64(42), "42"},
+		{"int", datatype.Int, int32(-42), "-42"},
+		{"smallint", datatype.Smallint, int16(math.MaxInt16), "32767"},
+		{"tinyint", datatype.Tinyint, int8(-128), "-128"},
+		{"varint", datatype.Varint, literalDecimal, "-123456789012345678901234567890"},
+		{"boolean", datatype.Boolean, true, "true"},
+		{"double", datatype.Double, 1.5, "1.5"},
+		{"double exponent", datatype.Double, 1.5e-10, "1.5e-10"},
+		{"double NaN", datatype.Double, math.NaN(), "NaN"},
+		{"double +Inf", datatype.Double, math.Inf(1), "Infinity"},
+		{"float -Inf", datatype.Float, float32(math.Inf(-1)), "-Infinity"},
+		{"float", datatype.Float, float32(0.1), "0.1"},
+		{"decimal", datatype.Decimal, CqlDecimal{Unscaled: big.NewInt(-12345), Scale: 2}, "-123.45"},
+		{"decimal leading zeros", datatype.Decimal, CqlDecimal{Unscaled: big.NewInt(5), Scale: 3}, "0.005"},
+		{"decimal integer", datatype.Decimal, CqlDecimal{Unscaled: big.NewInt(12), Scale: 0}, "12"},
+		{"decimal negative scale", datatype.Decimal, CqlDecimal{Unscaled: big.NewInt(12), Scale: -3}, "12E+3"},
+		{"blob", datatype.Blob, []byte{0xca, 0xfe}, "0xcafe"},
+		{"blob empty", datatype.Blob, []byte{}, "0x"},
+		{"uuid", datatype.Uuid, literalUuid, "c0d1d21e-bb01-4196-86db-bc317bc1796a"},
+		{"timeuuid", datatype.Timeuuid, literalUuid, "c0d1d21e-bb01-4196-86db-bc317bc1796a"},
+		{"inet v4", datatype.Inet, net.ParseIP("192.168.0.1"), "'192.168.0.1'"},
+		{"inet v6", datatype.Inet, net.ParseIP("::1"), "'::1'"},
+		{"timestamp", datatype.Timestamp, literalTimestamp, "'2021-03-04T12:34:56.789Z'"},
+		{"date", datatype.Date, literalDate, "'2021-03-04'"},
+		{"time", datatype.Time, literalTimeOfDay, "'12:34:56.789000000'"},
+		{"duration", datatype.Duration, CqlDuration{Months: 14, Days: 3, Nanos: 4*time.Hour + 5*time.Minute + 6*time.Second + 7*time.Millisecond + 8*time.Microsecond + 9}, "1y2mo3d4h5m6s7ms8us9ns"},
+		{"duration negative", datatype.Duration, CqlDuration{Days: -2, Nanos: -time.Hour}, "-2d1h"},
+		{"duration zero", datatype.Duration, CqlDuration{}, "0s"},
+		{"point", datatype.Point, DsePoint{X: 1, Y: 2}, "'POINT (1 2)'"},
+		{"custom", datatype.NewCustom("com.example.Custom"), []byte{1, 2}, "0x0102"},
+		{"list", datatype.NewList(datatype.Int), []int32{1, 2, 3}, "[1, 2, 3]"},
+		{"list empty", datatype.NewList(datatype.Int), []int32{}, "[]"},
+		{"set", datatype.NewSet(datatype.Varchar), []string{"a", "b"}, "{'a', 'b'}"},
+		{"map", datatype.NewMap(datatype.Varchar, datatype.NewList(datatype.Int)), map[string][]int32{"a": {1}}, "{'a': [1]}"},
+		{"tuple", literalTupleType, []interface{}{1, "a", nil}, "(1, 'a', NULL)"},
+		{"udt", literalUdtType, map[string]interface{}{"street": "Main St", "zip_code": 123, "Country": nil}, `{street: 'Main St', zip_code: 123, "Country": NULL}`},
+		{"vector fixed", datatype.NewVector(datatype.Float, 3), []float32{1, 2.5, -3}, "[1, 2.5, -3]"},
+		{"vector variable", datatype.NewVector(datatype.Varchar, 2), []string{"a", "bc"}, "['a', 'bc']"},
+		{"nested", datatype.NewList(datatype.NewTuple(datatype.Timestamp, datatype.Blob)), [][]interface{}{{literalTimestamp, []byte{1}}}, "[('2021-03-04T12:34:56.789Z', 0x01)]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := encodeLiteralTestValue(t, tt.dt, tt.value)
+			literal, err := FormatLiteral(encoded, tt.dt, primitive.ProtocolVersion5)
+			require.NoError(t, err)
+			assert.Equal(t, tt.literal, literal)
+			parsed, err := ParseLiteral(tt.literal, tt.dt, primitive.ProtocolVersion5)
+			require.NoError(t, err)
+			assert.Equal(t, encoded, []byte(parsed))
+		})
+	}
+}
+
+func TestRegistry_FormatAndParseLiteral(t *testing.T) {
+	count := 0
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(countingCodec{Int, &count}))
+	listType := datatype.NewList(datatype.Int)
+	encoded := encodeLiteralTestValue(t, listType, []int32{1, 2})
+	literal, err := registry.FormatLiteral(encoded, listType, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	assert.Equal(t, "[1, 2]", literal)
+	assert.Equal(t, 2, count)
+	parsed, err := registry.ParseLiteral(literal, listType, primitive.ProtocolVersion5)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, []byte(parsed))
+	assert.Equal(t, 4, count)
+}
+
+func TestFormatLiteral_Versions(t *testing.T) {
+	dt := datatype.NewList(datatype.Int)
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			codec, _ := NewCodec(dt)
+			encoded, _ := codec.Encode([]int32{1, 2}, version)
+			literal, err := FormatLiteral(encoded, dt, version)
+			require.NoError(t, err)
+			assert.Equal(t, "[1, 2]", literal)
+			parsed, err := ParseLiteral(literal, dt, version)
+			require.NoError(t, err)
+			assert.Equal(t, encoded, []byte(parsed))
+		})
+	}
+}
+
+func TestFormatLiteral_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source []byte
+		dt     datatype.DataType
+		err    string
+	}{
+		{"nil data type", []byte{1}, nil, "data type is nil"},
+		{"wrong length", []byte{1, 2}, datatype.Int, "cannot format CQL int literal: cannot decode CQL int as *interface {}"},
+		{"collection truncated", []byte{0, 0, 0, 2, 0, 0, 0, 4, 0, 0, 0, 1}, datatype.NewList(datatype.Int), "cannot format CQL list<int> literal: cannot read element 1"},
+		{"element invalid", []byte{0, 0, 0, 1, 0, 0, 0, 1, 1}, datatype.NewSet(datatype.Int), "cannot format CQL set<int> literal: cannot format element 0"},
+		{"map value invalid", []byte{0, 0, 0, 1, 0, 0, 0, 1, 1, 0, 0, 0, 1, 1}, datatype.NewMap(datatype.Tinyint, datatype.Int), "cannot format entry 0 value"},
+		{"bytes remaining", []byte{0, 0, 0, 0, 1}, datatype.NewList(datatype.Int), "source was not fully read"},
+		{"udt field missing", []byte{0, 0, 0, 1, 'a'}, literalUdtType, "cannot read field 1 (zip_code)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			literal, err := FormatLiteral(tt.source, tt.dt, primitive.ProtocolVersion5)
+			assert.Empty(t, literal)
+			assertErrorMessage(t, tt.err, err)
+		})
+	}
+}
+
+func TestParseLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		literal  string
+		dt       datatype.DataType
+		expected interface{}
+	}{
+		{"null lower case", "null", datatype.Varchar, nil},
+		{"null with spaces", "  NuLL  ", datatype.NewList(datatype.Int), nil},
+		{"dollar string", "$$it's$$", datatype.Varchar, "it's"},
+		{"boolean upper case", "FALSE", datatype.Boolean, false},
+		{"blob upper case prefix", "0XCAFE", datatype.Blob, []byte{0xca, 0xfe}},
+		{"uuid quoted", "'c0d1d21e-bb01-4196-86db-bc317bc1796a'", datatype.Uuid, literalUuid},
+		{"timestamp millis", "1614861296789", datatype.Timestamp, literalTimestamp},
+		{"timestamp space", "'2021-03-04 12:34:56.789+0000'", datatype.Timestamp, literalTimestamp},
+		{"timestamp offset", "'2021-03-04T13:34:56.789+01:00'", datatype.Timestamp, literalTimestamp},
+		{"timestamp no zone", "'2021-03-04 12:34'", datatype.Timestamp, literalTimestamp.Truncate(time.Minute)},
+		{"timestamp date only", "'2021-03-04'", datatype.Timestamp, literalDate},
+		{"date raw days", "2147502338", datatype.Date, literalDate},
+		{"time nanos", "45296789000000", datatype.Time, literalTimeOfDay},
+		{"time no fraction", "'12:34:56'", datatype.Time, literalTimeOfDay.Truncate(time.Second)},
+		{"decimal exponent", "1.23E-4", datatype.Decimal, CqlDecimal{Unscaled: big.NewInt(123), Scale: 6}},
+		{"duration weeks", "2w1D", datatype.Duration, CqlDuration{Days: 15}},
+		{"duration micros", "3µs", datatype.Duration, CqlDuration{Nanos: 3 * time.Microsecond}},
+		{"duration ISO 8601", "-P1DT2H", datatype.Duration, CqlDuration{Days: -1, Nanos: -2 * time.Hour}},
+		{"duration ISO 8601 alternative", "P0001-02-03T04:05:06", datatype.Duration, CqlDuration{Months: 14, Days: 3, Nanos: 4*time.Hour + 5*time.Minute + 6*time.Second}},
+		{"list of duration ISO 8601 alternative", "[P0000-00-01T00:00:00, 1h]", datatype.NewList(datatype.Duration), []CqlDuration{{Days: 1}, {Nanos: time.Hour}}},
+		{"tuple missing elements", "( 1 )", literalTupleType, []interface{}{int32(1), nil, nil}},
+		{"udt any order", `{"Country": 'FR', STREET: 'Main St'}`, literalUdtType, map[string]interface{}{"street": "Main St", "Country": "FR"}},
+		{"set whitespace", " { 'b' ,'a' } ", datatype.NewSet(datatype.Varchar), []string{"b", "a"}},
+		{"map empty", "{}", datatype.NewMap(datatype.Int, datatype.Int), map[int32]int32{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseLiteral(tt.literal, tt.dt, primitive.ProtocolVersion5)
+			require.NoError(t, err)
+			var expected []byte
+			if tt.expected != nil {
+				expected = encodeLiteralTestValue(t, tt.dt, tt.expected)
+			}
+			assert.Equal(t, expected, []byte(parsed))
+		})
+	}
+}
+
+func TestParseLiteral_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		literal string
+		dt      datatype.DataType
+		err     string
+	}{
+		{"nil data type", "1", nil, "data type is nil"},
+		{"empty", "", datatype.Int, "cannot parse CQL int literal \"\": expecting literal, got end of input at position 0"},
+		{"trailing input", "1 2", datatype.Int, "unexpected trailing input at position 2"},
+		{"int out of range", "2147483648", datatype.Int, "invalid int literal: cannot encode string as CQL int"},
+		{"int quoted", "'1'", datatype.Int, "invalid int literal: unexpected string literal at position 0"},
+		{"varchar unquoted", "abc", datatype.Varchar, "invalid varchar literal: expecting string literal at position 0"},
+		{"unterminated string", "'abc", datatype.Varchar, "unterminated quoted string"},
+		{"unterminated dollar string", "$$abc", datatype.Varchar, "unterminated string literal"},
+		{"boolean invalid", "yes", datatype.Boolean, "expecting true or false"},
+		{"blob invalid", "cafe", datatype.Blob, "expecting blob literal"},
+		{"blob odd digits", "0xcaf", datatype.Blob, "invalid blob literal"},
+		{"uuid invalid", "abc", datatype.Uuid, "invalid uuid literal"},
+		{"timestamp invalid", "'yesterday'", datatype.Timestamp, "cannot parse timestamp"},
+		{"time invalid", "'25:00:00'", datatype.Time, "cannot parse time"},
+		{"date invalid", "'2021-13-01'", datatype.Date, "invalid date literal"},
+		{"duration unknown unit", "1x", datatype.Duration, "unknown unit \"x\""},
+		{"duration quoted", "'1d'", datatype.Duration, "unexpected string literal"},
+		{"duration overflow", "2147483648d", datatype.Duration, "number of days overflows"},
+		{"duration wrong order", "1s1h", datatype.Duration, "unit \"h\" must appear before unit \"s\""},
+		{"list unterminated", "[1, 2", datatype.NewList(datatype.Int), "expecting ']', got end of input at position 5"},
+		{"list wrong delimiter", "{1}", datatype.NewList(datatype.Int), "expecting '[', got '{' at position 0"},
+		{"list invalid element", "[1, 'a']", datatype.NewList(datatype.Int), "invalid int literal: unexpected string literal at position 4"},
+		{"map missing colon", "{1 2}", datatype.NewMap(datatype.Int, datatype.Int), "expecting ':', got '2' at position 3"},
+		{"tuple too many", "(1, 'a', true, 2)", literalTupleType, "too many tuple elements, expecting 3 at position 15"},
+		{"udt unknown field", "{city: 'Paris'}", literalUdtType, "unknown field city at position 1"},
+		{"udt duplicate field", "{street: 'a', street: 'b'}", literalUdtType, "duplicate field street at position 14"},
+		{"udt case-sensitive field", "{country: 'FR'}", literalUdtType, "unknown field country"},
+		{"vector too short", "[1, 2]", datatype.NewVector(datatype.Int, 3), "expected vector of 3 elements, got: 2"},
+		{"vector too long", "[1, 2]", datatype.NewVector(datatype.Int, 1), "too many vector elements, expecting 1"},
+		{"vector null element", "[1, null]", datatype.NewVector(datatype.Int, 2), "vector elements cannot be null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseLiteral(tt.literal, tt.dt, primitive.ProtocolVersion5)
+			assert.Nil(t, parsed)
+			assertErrorMessage(t, tt.err, err)
+		})
+	}
+}
@@ -24,14 +24,18 @@ import (
 )
 
 func NewMap(dataType *datatype.Map) (Codec, error) {
+	return DefaultRegistry.newMap(dataType)
+}
+
+func (r *Registry) newMap(dataType *datatype.Map) (Codec, error) {
 	if dataType == nil {
 		return nil, ErrNilDataType
 	}
-	keyCodec, err := NewCodec(dataType.KeyType)
+	keyCodec, err := r.NewCodec(dataType.KeyType)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create codec for map keys: %w", err)
 	}
-	valueCodec, err := NewCodec(dataType.ValueType)
+	valueCodec, err := r.NewCodec(dataType.ValueType)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create codec for map values: %w", err)
 	}
@@ -49,14 +53,30 @@ func (c *mapCodec) DataType() datatype.DataType {
 }
 
 func (c *mapCodec) Encode(source interface{}, version primitive.ProtocolVersion) (dest []byte, err error) {
-	ext, size, err := c.createExtractor(source)
-	if err == nil && ext != nil {
-		dest, err = writeMap(ext, size, c.keyCodec, c.valueCodec, version)
+	dest, _, err = c.AppendEncode(nil, source, version)
+	return
+}
+
+// AppendEncode appends the encoded map to dest. Maps with string keys and values of the preferred Go type of the value
+// type, e.g. map[string]int32 for map<varchar,int>, are encoded without reflection, provided that the key and value
+// codecs are built-in codecs.
+func (c *mapCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	var handled bool
+	if result, wasNil, handled, err = appendMapFast(dest, source, c.keyCodec, c.valueCodec, version); !handled {
+		var ext keyValueExtractor
+		var size int
+		if ext, size, err = c.createExtractor(source); err == nil {
+			if wasNil = ext == nil; !wasNil {
+				result, err = appendMap(dest, ext, size, c.keyCodec, c.valueCodec, version)
+			}
+		}
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		return dest, false, errCannotEncode(source, c.DataType(), version, err)
+	} else if wasNil {
+		return dest, true, nil
 	}
-	return
+	return result, false, nil
 }
 
 func (c *mapCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
@@ -85,7 +105,7 @@ func (c *mapCodec) createExtractor(source interface{}) (ext keyValueExtractor, s
 				if c.keyCodec.DataType() != datatype.Varchar && c.keyCodec.DataType() != datatype.Ascii {
 					err = errWrongDataType("map key", datatype.Varchar, datatype.Ascii, c.keyCodec.DataType())
 				} else {
-					size = sourceValue.NumField()
+					size = len(structFieldsOf(sourceType).byName)
 					ext, err = newStructExtractor(sourceValue)
 				}
 			}
@@ -135,24 +155,30 @@ func (c *mapCodec) createInjector(dest interface{}, wasNull bool) (injectorFacto
 }
 
 func writeMap(ext keyValueExtractor, size int, keyCodec Codec, valueCodec Codec, version primitive.ProtocolVersion) ([]byte, error) {
-	buf := &bytes.Buffer{}
-	if err := writeCollectionSize(size, buf, version); err != nil {
+	dest, err := appendMap(nil, ext, size, keyCodec, valueCodec, version)
+	if err != nil {
 		return nil, err
 	}
+	return dest, nil
+}
+
+func appendMap(dest []byte, ext keyValueExtractor, size int, keyCodec Codec, valueCodec Codec, version primitive.ProtocolVersion) ([]byte, error) {
+	start := len(dest)
+	dest, err := appendCollectionSize(dest, size, version)
+	if err != nil {
+		return dest, err
+	}
 	for i := 0; i < size; i++ {
 		key := ext.getKey(i)
 		if value, err := ext.getElem(i, key); err != nil {
-			return nil, errCannotExtractMapValue(i, err)
-		} else if encodedKey, err := keyCodec.Encode(key, version); err != nil {
-			return nil, errCannotEncodeMapKey(i, err)
-		} else if encodedValue, err := valueCodec.Encode(value, version); err != nil {
-			return nil, errCannotEncodeMapValue(i, err)
-		} else {
-			_ = primitive.WriteBytes(encodedKey, buf)
-			_ = primitive.WriteBytes(encodedValue, buf)
+			return dest[:start], errCannotExtractMapValue(i, err)
+		} else if dest, err = AppendValue(keyCodec, dest, key, version); err != nil {
+			return dest[:start], errCannotEncodeMapKey(i, err)
+		} else if dest, err = AppendValue(valueCodec, dest, value, version); err != nil {
+			return dest[:start], errCannotEncodeMapValue(i, err)
 		}
 	}
-	return buf.Bytes(), nil
+	return dest, nil
 }
 
 func readMap(source []byte, injectorFactory func(int) (keyValueInjector, error), keyCodec Codec, valueCodec Codec, version primitive.ProtocolVersion) error {
@@ -96,6 +96,12 @@ type coordinates struct {
 	Y float32 `cassandra:"y"`
 }
 
+type coordinatesExtended struct {
+	coordinates
+	Z float32 `cql:"-"`
+	w float32
+}
+
 var (
 	mapOneTwoAbcBytes2 = []byte{
 		0, 1,
@@ -236,6 +242,7 @@ func Test_mapCodec_Encode(t *testing.T) {
 				{"map<int,map<int,varchar>> interface", mapComplex, &map[interface{}]map[interface{}]interface{}{intPtr(0): {intPtr(12): stringPtr("abc")}}, mapZeroOneTwoAbcBytes4, ""},
 				{"coordinates empty", mapCoordinates, &coordinates{}, mapCoordinatesEmptyBytes4, ""},
 				{"coordinates non empty", mapCoordinates, &coordinates{X: 12.34, Y: -56.78}, mapCoordinatesBytes4, ""},
+				{"coordinates embedded", mapCoordinates, &coordinatesExtended{coordinates: coordinates{X: 12.34, Y: -56.78}, Z: 1, w: 2}, mapCoordinatesBytes4, ""},
 				{"coordinates wrong key", mapSimple, &coordinates{X: 12.34, Y: -56.78}, nil, fmt.Sprintf("cannot encode *datacodec.coordinates as CQL map<int,varchar> with %v: wrong map key, expected varchar or ascii, got: int", version)},
 			}
 			for _, tt := range tests {
@@ -278,6 +285,7 @@ func Test_mapCodec_Encode(t *testing.T) {
 				{"map<int,map<int,varchar>> interface", mapComplex, &map[interface{}]map[interface{}]interface{}{intPtr(0): {intPtr(12): stringPtr("abc")}}, mapZeroOneTwoAbcBytes2, ""},
 				{"coordinates empty", mapCoordinates, &coordinates{}, mapCoordinatesEmptyBytes2, ""},
 				{"coordinates non empty", mapCoordinates, &coordinates{X: 12.34, Y: -56.78}, mapCoordinatesBytes2, ""},
+				{"coordinates embedded", mapCoordinates, &coordinatesExtended{coordinates: coordinates{X: 12.34, Y: -56.78}, Z: 1, w: 2}, mapCoordinatesBytes2, ""},
 				{"coordinates wrong key", mapSimple, &coordinates{X: 12.34, Y: -56.78}, nil, fmt.Sprintf("cannot encode *datacodec.coordinates as CQL map<int,varchar> with %v: wrong map key, expected varchar or ascii, got: int", version)},
 			}
 			for _, tt := range tests {
@@ -322,6 +330,7 @@ func Test_mapCodec_Decode(t *testing.T) {
 				{"coordinates nil", mapCoordinates, nil, &coordinates{}, &coordinates{}, true, ""},
 				{"coordinates empty", mapCoordinates, mapCoordinatesEmptyBytes4, &coordinates{}, &coordinates{}, false, ""},
 				{"coordinates non empty", mapCoordinates, mapCoordinatesBytes4, &coordinates{}, &coordinates{X: 12.34, Y: -56.78}, false, ""},
+				{"coordinates embedded", mapCoordinates, mapCoordinatesBytes4, &coordinatesExtended{}, &coordinatesExtended{coordinates: coordinates{X: 12.34, Y: -56.78}}, false, ""},
 				{"coordinates wrong", mapSimple, mapCoordinatesBytes4, &coordinates{}, &coordinates{}, false, fmt.Sprintf("cannot decode CQL map<int,varchar> as *datacodec.coordinates with %v: wrong map key, expected varchar or ascii, got: int", version)},
 			}
 			for _, tt := range tests {
@@ -388,6 +397,7 @@ func Test_mapCodec_Decode(t *testing.T) {
 				{"coordinates nil", mapCoordinates, nil, &coordinates{}, &coordinates{}, true, ""},
 				{"coordinates empty", mapCoordinates, mapCoordinatesEmptyBytes2, &coordinates{}, &coordinates{}, false, ""},
 				{"coordinates non empty", mapCoordinates, mapCoordinatesBytes2, &coordinates{}, &coordinates{X: 12.34, Y: -56.78}, false, ""},
+				{"coordinates embedded", mapCoordinates, mapCoordinatesBytes2, &coordinatesExtended{}, &coordinatesExtended{coordinates: coordinates{X: 12.34, Y: -56.78}}, false, ""},
 				{"coordinates wrong", mapSimple, mapCoordinatesBytes2, &coordinates{}, &coordinates{}, false, fmt.Sprintf("cannot decode CQL map<int,varchar> as *datacodec.coordinates with %v: wrong map key, expected varchar or ascii, got: int", version)},
 			}
 			for _, tt := range tests {
@@ -19,6 +19,7 @@ import (
 	"net"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/datastax/go-cassandra-native-protocol/primitive"
@@ -44,6 +45,10 @@ var (
 	typeOfInterfaceSlice       = reflect.TypeOf([]interface{}{})
 	typeOfStringToInterfaceMap = reflect.TypeOf(map[string]interface{}{})
 	typeOfBigIntPointer        = reflect.TypeOf(big.NewInt(0))
+	typeOfDsePoint             = reflect.TypeOf(DsePoint{})
+	typeOfDseLineString        = reflect.TypeOf(DseLineString{})
+	typeOfDsePolygon           = reflect.TypeOf(DsePolygon{})
+	typeOfDseDateRange         = reflect.TypeOf(DseDateRange{})
 )
 
 // reflectSource is used for collections, maps, tuples and udts. It analyzes the source and returns reflection data.
@@ -161,28 +166,206 @@ func ensureNillable(targetType reflect.Type) reflect.Type {
 	return targetType
 }
 
-// Locates a struct field given a UDT field name. If no field can be located, this function returns a zero
-// reflect.Value. If the struct field has a "cassandra" tag, then the tag must match the UDT field name exactly;
-// otherwise, the UDT field name must match the struct field name, but case insensitively.
-// The case-insensitive lookup is required because only exported struct fields can be located, and such fields are
-// required to have a first upper-case letter.
-func locateFieldByName(structValue reflect.Value, name string) (value reflect.Value) {
-	structType := structValue.Type()
+// structField describes a struct field that can be mapped to a UDT field or to a tuple element.
+type structField struct {
+	// name is the CQL name of the field: either the name found in the field tag, or the Go field name.
+	name string
+	// tagged is true if name was found in the field tag.
+	tagged bool
+	// omitEmpty is true if the field tag has the "omitempty" option: zero values are then encoded as CQL NULLs.
+	omitEmpty bool
+	// index is the index sequence to reach the field from its outermost struct; fields promoted from embedded
+	// structs have more than one index.
+	index []int
+}
+
+// structFields holds the mappable fields of a struct type.
+type structFields struct {
+	// byName contains the fields used to map UDT fields by name; fields of embedded structs are flattened.
+	byName []structField
+	// byPosition contains the fields used to map tuple elements by position; embedded structs are not flattened and
+	// are mapped as a single element.
+	byPosition []structField
+}
+
+// The struct fields of all struct types encountered so far, keyed by reflect.Type.
+var structFieldsCache sync.Map
+
+// Returns the fields of the given struct type that can be mapped to UDT fields or tuple elements, in order of
+// declaration; fields whose tag is "-" are skipped.
+// When mapping by name, only exported fields are returned, and fields of embedded structs without a tag are flattened,
+// as if they were declared in the outer struct. Like encoding/json, fields promoted this way are shadowed by shallower
+// fields with the same name; if several fields with the same name are found at the same depth, the tagged one is
+// used, and if none or more than one of them is tagged, they are all ignored.
+// When mapping by position, all fields count, including unexported ones, which cannot be accessed.
+func structFieldsOf(structType reflect.Type) *structFields {
+	if fields, found := structFieldsCache.Load(structType); found {
+		return fields.(*structFields)
+	}
+	var candidates []structField
+	collectStructFields(structType, nil, true, map[reflect.Type]bool{}, &candidates)
+	fields := &structFields{}
+	for i, candidate := range candidates {
+		if dominantStructField(candidates, candidate.name) == i {
+			fields.byName = append(fields.byName, candidate)
+		}
+	}
+	collectStructFields(structType, nil, false, nil, &fields.byPosition)
+	actual, _ := structFieldsCache.LoadOrStore(structType, fields)
+	return actual.(*structFields)
+}
+
+// Returns the index of the candidate field that dominates all others with the given name, or -1 if there is none:
+// shallower fields dominate deeper ones, and at the same depth, a single tagged field dominates untagged ones.
+func dominantStructField(candidates []structField, name string) int {
+	dominant, depth, tagged, conflict := -1, 0, false, false
+	for i, candidate := range candidates {
+		if candidate.name != name {
+			continue
+		}
+		if dominant == -1 || len(candidate.index) < depth {
+			dominant, depth, tagged, conflict = i, len(candidate.index), candidate.tagged, false
+		} else if len(candidate.index) == depth {
+			if candidate.tagged && !tagged {
+				dominant, tagged, conflict = i, true, false
+			} else if candidate.tagged == tagged {
+				conflict = true
+			}
+		}
+	}
+	if conflict {
+		return -1
+	}
+	return dominant
+}
+
+func collectStructFields(structType reflect.Type, index []int, flatten bool, visited map[reflect.Type]bool, fields *[]structField) {
+	if flatten {
+		visited[structType] = true
+		defer delete(visited, structType)
+	}
 	for i := 0; i < structType.NumField(); i++ {
 		field := structType.Field(i)
-		if strings.EqualFold(name, field.Name) || field.Tag.Get("cassandra") == name {
-			value = structValue.Field(i)
-			break
+		name, omitEmpty, skip := parseStructTag(field)
+		if skip {
+			continue
 		}
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+		if flatten && field.Anonymous && name == "" {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				if !visited[embeddedType] {
+					collectStructFields(embeddedType, fieldIndex, flatten, visited, fields)
+				}
+				continue
+			}
+		}
+		if flatten && field.PkgPath != "" {
+			continue // unexported
+		}
+		tagged := name != ""
+		if !tagged {
+			name = field.Name
+		}
+		*fields = append(*fields, structField{name: name, tagged: tagged, omitEmpty: omitEmpty, index: fieldIndex})
+	}
+}
+
+// Parses the "cql" tag of the given struct field; the legacy "cassandra" tag is also accepted. The tag value is the
+// CQL field name, optionally followed by a comma and the "omitempty" option; a tag value of "-" means that the field
+// must be skipped.
+func parseStructTag(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag, found := field.Tag.Lookup("cql")
+	if !found {
+		tag = field.Tag.Get("cassandra")
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, false
+}
+
+// Locates a struct field given a UDT field name. If no field can be located, this function returns nil. If the
+// struct field has a name in its tag, then the tag must match the UDT field name exactly; otherwise, the UDT field
+// name must match the struct field name, but case insensitively. Tagged fields take precedence over untagged ones, and
+// exact matches take precedence over case-insensitive ones.
+// The case-insensitive lookup is required because only exported struct fields can be located, and such fields are
+// required to have a first upper-case letter.
+func findStructFieldByName(structType reflect.Type, name string) *structField {
+	fields := structFieldsOf(structType).byName
+	var exact, insensitive *structField
+	for i, field := range fields {
+		if field.tagged {
+			if field.name == name {
+				return &fields[i]
+			}
+		} else if exact == nil && field.name == name {
+			exact = &fields[i]
+		} else if insensitive == nil && strings.EqualFold(name, field.name) {
+			insensitive = &fields[i]
+		}
+	}
+	if exact != nil {
+		return exact
+	}
+	return insensitive
+}
+
+// Locates a struct field given a tuple element index. Skipped fields do not count as tuple elements, but unexported
+// ones do. If the index is out of range, this function returns nil.
+func findStructFieldByIndex(structType reflect.Type, index int) *structField {
+	fields := structFieldsOf(structType).byPosition
+	if index >= 0 && index < len(fields) {
+		return &fields[index]
+	}
+	return nil
+}
+
+// Returns the value of this field in the given struct value. If the field is promoted from an embedded struct
+// pointer that is nil, the pointer is set to a newly-allocated struct if allocate is true and the pointer is
+// settable; otherwise, a zero reflect.Value is returned.
+func (f *structField) valueOf(structValue reflect.Value, allocate bool) reflect.Value {
+	value := structValue
+	for i, index := range f.index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !allocate || !value.CanSet() {
+					return reflect.Value{}
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(index)
+	}
+	return value
+}
+
+// Locates a struct field given a UDT field name, see findStructFieldByName. If no field can be located, this function
+// returns a zero reflect.Value. Nil embedded struct pointers are allocated if possible.
+func locateFieldByName(structValue reflect.Value, name string) (value reflect.Value) {
+	if field := findStructFieldByName(structValue.Type(), name); field != nil {
+		value = field.valueOf(structValue, true)
 	}
 	return
 }
 
-// Locates a struct field given its index. If the index is out of range, this function returns a zero reflect.Value.
+// Locates a struct field given its index, see findStructFieldByIndex. If the index is out of range, this function
+// returns a zero reflect.Value. Nil embedded struct pointers are allocated if possible.
 func locateFieldByIndex(structValue reflect.Value, index int) (value reflect.Value) {
-	structType := structValue.Type()
-	if index >= 0 && index < structType.NumField() {
-		value = structValue.Field(index)
+	if field := findStructFieldByIndex(structValue.Type(), index); field != nil {
+		value = field.valueOf(structValue, true)
 	}
 	return
 }
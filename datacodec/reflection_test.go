@@ -0,0 +1,257 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+type (
+	taggedUdtBase struct {
+		Id int `cql:"f1"`
+	}
+	taggedUdt struct {
+		taggedUdtBase
+		Flag    bool    `cql:"f2,omitempty"`
+		Text    *string `cql:"f3"`
+		Ignored string  `cql:"-"`
+	}
+	EmbeddedUdtBase struct {
+		Id int `cql:"f1"`
+	}
+	taggedUdtPointer struct {
+		*EmbeddedUdtBase
+		F2 bool
+		F3 *string
+	}
+	shadowingUdt struct {
+		taggedUdtBase
+		Id     int `cql:"f1"`
+		Legacy int `cassandra:"legacy_name"`
+		hidden int
+	}
+	AmbiguousUdtBase1 struct {
+		F1 int
+		F2 int
+	}
+	AmbiguousUdtBase2 struct {
+		F2 int
+		F1 int `cql:"F1"`
+	}
+	ambiguousUdt struct {
+		AmbiguousUdtBase1
+		AmbiguousUdtBase2
+		F3 int
+	}
+	taggedTuple struct {
+		Int     int
+		Skipped string `cql:"-"`
+		Bool    bool   `cql:",omitempty"`
+		String  *string
+	}
+)
+
+func Test_structFieldsOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		structType reflect.Type
+		byName     []structField
+		byPosition []structField
+	}{
+		{
+			"tagged",
+			reflect.TypeOf(taggedUdt{}),
+			[]structField{
+				{name: "f1", tagged: true, index: []int{0, 0}},
+				{name: "f2", tagged: true, omitEmpty: true, index: []int{1}},
+				{name: "f3", tagged: true, index: []int{2}},
+			},
+			[]structField{
+				// unexported embedded struct: counts as a tuple element, but cannot be accessed
+				{name: "taggedUdtBase", index: []int{0}},
+				{name: "f2", tagged: true, omitEmpty: true, index: []int{1}},
+				{name: "f3", tagged: true, index: []int{2}},
+			},
+		},
+		{
+			"embedded pointer",
+			reflect.TypeOf(taggedUdtPointer{}),
+			[]structField{
+				{name: "f1", tagged: true, index: []int{0, 0}},
+				{name: "F2", index: []int{1}},
+				{name: "F3", index: []int{2}},
+			},
+			[]structField{
+				{name: "EmbeddedUdtBase", index: []int{0}},
+				{name: "F2", index: []int{1}},
+				{name: "F3", index: []int{2}},
+			},
+		},
+		{
+			"shadowing and legacy tag",
+			reflect.TypeOf(shadowingUdt{}),
+			[]structField{
+				{name: "f1", tagged: true, index: []int{1}},
+				{name: "legacy_name", tagged: true, index: []int{2}},
+			},
+			[]structField{
+				{name: "taggedUdtBase", index: []int{0}},
+				{name: "f1", tagged: true, index: []int{1}},
+				{name: "legacy_name", tagged: true, index: []int{2}},
+				{name: "hidden", index: []int{3}},
+			},
+		},
+		{
+			"ambiguous embedded fields",
+			reflect.TypeOf(ambiguousUdt{}),
+			[]structField{
+				{name: "F1", tagged: true, index: []int{1, 1}},
+				{name: "F3", index: []int{2}},
+			},
+			[]structField{
+				{name: "AmbiguousUdtBase1", index: []int{0}},
+				{name: "AmbiguousUdtBase2", index: []int{1}},
+				{name: "F3", index: []int{2}},
+			},
+		},
+		{
+			"tuple",
+			reflect.TypeOf(taggedTuple{}),
+			[]structField{
+				{name: "Int", index: []int{0}},
+				{name: "Bool", omitEmpty: true, index: []int{2}},
+				{name: "String", index: []int{3}},
+			},
+			[]structField{
+				{name: "Int", index: []int{0}},
+				{name: "Bool", omitEmpty: true, index: []int{2}},
+				{name: "String", index: []int{3}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := structFieldsOf(tt.structType)
+			assert.Equal(t, tt.byName, fields.byName)
+			assert.Equal(t, tt.byPosition, fields.byPosition)
+			assert.Same(t, fields, structFieldsOf(tt.structType))
+		})
+	}
+}
+
+func Test_findStructFieldByName(t *testing.T) {
+	type mixedCase struct {
+		Foo int
+		Bar int `cql:"Foo"`
+		Baz int `cql:"qix"`
+	}
+	structType := reflect.TypeOf(mixedCase{})
+	assert.Equal(t, []int{1}, findStructFieldByName(structType, "Foo").index)
+	// the untagged field Foo is hidden by the tagged field with the same name, which requires an exact match
+	assert.Nil(t, findStructFieldByName(structType, "foo"))
+	assert.Equal(t, []int{2}, findStructFieldByName(structType, "qix").index)
+	assert.Nil(t, findStructFieldByName(structType, "QIX"))
+	assert.Nil(t, findStructFieldByName(structType, "baz"))
+}
+
+func Test_udtCodec_structTags(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersionsGreaterThanOrEqualTo(primitive.ProtocolVersion3) {
+		t.Run(version.String(), func(t *testing.T) {
+			t.Run("encode", func(t *testing.T) {
+				tests := []struct {
+					name     string
+					source   interface{}
+					expected []byte
+				}{
+					{"tagged", taggedUdt{taggedUdtBase{123}, true, stringPtr("abc"), "ignored"}, oneTwoThreeAbcUdtBytes},
+					{"tagged omitempty", &taggedUdt{taggedUdtBase{123}, false, nil, "ignored"}, udtWithNullFieldsBytes2},
+					{"embedded pointer", taggedUdtPointer{&EmbeddedUdtBase{123}, false, nil}, udtWithNullFieldsBytes},
+					{"embedded nil pointer", taggedUdtPointer{nil, true, stringPtr("abc")}, []byte{
+						255, 255, 255, 255, // nil int
+						0, 0, 0, 1, // length of boolean
+						1,          // boolean
+						0, 0, 0, 3, // length of string
+						a, b, c, // string
+					}},
+				}
+				for _, tt := range tests {
+					t.Run(tt.name, func(t *testing.T) {
+						actual, err := udtCodecSimple.Encode(tt.source, version)
+						require.NoError(t, err)
+						assert.Equal(t, tt.expected, actual)
+					})
+				}
+			})
+			t.Run("decode", func(t *testing.T) {
+				t.Run("tagged", func(t *testing.T) {
+					dest := taggedUdt{Ignored: "untouched"}
+					wasNull, err := udtCodecSimple.Decode(oneTwoThreeAbcUdtBytes, &dest, version)
+					require.NoError(t, err)
+					assert.False(t, wasNull)
+					assert.Equal(t, taggedUdt{taggedUdtBase{123}, true, stringPtr("abc"), "untouched"}, dest)
+				})
+				t.Run("tagged nulls", func(t *testing.T) {
+					dest := taggedUdt{taggedUdtBase{1}, true, stringPtr("abc"), "untouched"}
+					wasNull, err := udtCodecSimple.Decode(nullElementsUdtBytes, &dest, version)
+					require.NoError(t, err)
+					assert.False(t, wasNull)
+					assert.Equal(t, taggedUdt{Ignored: "untouched"}, dest)
+				})
+				t.Run("embedded nil pointer", func(t *testing.T) {
+					var dest taggedUdtPointer
+					wasNull, err := udtCodecSimple.Decode(oneTwoThreeAbcUdtBytes, &dest, version)
+					require.NoError(t, err)
+					assert.False(t, wasNull)
+					assert.Equal(t, taggedUdtPointer{&EmbeddedUdtBase{123}, true, stringPtr("abc")}, dest)
+				})
+			})
+		})
+	}
+}
+
+func Test_tupleCodec_structTags(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersionsGreaterThanOrEqualTo(primitive.ProtocolVersion3) {
+		t.Run(version.String(), func(t *testing.T) {
+			t.Run("encode", func(t *testing.T) {
+				actual, err := tupleCodecSimple.Encode(taggedTuple{123, "ignored", true, stringPtr("abc")}, version)
+				require.NoError(t, err)
+				assert.Equal(t, tupleOneTwoThreeTrueAbcBytes, actual)
+			})
+			t.Run("encode omitempty", func(t *testing.T) {
+				actual, err := tupleCodecSimple.Encode(&taggedTuple{0, "ignored", false, nil}, version)
+				require.NoError(t, err)
+				assert.Equal(t, []byte{
+					0, 0, 0, 4, // length of int
+					0, 0, 0, 0, // int
+					255, 255, 255, 255, // nil boolean
+					255, 255, 255, 255, // nil string
+				}, actual)
+			})
+			t.Run("decode", func(t *testing.T) {
+				dest := taggedTuple{Skipped: "untouched"}
+				wasNull, err := tupleCodecSimple.Decode(tupleOneTwoThreeTrueAbcBytes, &dest, version)
+				require.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, taggedTuple{123, "untouched", true, stringPtr("abc")}, dest)
+			})
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// Registry creates codecs for CQL types, and allows callers to register their own codecs, either for a given CQL type,
+// or for a given Go type when used with a given CQL type.
+//
+// Codecs registered with Register are keyed by their CQL type, as determined by datatype.EqualIgnoringFrozen, since
+// frozenness is not transmitted on the wire, and replace the built-in codec for that type; this is typically used to
+// provide a real codec for a datatype.Custom class name.
+//
+// Codecs registered with RegisterGoType are keyed by both their CQL type and a Go type, and are only used when encoding
+// a value of that Go type (or a pointer thereto), or when decoding into a pointer to that Go type; for any other Go
+// type, the codec registered for the CQL type, or the built-in codec, is used. This is typically used to map a custom
+// Go type, e.g. a type CustomerID [16]byte, to an existing CQL type, e.g. uuid.
+//
+// Registered codecs are used recursively: a codec created by the registry for a list, set, map, tuple, user-defined
+// type or vector uses the registry to create the codecs of its elements. Codecs are resolved when created: registering
+// a codec has no effect on codecs previously created by the registry.
+//
+// A Registry is safe for concurrent use. The zero value is not usable; use NewRegistry to create new instances.
+type Registry struct {
+	lock sync.RWMutex
+	// codecs registered by CQL type, keyed by data type code.
+	codecs map[primitive.DataTypeCode][]Codec
+	// codecs registered by CQL and Go type, keyed by data type code.
+	goTypeCodecs map[primitive.DataTypeCode][]*goTypeCodec
+}
+
+// DefaultRegistry is the registry used by NewCodec and by all the codec constructor functions in this package. It
+// initially contains no registered codecs, and thus only provides the built-in codecs. Codecs registered with this
+// registry affect all codecs created afterwards by NewCodec, RowScanner and Binder, as well as the literal and JSON
+// conversion functions.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates a new Registry without any registered codec.
+func NewRegistry() *Registry {
+	return &Registry{
+		codecs:       map[primitive.DataTypeCode][]Codec{},
+		goTypeCodecs: map[primitive.DataTypeCode][]*goTypeCodec{},
+	}
+}
+
+// Register registers the given codec for its CQL type, replacing the codec previously registered for the same CQL
+// type, if any.
+func (r *Registry) Register(codec Codec) error {
+	if codec == nil {
+		return errors.New("cannot register nil codec")
+	}
+	dt := codec.DataType()
+	if dt == nil {
+		return errCannotRegisterCodec(codec, ErrNilDataType)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	codecs := r.codecs[dt.Code()]
+	for i, existing := range codecs {
+		if datatype.EqualIgnoringFrozen(existing.DataType(), dt) {
+			codecs[i] = codec
+			return nil
+		}
+	}
+	r.codecs[dt.Code()] = append(codecs, codec)
+	return nil
+}
+
+// RegisterGoType registers the given codec for its CQL type and the given Go type, replacing the codec previously
+// registered for the same CQL and Go types, if any. The Go type cannot be a pointer type nor an interface type. The
+// codec is invoked with the source and destination values unchanged, and thus must accept values of the Go type and
+// pointers thereto when encoding, and pointers to the Go type when decoding.
+func (r *Registry) RegisterGoType(goType reflect.Type, codec Codec) error {
+	if codec == nil {
+		return errors.New("cannot register nil codec")
+	}
+	dt := codec.DataType()
+	if dt == nil {
+		return errCannotRegisterCodec(codec, ErrNilDataType)
+	} else if goType == nil {
+		return errCannotRegisterCodec(codec, errors.New("Go type is nil"))
+	} else if goType.Kind() == reflect.Ptr || goType.Kind() == reflect.Interface {
+		return errCannotRegisterCodec(codec, fmt.Errorf("Go type %v is a pointer or interface type", goType))
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	codecs := r.goTypeCodecs[dt.Code()]
+	for i, existing := range codecs {
+		if existing.goType == goType && datatype.EqualIgnoringFrozen(existing.DataType(), dt) {
+			codecs[i] = &goTypeCodec{goType, codec}
+			return nil
+		}
+	}
+	r.goTypeCodecs[dt.Code()] = append(codecs, &goTypeCodec{goType, codec})
+	return nil
+}
+
+// NewCodec creates a new codec for the given data type. If a codec was registered for the data type, it is used,
+// otherwise a built-in codec is created, as explained in the package-level NewCodec function. If codecs were
+// registered for the data type and specific Go types, the returned codec dispatches to them depending on the Go type
+// of the values to encode or decode.
+func (r *Registry) NewCodec(dt datatype.DataType) (Codec, error) {
+	if dt == nil {
+		return nil, ErrNilDataType
+	}
+	codec, goTypeCodecs := r.lookup(dt)
+	if codec == nil {
+		var err error
+		if codec, err = r.newBuiltinCodec(dt); err != nil {
+			return nil, err
+		}
+	}
+	if len(goTypeCodecs) > 0 {
+		return &goTypeDispatchCodec{codec, goTypeCodecs}, nil
+	}
+	return codec, nil
+}
+
+func (r *Registry) lookup(dt datatype.DataType) (codec Codec, goTypeCodecs []*goTypeCodec) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, registered := range r.codecs[dt.Code()] {
+		if datatype.EqualIgnoringFrozen(registered.DataType(), dt) {
+			codec = registered
+			break
+		}
+	}
+	for _, registered := range r.goTypeCodecs[dt.Code()] {
+		if datatype.EqualIgnoringFrozen(registered.DataType(), dt) {
+			goTypeCodecs = append(goTypeCodecs, registered)
+		}
+	}
+	return
+}
+
+type goTypeCodec struct {
+	goType reflect.Type
+	Codec
+}
+
+// goTypeDispatchCodec delegates to the codec registered for the Go type of the value being encoded or decoded, if
+// any, and to the default codec otherwise.
+type goTypeDispatchCodec struct {
+	Codec
+	goTypeCodecs []*goTypeCodec
+}
+
+func (c *goTypeDispatchCodec) Encode(source interface{}, version primitive.ProtocolVersion) (dest []byte, err error) {
+	if sourceType := reflect.TypeOf(source); sourceType != nil {
+		if sourceType.Kind() == reflect.Ptr {
+			sourceType = sourceType.Elem()
+		}
+		for _, codec := range c.goTypeCodecs {
+			if codec.goType == sourceType {
+				return codec.Encode(source, version)
+			}
+		}
+	}
+	return c.Codec.Encode(source, version)
+}
+
+func (c *goTypeDispatchCodec) Decode(
+	source []byte,
+	dest interface{},
+	version primitive.ProtocolVersion,
+) (wasNull bool, err error) {
+	if destType := reflect.TypeOf(dest); destType != nil && destType.Kind() == reflect.Ptr {
+		for _, codec := range c.goTypeCodecs {
+			if codec.goType == destType.Elem() {
+				return codec.Decode(source, dest, version)
+			}
+		}
+	}
+	return c.Codec.Decode(source, dest, version)
+}
@@ -0,0 +1,224 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+type customerID [16]byte
+
+var typeOfCustomerID = reflect.TypeOf(customerID{})
+
+// customerIDCodec encodes customerID values to CQL uuid.
+type customerIDCodec struct{}
+
+func (c customerIDCodec) DataType() datatype.DataType {
+	return datatype.Uuid
+}
+
+func (c customerIDCodec) Encode(source interface{}, version primitive.ProtocolVersion) ([]byte, error) {
+	switch s := source.(type) {
+	case customerID:
+		return Uuid.Encode([16]byte(s), version)
+	case *customerID:
+		if s == nil {
+			return nil, nil
+		}
+		return Uuid.Encode([16]byte(*s), version)
+	}
+	return nil, errors.New("not a customerID")
+}
+
+func (c customerIDCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (bool, error) {
+	return Uuid.Decode(source, (*[16]byte)(dest.(*customerID)), version)
+}
+
+// upperCaseCodec encodes strings to a custom type, converting them to upper case.
+type upperCaseCodec struct {
+	dataType datatype.DataType
+}
+
+func (c upperCaseCodec) DataType() datatype.DataType {
+	return c.dataType
+}
+
+func (c upperCaseCodec) Encode(source interface{}, _ primitive.ProtocolVersion) ([]byte, error) {
+	return []byte("UPPER:" + source.(string)), nil
+}
+
+func (c upperCaseCodec) Decode(source []byte, dest interface{}, _ primitive.ProtocolVersion) (bool, error) {
+	*dest.(*string) = "decoded:" + string(source)
+	return source == nil, nil
+}
+
+// countingCodec delegates to another codec and counts its invocations.
+type countingCodec struct {
+	Codec
+	count *int
+}
+
+func (c countingCodec) Encode(source interface{}, version primitive.ProtocolVersion) ([]byte, error) {
+	*c.count++
+	return c.Codec.Encode(source, version)
+}
+
+func (c countingCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (bool, error) {
+	*c.count++
+	return c.Codec.Decode(source, dest, version)
+}
+
+var (
+	customerIDBytes = []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	customerID1     = customerID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+)
+
+func TestRegistry_Register(t *testing.T) {
+	customType := datatype.NewCustom("com.example.UpperCase")
+	codec := upperCaseCodec{customType}
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(codec))
+	t.Run("custom", func(t *testing.T) {
+		actual, err := registry.NewCodec(datatype.NewCustom("com.example.UpperCase"))
+		require.NoError(t, err)
+		assert.Equal(t, codec, actual)
+		actual, err = registry.NewCodec(datatype.NewCustom("com.example.Other"))
+		require.NoError(t, err)
+		assert.Equal(t, NewCustom(datatype.NewCustom("com.example.Other")), actual)
+	})
+	t.Run("recursive", func(t *testing.T) {
+		tupleType := datatype.NewTuple(datatype.Int, datatype.NewList(customType))
+		tupleCodec, err := registry.NewCodec(tupleType)
+		require.NoError(t, err)
+		encoded, err := tupleCodec.Encode([]interface{}{1, []string{"a"}}, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{
+			0, 0, 0, 4, 0, 0, 0, 1, // int
+			0, 0, 0, 15, // list length
+			0, 0, 0, 1, // list size
+			0, 0, 0, 7, 'U', 'P', 'P', 'E', 'R', ':', 'a', // element
+		}, encoded)
+		var decoded struct {
+			I int
+			L []string
+		}
+		wasNull, err := tupleCodec.Decode(encoded, &decoded, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.False(t, wasNull)
+		assert.Equal(t, 1, decoded.I)
+		assert.Equal(t, []string{"decoded:UPPER:a"}, decoded.L)
+	})
+	t.Run("default registry unaffected", func(t *testing.T) {
+		actual, err := NewCodec(customType)
+		require.NoError(t, err)
+		assert.Equal(t, NewCustom(customType), actual)
+	})
+	t.Run("replace", func(t *testing.T) {
+		replacement := upperCaseCodec{datatype.NewCustom("com.example.UpperCase")}
+		require.NoError(t, registry.Register(replacement))
+		actual, err := registry.NewCodec(customType)
+		require.NoError(t, err)
+		assert.Same(t, replacement.dataType, actual.DataType())
+	})
+	t.Run("built-in type", func(t *testing.T) {
+		intCodec := upperCaseCodec{datatype.Int}
+		require.NoError(t, registry.Register(intCodec))
+		actual, err := registry.NewCodec(datatype.Int)
+		require.NoError(t, err)
+		assert.Equal(t, intCodec, actual)
+		actual, err = registry.NewCodec(datatype.Bigint)
+		require.NoError(t, err)
+		assert.Equal(t, Bigint, actual)
+	})
+	t.Run("frozenness ignored", func(t *testing.T) {
+		frozenCodec := upperCaseCodec{&datatype.List{ElementType: datatype.Varchar, Frozen: true}}
+		require.NoError(t, registry.Register(frozenCodec))
+		// frozenness is not transmitted on the wire
+		actual, err := registry.NewCodec(datatype.NewList(datatype.Varchar))
+		require.NoError(t, err)
+		assert.Equal(t, frozenCodec, actual)
+		unfrozenCodec := upperCaseCodec{datatype.NewList(datatype.Varchar)}
+		require.NoError(t, registry.Register(unfrozenCodec))
+		actual, err = registry.NewCodec(&datatype.List{ElementType: datatype.Varchar, Frozen: true})
+		require.NoError(t, err)
+		assert.Equal(t, unfrozenCodec, actual)
+	})
+}
+
+func TestRegistry_RegisterGoType(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.RegisterGoType(typeOfCustomerID, customerIDCodec{}))
+	t.Run("simple", func(t *testing.T) {
+		codec, err := registry.NewCodec(datatype.Uuid)
+		require.NoError(t, err)
+		assert.Equal(t, datatype.Uuid, codec.DataType())
+		for _, source := range []interface{}{customerID1, &customerID1, primitive.UUID(customerID1), customerIDBytes} {
+			encoded, err := codec.Encode(source, primitive.ProtocolVersion5)
+			require.NoError(t, err)
+			assert.Equal(t, customerIDBytes, encoded)
+		}
+		var decoded customerID
+		wasNull, err := codec.Decode(customerIDBytes, &decoded, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.False(t, wasNull)
+		assert.Equal(t, customerID1, decoded)
+		var decodedUuid primitive.UUID
+		_, err = codec.Decode(customerIDBytes, &decodedUuid, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, primitive.UUID(customerID1), decodedUuid)
+		// other CQL types are not affected
+		codec, err = registry.NewCodec(datatype.Timeuuid)
+		require.NoError(t, err)
+		assert.Equal(t, Timeuuid, codec)
+	})
+	t.Run("recursive", func(t *testing.T) {
+		codec, err := registry.NewCodec(datatype.NewMap(datatype.Varchar, datatype.NewList(datatype.Uuid)))
+		require.NoError(t, err)
+		source := map[string][]customerID{"a": {customerID1}}
+		encoded, err := codec.Encode(source, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		var decoded map[string][]customerID
+		_, err = codec.Decode(encoded, &decoded, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, source, decoded)
+		var decodedPtr map[string][]*customerID
+		_, err = codec.Decode(encoded, &decodedPtr, primitive.ProtocolVersion5)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]*customerID{"a": {&customerID1}}, decodedPtr)
+	})
+	t.Run("default registry unaffected", func(t *testing.T) {
+		_, err := Uuid.Encode(customerID1, primitive.ProtocolVersion5)
+		assert.Error(t, err)
+	})
+}
+
+func TestRegistry_errors(t *testing.T) {
+	registry := NewRegistry()
+	assert.EqualError(t, registry.Register(nil), "cannot register nil codec")
+	assert.EqualError(t, registry.Register(upperCaseCodec{}), "cannot register codec for CQL type <nil>: data type is nil")
+	assert.EqualError(t, registry.RegisterGoType(typeOfCustomerID, nil), "cannot register nil codec")
+	assert.EqualError(t, registry.RegisterGoType(nil, customerIDCodec{}), "cannot register codec for CQL type uuid: Go type is nil")
+	assert.EqualError(t, registry.RegisterGoType(reflect.PtrTo(typeOfCustomerID), customerIDCodec{}), "cannot register codec for CQL type uuid: Go type *datacodec.customerID is a pointer or interface type")
+	_, err := registry.NewCodec(nil)
+	assert.Equal(t, ErrNilDataType, err)
+}
@@ -0,0 +1,162 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// RowScanner decodes rows of a message.RowsResult into Go values. A RowScanner is created from the result's
+// RowsMetadata; it creates one codec per column upfront, then reuses these codecs for every scanned row. A RowScanner
+// is safe for concurrent use.
+type RowScanner struct {
+	columns []*message.ColumnMetadata
+	codecs  []Codec
+	version primitive.ProtocolVersion
+}
+
+// NewRowScanner creates a new RowScanner for rows described by the given metadata and encoded with the given
+// protocol version. The metadata must contain column specs; an error is returned if the metadata has the NO_METADATA
+// flag set, or if a codec cannot be created for one of the columns. Codecs are created with the DefaultRegistry.
+func NewRowScanner(metadata *message.RowsMetadata, version primitive.ProtocolVersion) (*RowScanner, error) {
+	return DefaultRegistry.NewRowScanner(metadata, version)
+}
+
+// NewRowScanner creates a new RowScanner whose codecs are created by this registry; see the package-level
+// NewRowScanner function.
+func (r *Registry) NewRowScanner(metadata *message.RowsMetadata, version primitive.ProtocolVersion) (*RowScanner, error) {
+	if metadata == nil {
+		return nil, errors.New("cannot create row scanner: metadata is nil")
+	} else if len(metadata.Columns) == 0 {
+		return nil, errors.New("cannot create row scanner: metadata has no column specs")
+	}
+	codecs := make([]Codec, len(metadata.Columns))
+	for i, column := range metadata.Columns {
+		if codec, err := r.NewCodec(column.Type); err != nil {
+			return nil, fmt.Errorf("cannot create row scanner: %w", errCannotCreateColumnCodec(i, column.Name, err))
+		} else {
+			codecs[i] = codec
+		}
+	}
+	return &RowScanner{columns: metadata.Columns, codecs: codecs, version: version}, nil
+}
+
+// Columns returns the column specs of the rows scanned by this scanner.
+func (s *RowScanner) Columns() []*message.ColumnMetadata {
+	return s.columns
+}
+
+// Codecs returns the codecs used by this scanner, one per column.
+func (s *RowScanner) Codecs() []Codec {
+	return s.codecs
+}
+
+// Scan decodes the given row into dest. The following destinations are accepted:
+//
+//   - A pointer to a struct: columns are mapped to struct fields by name, using the same rules as for user-defined
+//     types, see the package documentation. Columns without a matching field are ignored.
+//   - A map with string keys, or a pointer to such a map: each column is stored under its name. If the pointer points
+//     to a nil map, a new map is allocated.
+//   - A slice of pointers, e.g. []interface{}{&id, &name}: each column is decoded into the pointer at the same position;
+//     the slice length must match the number of columns.
+//
+// In all cases, CQL NULLs are decoded as zero values.
+func (s *RowScanner) Scan(row message.Row, dest interface{}) (err error) {
+	if len(row) != len(s.columns) {
+		err = fmt.Errorf("expected row of %d columns, got: %d", len(s.columns), len(row))
+	} else if pointers, ok := dest.([]interface{}); ok {
+		err = s.scanPointers(row, pointers)
+	} else {
+		err = s.scanReflect(row, dest)
+	}
+	if err != nil {
+		err = fmt.Errorf("cannot scan row into %T: %w", dest, err)
+	}
+	return
+}
+
+func (s *RowScanner) scanPointers(row message.Row, pointers []interface{}) error {
+	if len(pointers) != len(s.columns) {
+		return fmt.Errorf("expected %d destinations, got: %d", len(s.columns), len(pointers))
+	}
+	for i, codec := range s.codecs {
+		if _, err := codec.Decode(row[i], pointers[i], s.version); err != nil {
+			return errCannotDecodeColumn(i, s.columns[i].Name, err)
+		}
+	}
+	return nil
+}
+
+func (s *RowScanner) scanReflect(row message.Row, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if !destValue.IsValid() {
+		return ErrNilDestination
+	} else if destValue.Kind() == reflect.Ptr {
+		if destValue.IsNil() {
+			return ErrNilDestination
+		}
+		destValue = destValue.Elem()
+	}
+	switch destValue.Kind() {
+	case reflect.Struct:
+		if !destValue.CanSet() {
+			return ErrPointerTypeExpected
+		}
+		inj, err := newStructInjector(destValue)
+		if err != nil {
+			return err
+		}
+		return s.scanInjector(row, inj, func(name string) bool {
+			return findStructFieldByName(destValue.Type(), name) != nil
+		})
+	case reflect.Map:
+		if destValue.Type().Key().Kind() != reflect.String {
+			return errWrongElementType("map key", typeOfString, destValue.Type().Key())
+		} else if destValue.IsNil() {
+			if !destValue.CanSet() {
+				return ErrNilDestination
+			}
+			destValue.Set(reflect.MakeMapWithSize(destValue.Type(), len(s.columns)))
+		}
+		inj, err := newMapInjector(destValue)
+		if err != nil {
+			return err
+		}
+		return s.scanInjector(row, inj, func(string) bool { return true })
+	}
+	return ErrDestinationTypeNotSupported
+}
+
+func (s *RowScanner) scanInjector(row message.Row, inj injector, accept func(name string) bool) error {
+	for i, codec := range s.codecs {
+		name := s.columns[i].Name
+		if !accept(name) {
+			continue
+		}
+		if decoded, err := inj.zeroElem(i, name); err != nil {
+			return errCannotCreateColumn(i, name, err)
+		} else if wasNull, err := codec.Decode(row[i], decoded, s.version); err != nil {
+			return errCannotDecodeColumn(i, name, err)
+		} else if err = inj.setElem(i, name, decoded, false, wasNull); err != nil {
+			return errCannotInjectColumn(i, name, err)
+		}
+	}
+	return nil
+}
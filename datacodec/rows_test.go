@@ -0,0 +1,161 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+var rowsMetadata = &message.RowsMetadata{
+	ColumnCount: 3,
+	Columns: []*message.ColumnMetadata{
+		{Keyspace: "ks1", Table: "table1", Name: "user_id", Index: 0, Type: datatype.Int},
+		{Keyspace: "ks1", Table: "table1", Name: "name", Index: 1, Type: datatype.Varchar},
+		{Keyspace: "ks1", Table: "table1", Name: "scores", Index: 2, Type: datatype.NewList(datatype.Int)},
+	},
+}
+
+type scannedRow struct {
+	UserId int32   `cql:"user_id"`
+	Name   *string `cql:"name"`
+	Scores []int
+	Other  string
+}
+
+func encodeRow(t *testing.T, version primitive.ProtocolVersion, values ...interface{}) message.Row {
+	row := make(message.Row, len(values))
+	for i, value := range values {
+		codec, err := NewCodec(rowsMetadata.Columns[i].Type)
+		require.NoError(t, err)
+		row[i], err = codec.Encode(value, version)
+		require.NoError(t, err)
+	}
+	return row
+}
+
+func TestNewRowScanner(t *testing.T) {
+	scanner, err := NewRowScanner(rowsMetadata, primitive.ProtocolVersion4)
+	require.NoError(t, err)
+	assert.Equal(t, rowsMetadata.Columns, scanner.Columns())
+	assert.Equal(t, []Codec{Int, Varchar, scanner.Codecs()[2]}, scanner.Codecs())
+	assert.Equal(t, datatype.NewList(datatype.Int), scanner.Codecs()[2].DataType())
+	t.Run("nil metadata", func(t *testing.T) {
+		_, err := NewRowScanner(nil, primitive.ProtocolVersion4)
+		assert.EqualError(t, err, "cannot create row scanner: metadata is nil")
+	})
+	t.Run("no metadata", func(t *testing.T) {
+		_, err := NewRowScanner(&message.RowsMetadata{ColumnCount: 1}, primitive.ProtocolVersion4)
+		assert.EqualError(t, err, "cannot create row scanner: metadata has no column specs")
+	})
+	t.Run("wrong data type", func(t *testing.T) {
+		_, err := NewRowScanner(&message.RowsMetadata{
+			ColumnCount: 1,
+			Columns:     []*message.ColumnMetadata{{Name: "col1", Type: wrongDataType{}}},
+		}, primitive.ProtocolVersion4)
+		assert.EqualError(t, err, "cannot create row scanner: cannot create codec for column 0 (col1): cannot create data codec for CQL type 666")
+	})
+	t.Run("registry", func(t *testing.T) {
+		registry := NewRegistry()
+		intCodec := upperCaseCodec{datatype.Int}
+		require.NoError(t, registry.Register(intCodec))
+		scanner, err := registry.NewRowScanner(rowsMetadata, primitive.ProtocolVersion4)
+		require.NoError(t, err)
+		assert.Equal(t, intCodec, scanner.Codecs()[0])
+	})
+}
+
+func TestRowScanner_Scan(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			scanner, err := NewRowScanner(rowsMetadata, version)
+			require.NoError(t, err)
+			row := encodeRow(t, version, 123, "abc", []int{1, 2})
+			nullRow := message.Row{nil, nil, nil}
+			t.Run("struct", func(t *testing.T) {
+				dest := scannedRow{Other: "untouched"}
+				err := scanner.Scan(row, &dest)
+				require.NoError(t, err)
+				assert.Equal(t, scannedRow{123, stringPtr("abc"), []int{1, 2}, "untouched"}, dest)
+			})
+			t.Run("struct nulls", func(t *testing.T) {
+				dest := scannedRow{123, stringPtr("abc"), []int{1, 2}, "untouched"}
+				err := scanner.Scan(nullRow, &dest)
+				require.NoError(t, err)
+				assert.Equal(t, scannedRow{Other: "untouched"}, dest)
+			})
+			t.Run("struct not pointer", func(t *testing.T) {
+				err := scanner.Scan(row, scannedRow{})
+				assert.EqualError(t, err, "cannot scan row into datacodec.scannedRow: destination is not pointer")
+			})
+			t.Run("map", func(t *testing.T) {
+				dest := map[string]interface{}{}
+				err := scanner.Scan(row, dest)
+				require.NoError(t, err)
+				assert.Equal(t, map[string]interface{}{"user_id": int32(123), "name": "abc", "scores": []*int32{int32Ptr(1), int32Ptr(2)}}, dest)
+			})
+			t.Run("map nil pointer", func(t *testing.T) {
+				var dest map[string]interface{}
+				err := scanner.Scan(nullRow, &dest)
+				require.NoError(t, err)
+				assert.Equal(t, map[string]interface{}{"user_id": nil, "name": nil, "scores": nil}, dest)
+			})
+			t.Run("map wrong key", func(t *testing.T) {
+				err := scanner.Scan(row, map[int]interface{}{})
+				assert.EqualError(t, err, "cannot scan row into map[int]interface {}: wrong map key, expected string, got: int")
+			})
+			t.Run("pointers", func(t *testing.T) {
+				var id int
+				var name string
+				var scores []int64
+				err := scanner.Scan(row, []interface{}{&id, &name, &scores})
+				require.NoError(t, err)
+				assert.Equal(t, 123, id)
+				assert.Equal(t, "abc", name)
+				assert.Equal(t, []int64{1, 2}, scores)
+			})
+			t.Run("pointers wrong length", func(t *testing.T) {
+				var id int
+				err := scanner.Scan(row, []interface{}{&id})
+				assert.EqualError(t, err, "cannot scan row into []interface {}: expected 3 destinations, got: 1")
+			})
+			t.Run("pointers wrong type", func(t *testing.T) {
+				var id int
+				var name float64
+				var scores []int
+				err := scanner.Scan(row, []interface{}{&id, &name, &scores})
+				assert.Contains(t, err.Error(), "cannot scan row into []interface {}: cannot decode column 1 (name)")
+			})
+			t.Run("wrong row length", func(t *testing.T) {
+				err := scanner.Scan(row[:2], &scannedRow{})
+				assert.EqualError(t, err, "cannot scan row into *datacodec.scannedRow: expected row of 3 columns, got: 2")
+			})
+			t.Run("nil destination", func(t *testing.T) {
+				err := scanner.Scan(row, nil)
+				assert.EqualError(t, err, "cannot scan row into <nil>: destination is nil")
+			})
+			t.Run("unsupported destination", func(t *testing.T) {
+				err := scanner.Scan(row, new(int))
+				assert.EqualError(t, err, "cannot scan row into *int: destination type not supported")
+			})
+		})
+	}
+}
@@ -43,11 +43,24 @@ func (c *smallintCodec) Encode(source interface{}, version primitive.ProtocolVer
 		}
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *smallintCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	if !version.SupportsDataType(c.DataType().Code()) {
+		return appendEncoded(c, dest, source, version)
+	}
+	var val int16
+	if val, wasNil, err = convertToInt16(source); err != nil {
+		return appendEncoded(c, dest, source, version)
+	} else if !wasNil {
+		dest = appendInt16(dest, val)
+	}
+	return dest, wasNil, nil
+}
+
 func (c *smallintCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	if !version.SupportsDataType(c.DataType().Code()) {
 		wasNull = len(source) == 0
@@ -59,7 +72,7 @@ func (c *smallintCodec) Decode(source []byte, dest interface{}, version primitiv
 		}
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -250,6 +263,10 @@ func convertFromInt16(val int16, wasNull bool, dest interface{}) (err error) {
 	return
 }
 
+func appendInt16(dest []byte, val int16) []byte {
+	return append(dest, byte(val>>8), byte(val))
+}
+
 func writeInt16(val int16) (dest []byte) {
 	dest = make([]byte, primitive.LengthOfShort)
 	binary.BigEndian.PutUint16(dest, uint16(val))
@@ -109,11 +109,24 @@ func (c *timeCodec) Encode(source interface{}, version primitive.ProtocolVersion
 		}
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *timeCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	if !version.SupportsDataType(c.DataType().Code()) {
+		return appendEncoded(c, dest, source, version)
+	}
+	var val int64
+	if val, wasNil, err = convertToInt64Time(source, c.layout); err != nil {
+		return appendEncoded(c, dest, source, version)
+	} else if !wasNil {
+		dest = appendInt64(dest, val)
+	}
+	return dest, wasNil, nil
+}
+
 func (c *timeCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	if !version.SupportsDataType(c.DataType().Code()) {
 		wasNull = len(source) == 0
@@ -125,7 +138,7 @@ func (c *timeCodec) Decode(source []byte, dest interface{}, version primitive.Pr
 		}
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -105,18 +105,28 @@ func (c *timestampCodec) Encode(source interface{}, version primitive.ProtocolVe
 		dest = writeInt64(val)
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *timestampCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	var val int64
+	if val, wasNil, err = convertToInt64Timestamp(source, c.layout, c.location); err != nil {
+		return appendEncoded(c, dest, source, version)
+	} else if !wasNil {
+		dest = appendInt64(dest, val)
+	}
+	return dest, wasNil, nil
+}
+
 func (c *timestampCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	var val int64
 	if val, wasNull, err = readInt64(source); err == nil {
 		err = convertFromInt64Timestamp(val, wasNull, dest, c.layout, c.location)
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
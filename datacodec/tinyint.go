@@ -43,11 +43,24 @@ func (c *tinyintCodec) Encode(source interface{}, version primitive.ProtocolVers
 		}
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *tinyintCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	if !version.SupportsDataType(c.DataType().Code()) {
+		return appendEncoded(c, dest, source, version)
+	}
+	var val int8
+	if val, wasNil, err = convertToInt8(source); err != nil {
+		return appendEncoded(c, dest, source, version)
+	} else if !wasNil {
+		dest = append(dest, byte(val))
+	}
+	return dest, wasNil, nil
+}
+
 func (c *tinyintCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	if !version.SupportsDataType(c.DataType().Code()) {
 		wasNull = len(source) == 0
@@ -59,7 +72,7 @@ func (c *tinyintCodec) Decode(source []byte, dest interface{}, version primitive
 		}
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
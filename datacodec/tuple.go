@@ -24,12 +24,16 @@ import (
 )
 
 func NewTuple(tupleType *datatype.Tuple) (Codec, error) {
+	return DefaultRegistry.newTuple(tupleType)
+}
+
+func (r *Registry) newTuple(tupleType *datatype.Tuple) (Codec, error) {
 	if tupleType == nil {
 		return nil, ErrNilDataType
 	}
 	elementCodecs := make([]Codec, len(tupleType.FieldTypes))
 	for i, elementType := range tupleType.FieldTypes {
-		if elementCodec, err := NewCodec(elementType); err != nil {
+		if elementCodec, err := r.NewCodec(elementType); err != nil {
 			return nil, fmt.Errorf("cannot create codec for tuple element %d: %w", i, err)
 		} else {
 			elementCodecs[i] = elementCodec
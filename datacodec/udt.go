@@ -24,12 +24,16 @@ import (
 )
 
 func NewUserDefined(dataType *datatype.UserDefined) (Codec, error) {
+	return DefaultRegistry.newUserDefined(dataType)
+}
+
+func (r *Registry) newUserDefined(dataType *datatype.UserDefined) (Codec, error) {
 	if dataType == nil {
 		return nil, ErrNilDataType
 	}
 	fieldCodecs := make([]Codec, len(dataType.FieldTypes))
 	for i, fieldType := range dataType.FieldTypes {
-		if fieldCodec, err := NewCodec(fieldType); err != nil {
+		if fieldCodec, err := r.NewCodec(fieldType); err != nil {
 			return nil, fmt.Errorf("cannot create codec for user-defined type field %d (%s): %w", i, dataType.FieldNames[i], err)
 		} else {
 			fieldCodecs[i] = fieldCodec
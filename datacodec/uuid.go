@@ -15,6 +15,8 @@
 package datacodec
 
 import (
+	"time"
+
 	"github.com/datastax/go-cassandra-native-protocol/datatype"
 	"github.com/datastax/go-cassandra-native-protocol/primitive"
 )
@@ -27,8 +29,10 @@ var Uuid Codec = &uuidCodec{dataType: datatype.Uuid}
 
 // Timeuuid is a codec for the CQL timeuuid type. Out of better options available in Go's standard library, its
 // preferred Go type is primitive.Uuid, but it can encode from and decode to []byte, [16]byte and string as well.
-// This codec does not actually enforce that user-provided UUIDs are time UUIDs; it is functionally equivalent to
-// the codecs returned by NewUuid.
+// It also encodes time.Time as the smallest timeuuid for that time, see primitive.MinTimeUuid, which is convenient
+// for range query bounds, e.g. WHERE t >= ?; use primitive.MaxTimeUuid for upper bounds. It decodes to time.Time by
+// extracting the UUID timestamp. Other than that, this codec does not actually enforce that user-provided UUIDs are
+// time UUIDs; it is functionally equivalent to the Uuid codec.
 // When dealing with UUIDs in Go, consider using a high-level library such as Google's uuid package:
 // https://pkg.go.dev/github.com/google/uuid.
 var Timeuuid Codec = &uuidCodec{dataType: datatype.Timeuuid}
@@ -42,23 +46,58 @@ func (c *uuidCodec) DataType() datatype.DataType {
 }
 
 func (c *uuidCodec) Encode(source interface{}, version primitive.ProtocolVersion) (dest []byte, err error) {
-	if dest, err = convertToUuidBytes(source); err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+	if dest, err = c.convertToBytes(source); err != nil {
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *uuidCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	if u, ok := source.(primitive.UUID); ok {
+		return append(dest, u[:]...), false, nil
+	}
+	var val []byte
+	if val, err = c.convertToBytes(source); err != nil {
+		return appendEncoded(c, dest, source, version)
+	}
+	return append(dest, val...), val == nil, nil
+}
+
 func (c *uuidCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	var val []byte
 	if val, wasNull, err = readUuid(source); err == nil {
-		err = convertFromUuidBytes(val, wasNull, dest)
+		err = c.convertFromBytes(val, wasNull, dest)
 	}
 	if err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
 
+// convertToBytes converts the given source to raw UUID bytes; timeuuid codecs also accept time.Time.
+func (c *uuidCodec) convertToBytes(source interface{}) (val []byte, err error) {
+	if c.dataType.Code() == primitive.DataTypeCodeTimeuuid {
+		switch s := source.(type) {
+		case time.Time:
+			return primitive.MinTimeUuid(s).Bytes(), nil
+		case *time.Time:
+			if s != nil {
+				return primitive.MinTimeUuid(*s).Bytes(), nil
+			}
+			return nil, nil
+		}
+	}
+	return convertToUuidBytes(source)
+}
+
+// convertFromBytes converts the given raw UUID bytes to dest; timeuuid codecs also accept *time.Time.
+func (c *uuidCodec) convertFromBytes(val []byte, wasNull bool, dest interface{}) error {
+	if d, ok := dest.(*time.Time); ok && c.dataType.Code() == primitive.DataTypeCodeTimeuuid {
+		return convertFromTimeuuidBytes(val, wasNull, d)
+	}
+	return convertFromUuidBytes(val, wasNull, dest)
+}
+
 func convertToUuidBytes(source interface{}) (val []byte, err error) {
 	switch s := source.(type) {
 	case primitive.UUID:
@@ -165,6 +204,22 @@ func convertFromUuidBytes(val []byte, wasNull bool, dest interface{}) (err error
 	return
 }
 
+func convertFromTimeuuidBytes(val []byte, wasNull bool, dest *time.Time) (err error) {
+	if dest == nil {
+		err = ErrNilDestination
+	} else if wasNull {
+		*dest = time.Time{}
+	} else {
+		var uuid primitive.UUID
+		copy(uuid[:], val)
+		*dest, err = uuid.Time()
+	}
+	if err != nil {
+		err = errDestinationConversionFailed(val, dest, err)
+	}
+	return
+}
+
 // The below function is roughly equivalent to primitive.ReadUuid.
 
 func readUuid(source []byte) (val []byte, wasNull bool, err error) {
@@ -17,6 +17,7 @@ package datacodec
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -27,6 +28,9 @@ import (
 var (
 	uuid      = primitive.UUID{0xC0, 0xD1, 0xD2, 0x1E, 0xBB, 0x01, 0x41, 0x96, 0x86, 0xDB, 0xBC, 0x31, 0x7B, 0xC1, 0x79, 0x6A}
 	uuidBytes = []byte{0xC0, 0xD1, 0xD2, 0x1E, 0xBB, 0x01, 0x41, 0x96, 0x86, 0xDB, 0xBC, 0x31, 0x7B, 0xC1, 0x79, 0x6A}
+	// 07123c50-7ce6-11eb-8080-808080808080, that is, minTimeuuid('2021-03-04 12:34:56.789+0000')
+	timeuuidTime     = time.Date(2021, 3, 4, 12, 34, 56, 789000000, time.UTC)
+	timeuuidMinBytes = []byte{0x07, 0x12, 0x3C, 0x50, 0x7C, 0xE6, 0x11, 0xEB, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80}
 )
 
 func Test_uuidCodec_DataType(t *testing.T) {
@@ -206,3 +210,60 @@ func Test_readUuid(t *testing.T) {
 		})
 	}
 }
+
+func Test_timeuuidCodec_Time(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			t.Run("encode", func(t *testing.T) {
+				tests := []struct {
+					name     string
+					source   interface{}
+					expected []byte
+					err      string
+				}{
+					{"time.Time", timeuuidTime, timeuuidMinBytes, ""},
+					{"time.Time sub-millisecond", timeuuidTime.Add(123456), timeuuidMinBytes, ""},
+					{"*time.Time", &timeuuidTime, timeuuidMinBytes, ""},
+					{"*time.Time nil", timeNilPtr(), nil, ""},
+				}
+				for _, tt := range tests {
+					t.Run(tt.name, func(t *testing.T) {
+						actual, err := Timeuuid.Encode(tt.source, version)
+						assert.Equal(t, tt.expected, actual)
+						assertErrorMessage(t, tt.err, err)
+						appended, wasNil, err := Timeuuid.(AppendEncoder).AppendEncode([]byte{1}, tt.source, version)
+						assert.Equal(t, append([]byte{1}, tt.expected...), appended)
+						assert.Equal(t, tt.expected == nil, wasNil)
+						assertErrorMessage(t, tt.err, err)
+					})
+				}
+				// uuid codecs do not accept time.Time, but fall back to its text marshaler
+				_, err := Uuid.Encode(timeuuidTime, version)
+				assertErrorMessage(t, "invalid UUID: \"2021-03-04T12:34:56.789Z\"", err)
+			})
+			t.Run("decode", func(t *testing.T) {
+				tests := []struct {
+					name     string
+					source   []byte
+					dest     interface{}
+					expected interface{}
+					wasNull  bool
+					err      string
+				}{
+					{"null", nil, new(time.Time), new(time.Time), true, ""},
+					{"non null", timeuuidMinBytes, new(time.Time), &timeuuidTime, false, ""},
+					{"nil dest", timeuuidMinBytes, timeNilPtr(), timeNilPtr(), false, "destination is nil"},
+					{"not a timeuuid", uuidBytes, new(time.Time), new(time.Time), false, fmt.Sprintf("cannot decode CQL timeuuid as *time.Time with %v: cannot convert from []uint8 to *time.Time: cannot extract timestamp from UUID c0d1d21e-bb01-4196-86db-bc317bc1796a: not a time-based UUID (version 4)", version)},
+				}
+				for _, tt := range tests {
+					t.Run(tt.name, func(t *testing.T) {
+						wasNull, err := Timeuuid.Decode(tt.source, tt.dest, version)
+						assert.Equal(t, tt.expected, tt.dest)
+						assert.Equal(t, tt.wasNull, wasNull)
+						assertErrorMessage(t, tt.err, err)
+					})
+				}
+			})
+		})
+	}
+}
@@ -39,14 +39,25 @@ func (c *stringCodec) DataType() datatype.DataType {
 
 func (c *stringCodec) Encode(source interface{}, version primitive.ProtocolVersion) (dest []byte, err error) {
 	if dest, err = convertToStringBytes(source); err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
 
+func (c *stringCodec) AppendEncode(dest []byte, source interface{}, version primitive.ProtocolVersion) (result []byte, wasNil bool, err error) {
+	if s, ok := source.(string); ok {
+		return append(dest, s...), false, nil
+	}
+	var val []byte
+	if val, err = convertToStringBytes(source); err != nil {
+		return appendEncoded(c, dest, source, version)
+	}
+	return append(dest, val...), val == nil, nil
+}
+
 func (c *stringCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
 	if wasNull, err = convertFromStringBytes(source, dest); err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -35,10 +35,10 @@ func (c *varintCodec) DataType() datatype.DataType {
 func (c *varintCodec) Encode(source interface{}, version primitive.ProtocolVersion) (dest []byte, err error) {
 	var val *big.Int
 	if val, err = convertToBigInt(source); err == nil && val != nil {
-		dest = val.Bytes()
+		dest = writeBigInt(val)
 	}
 	if err != nil {
-		err = errCannotEncode(source, c.DataType(), version, err)
+		dest, err = encodeFallback(c, source, version, errCannotEncode(source, c.DataType(), version, err))
 	}
 	return
 }
@@ -47,7 +47,7 @@ func (c *varintCodec) Decode(source []byte, dest interface{}, version primitive.
 	val := readBigInt(source)
 	wasNull = val == nil
 	if err = convertFromBigInt(val, wasNull, dest); err != nil {
-		err = errCannotDecode(dest, c.DataType(), version, err)
+		err = decodeFallback(c, source, dest, version, errCannotDecode(dest, c.DataType(), version, err))
 	}
 	return
 }
@@ -47,6 +47,8 @@ func Test_varintCodec_Encode(t *testing.T) {
 				{"nil", nil, nil, ""},
 				{"nil pointer", bigIntNilPtr(), nil, ""},
 				{"non nil", oneBigInt, []byte{1}, ""},
+				{"negative", big.NewInt(-100), []byte{0x9c}, ""},
+				{"high bit set", big.NewInt(128), []byte{0x00, 0x80}, ""},
 				{"conversion failed", float64(0), nil, fmt.Sprintf("cannot encode float64 as CQL varint with %v: cannot convert from float64 to *big.Int: conversion not supported", version)},
 			}
 			for _, tt := range tests {
@@ -0,0 +1,224 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// NewVector returns a codec for the CQL vector type. Vectors are encoded as exactly Dimensions elements, without any
+// leading element count; NULL elements are not allowed. Elements of fixed-length types (e.g. float, int, uuid) are
+// packed back to back, while elements of variable-length types are each prefixed with their length encoded as an
+// [unsigned vint]. The preferred Go type is a slice of the element's preferred Go type, e.g. []float32 for
+// vector<float, N>; arrays are also accepted, provided that their length matches the vector dimensions.
+func NewVector(dataType *datatype.Vector) (Codec, error) {
+	return DefaultRegistry.newVector(dataType)
+}
+
+func (r *Registry) newVector(dataType *datatype.Vector) (Codec, error) {
+	if dataType == nil {
+		return nil, ErrNilDataType
+	} else if dataType.Dimensions <= 0 {
+		return nil, errVectorDimensionsInvalid(dataType.Dimensions)
+	}
+	elementCodec, err := r.NewCodec(dataType.ElementType)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create codec for vector elements: %w", err)
+	}
+	return &vectorCodec{
+		dataType:      dataType,
+		elementCodec:  elementCodec,
+		elementLength: fixedValueLength(dataType.ElementType),
+	}, nil
+}
+
+type vectorCodec struct {
+	dataType     *datatype.Vector
+	elementCodec Codec
+	// the fixed length of each serialized element, or -1 if elements have variable lengths.
+	elementLength int
+}
+
+func (c *vectorCodec) DataType() datatype.DataType {
+	return c.dataType
+}
+
+func (c *vectorCodec) Encode(source interface{}, version primitive.ProtocolVersion) (dest []byte, err error) {
+	sourceValue, sourceType, wasNil := reflectSource(source)
+	if sourceType != nil {
+		switch sourceType.Kind() {
+		case reflect.Slice, reflect.Array:
+			if !wasNil {
+				var ext extractor
+				if ext, err = newSliceExtractor(sourceValue); err == nil {
+					if size := sourceValue.Len(); size != c.dataType.Dimensions {
+						err = errWrongVectorSize(c.dataType.Dimensions, size)
+					} else {
+						dest, err = c.writeVector(ext, version)
+					}
+				}
+			}
+		default:
+			err = ErrSourceTypeNotSupported
+		}
+	}
+	if err != nil {
+		err = errCannotEncode(source, c.DataType(), version, err)
+	}
+	return
+}
+
+func (c *vectorCodec) Decode(source []byte, dest interface{}, version primitive.ProtocolVersion) (wasNull bool, err error) {
+	wasNull = len(source) == 0
+	var inj injector
+	if inj, err = c.createInjector(dest, wasNull); err == nil && inj != nil {
+		err = c.readVector(source, inj, version)
+	}
+	if err != nil {
+		err = errCannotDecode(dest, c.DataType(), version, err)
+	}
+	return
+}
+
+func (c *vectorCodec) createInjector(dest interface{}, wasNull bool) (inj injector, err error) {
+	destValue, err := reflectDest(dest, wasNull)
+	if err == nil {
+		switch destValue.Kind() {
+		case reflect.Slice:
+			if !wasNull {
+				adjustSliceLength(destValue, c.dataType.Dimensions)
+				inj, err = newSliceInjector(destValue)
+			}
+		case reflect.Array:
+			if !wasNull {
+				if destValue.Len() != c.dataType.Dimensions {
+					err = errWrongVectorSize(c.dataType.Dimensions, destValue.Len())
+				} else {
+					inj, err = newSliceInjector(destValue)
+				}
+			}
+		case reflect.Interface:
+			if !wasNull {
+				var targetType reflect.Type
+				if targetType, err = PreferredGoType(c.DataType()); err == nil {
+					destValue.Set(reflect.MakeSlice(targetType, c.dataType.Dimensions, c.dataType.Dimensions))
+					inj, err = newSliceInjector(destValue.Elem())
+				}
+			}
+		default:
+			err = ErrDestinationTypeNotSupported
+		}
+	}
+	return
+}
+
+func (c *vectorCodec) writeVector(ext extractor, version primitive.ProtocolVersion) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	for i := 0; i < c.dataType.Dimensions; i++ {
+		if elem, err := ext.getElem(i, i); err != nil {
+			return nil, errCannotExtractElement(i, err)
+		} else if encodedElem, err := c.elementCodec.Encode(elem, version); err != nil {
+			return nil, errCannotEncodeElement(i, err)
+		} else if encodedElem == nil {
+			return nil, errCannotEncodeElement(i, ErrVectorNullElement)
+		} else if c.elementLength >= 0 {
+			if len(encodedElem) != c.elementLength {
+				return nil, errCannotEncodeElement(i, errWrongFixedLength(c.elementLength, len(encodedElem)))
+			}
+			buf.Write(encodedElem)
+		} else {
+			_, _ = primitive.WriteUnsignedVint(uint64(len(encodedElem)), buf)
+			buf.Write(encodedElem)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *vectorCodec) readVector(source []byte, inj injector, version primitive.ProtocolVersion) error {
+	reader := bytes.NewReader(source)
+	total := len(source)
+	for i := 0; i < c.dataType.Dimensions; i++ {
+		if encodedElem, err := c.readElement(reader); err != nil {
+			return errCannotReadElement(i, err)
+		} else if decodedElem, err := inj.zeroElem(i, i); err != nil {
+			return errCannotCreateElement(i, err)
+		} else if elementWasNull, err := c.elementCodec.Decode(encodedElem, decodedElem, version); err != nil {
+			return errCannotDecodeElement(i, err)
+		} else if err = inj.setElem(i, i, decodedElem, false, elementWasNull); err != nil {
+			return errCannotInjectElement(i, err)
+		}
+	}
+	if remaining := reader.Len(); remaining != 0 {
+		return errBytesRemaining(total, remaining)
+	}
+	return nil
+}
+
+func (c *vectorCodec) readElement(reader *bytes.Reader) ([]byte, error) {
+	return readVectorElement(reader, c.elementLength)
+}
+
+// readVectorElement reads a vector element of the given length, or, if length is negative, a vector element prefixed
+// with its length as an unsigned vint.
+func readVectorElement(reader *bytes.Reader, length int) ([]byte, error) {
+	if length < 0 {
+		if unsigned, _, err := primitive.ReadUnsignedVint(reader); err != nil {
+			return nil, err
+		} else if unsigned > uint64(reader.Len()) {
+			return nil, errWrongMinimumLength(int(unsigned), reader.Len())
+		} else {
+			length = int(unsigned)
+		}
+	}
+	encodedElem := make([]byte, length)
+	if _, err := io.ReadFull(reader, encodedElem); err != nil {
+		return nil, err
+	}
+	return encodedElem, nil
+}
+
+// fixedValueLength returns the length of serialized values of the given type, if that length is fixed, or -1 if
+// serialized values of the given type have variable lengths.
+func fixedValueLength(dt datatype.DataType) int {
+	if vectorType, ok := dt.(*datatype.Vector); ok {
+		if elementLength := fixedValueLength(vectorType.ElementType); elementLength >= 0 {
+			return elementLength * vectorType.Dimensions
+		}
+		return -1
+	}
+	switch dt.Code() {
+	case primitive.DataTypeCodeBoolean, primitive.DataTypeCodeTinyint:
+		return 1
+	case primitive.DataTypeCodeSmallint:
+		return 2
+	case primitive.DataTypeCodeInt, primitive.DataTypeCodeFloat, primitive.DataTypeCodeDate:
+		return 4
+	case primitive.DataTypeCodeBigint,
+		primitive.DataTypeCodeCounter,
+		primitive.DataTypeCodeDouble,
+		primitive.DataTypeCodeTime,
+		primitive.DataTypeCodeTimestamp:
+		return 8
+	case primitive.DataTypeCodeUuid, primitive.DataTypeCodeTimeuuid:
+		return 16
+	}
+	return -1
+}
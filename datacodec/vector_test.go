@@ -0,0 +1,156 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+var (
+	vectorOfFloat3, _   = NewVector(datatype.NewVector(datatype.Float, 3))
+	vectorOfVarchar2, _ = NewVector(datatype.NewVector(datatype.Varchar, 2))
+)
+
+var (
+	vectorFloat123Bytes = []byte{
+		0x3f, 0x80, 0, 0, // 1.0
+		0x40, 0, 0, 0, // 2.0
+		0x40, 0x40, 0, 0, // 3.0
+	}
+	vectorAbcDeBytes = []byte{
+		3, a, b, c, // unsigned vint length + element
+		2, d, e,
+	}
+)
+
+func TestNewVector(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		codec, err := NewVector(nil)
+		assert.Nil(t, codec)
+		assert.Equal(t, ErrNilDataType, err)
+	})
+	t.Run("invalid dimensions", func(t *testing.T) {
+		codec, err := NewVector(datatype.NewVector(datatype.Float, 0))
+		assert.Nil(t, codec)
+		assert.EqualError(t, err, "expected vector dimensions > 0, got: 0")
+	})
+	t.Run("from custom", func(t *testing.T) {
+		codec, err := NewCodec(datatype.NewCustom("org.apache.cassandra.db.marshal.VectorType(org.apache.cassandra.db.marshal.FloatType,3)"))
+		require.NoError(t, err)
+		assert.Equal(t, datatype.NewVector(datatype.Float, 3), codec.DataType())
+		goType, err := PreferredGoType(codec.DataType())
+		require.NoError(t, err)
+		assert.Equal(t, "[]float32", goType.String())
+	})
+}
+
+func Test_vectorCodec_Encode(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			tests := []struct {
+				name     string
+				codec    Codec
+				source   interface{}
+				expected []byte
+				err      string
+			}{
+				{"nil", vectorOfFloat3, nil, nil, ""},
+				{"nil slice", vectorOfFloat3, []float32(nil), nil, ""},
+				{"float slice", vectorOfFloat3, []float32{1, 2, 3}, vectorFloat123Bytes, ""},
+				{"float array", vectorOfFloat3, [3]float32{1, 2, 3}, vectorFloat123Bytes, ""},
+				{"float array pointer", vectorOfFloat3, &[3]float32{1, 2, 3}, vectorFloat123Bytes, ""},
+				{"float64 slice", vectorOfFloat3, []float64{1, 2, 3}, vectorFloat123Bytes, ""},
+				{"varchar slice", vectorOfVarchar2, []string{"abc", "de"}, vectorAbcDeBytes, ""},
+				{"wrong size", vectorOfFloat3, []float32{1, 2}, nil, "expected vector of 3 elements, got: 2"},
+				{"nil element", vectorOfVarchar2, []*string{stringPtr("abc"), nil}, nil, "cannot encode element 1: vector elements cannot be null"},
+				{"wrong type", vectorOfFloat3, 123, nil, "source type not supported"},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					actual, err := tt.codec.Encode(tt.source, version)
+					assert.Equal(t, tt.expected, actual)
+					if tt.err == "" {
+						assert.NoError(t, err)
+					} else {
+						assert.Contains(t, err.Error(), tt.err)
+					}
+				})
+			}
+		})
+	}
+}
+
+func Test_vectorCodec_Decode(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			t.Run("float slice", func(t *testing.T) {
+				var dest []float32
+				wasNull, err := vectorOfFloat3.Decode(vectorFloat123Bytes, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, []float32{1, 2, 3}, dest)
+			})
+			t.Run("float array", func(t *testing.T) {
+				var dest [3]float32
+				wasNull, err := vectorOfFloat3.Decode(vectorFloat123Bytes, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, [3]float32{1, 2, 3}, dest)
+			})
+			t.Run("interface", func(t *testing.T) {
+				var dest interface{}
+				wasNull, err := vectorOfFloat3.Decode(vectorFloat123Bytes, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, []float32{1, 2, 3}, dest)
+			})
+			t.Run("varchar slice", func(t *testing.T) {
+				var dest []string
+				wasNull, err := vectorOfVarchar2.Decode(vectorAbcDeBytes, &dest, version)
+				assert.NoError(t, err)
+				assert.False(t, wasNull)
+				assert.Equal(t, []string{"abc", "de"}, dest)
+			})
+			t.Run("null", func(t *testing.T) {
+				dest := []float32{1}
+				wasNull, err := vectorOfFloat3.Decode(nil, &dest, version)
+				assert.NoError(t, err)
+				assert.True(t, wasNull)
+				assert.Nil(t, dest)
+			})
+			t.Run("wrong array size", func(t *testing.T) {
+				var dest [2]float32
+				_, err := vectorOfFloat3.Decode(vectorFloat123Bytes, &dest, version)
+				assert.Contains(t, err.Error(), "expected vector of 3 elements, got: 2")
+			})
+			t.Run("bytes remaining", func(t *testing.T) {
+				var dest []float32
+				_, err := vectorOfFloat3.Decode(append(vectorFloat123Bytes, 1), &dest, version)
+				assert.Contains(t, err.Error(), "source was not fully read: bytes total: 13, read: 12, remaining: 1")
+			})
+			t.Run("truncated", func(t *testing.T) {
+				var dest []string
+				_, err := vectorOfVarchar2.Decode(vectorAbcDeBytes[:5], &dest, version)
+				assert.Contains(t, err.Error(), "cannot read element 1: expected at least 2 bytes but got: 0")
+			})
+		})
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// containerKind is the kind of a CQL value containing other values.
+type containerKind int
+
+const (
+	containerList = containerKind(iota)
+	containerSet
+	containerMap
+	containerTuple
+	containerUdt
+	containerVector
+)
+
+func (k containerKind) String() string {
+	switch k {
+	case containerList:
+		return "list"
+	case containerSet:
+		return "set"
+	case containerMap:
+		return "map"
+	case containerTuple:
+		return "tuple"
+	case containerUdt:
+		return "user-defined type"
+	case containerVector:
+		return "vector"
+	}
+	return "unknown"
+}
+
+// valueFormatter writes the values visited by a decodeWalker in a given text format, e.g. CQL literals or JSON.
+type valueFormatter interface {
+
+	// writeNull writes a NULL value.
+	writeNull()
+
+	// writeSimple writes a value of the given simple CQL type, as decoded by the registry codec for that type.
+	writeSimple(value interface{}, dt datatype.DataType) error
+
+	// open writes the opening delimiter of a container of the given kind.
+	open(kind containerKind)
+
+	// close writes the closing delimiter of a container of the given kind.
+	close(kind containerKind)
+
+	// writeSeparator writes the separator between two elements, entries or fields of a container.
+	writeSeparator()
+
+	// startMapKey is invoked before a map key is written.
+	startMapKey()
+
+	// endMapKey is invoked after a map key is written; it writes the separator between the key and the value.
+	endMapKey()
+
+	// writeFieldName writes the name of a user-defined type field, and the separator between the name and the value.
+	writeFieldName(name string)
+}
+
+// decodeWalker walks the tree of an encoded value: collections, maps, tuples, user-defined types and vectors are
+// iterated, while simple values are decoded with the codecs of the registry and written by the formatter.
+type decodeWalker struct {
+	registry  *Registry
+	version   primitive.ProtocolVersion
+	formatter valueFormatter
+	// verb describes the conversion in error messages, e.g. "format".
+	verb string
+}
+
+func (w *decodeWalker) walk(source []byte, dt datatype.DataType) error {
+	if source == nil {
+		w.formatter.writeNull()
+		return nil
+	} else if vectorType, ok := asVector(dt); ok {
+		return w.walkVector(source, vectorType)
+	}
+	switch dt.Code() {
+	case primitive.DataTypeCodeList:
+		return w.walkCollection(source, dt.(*datatype.List).ElementType, containerList)
+	case primitive.DataTypeCodeSet:
+		return w.walkCollection(source, dt.(*datatype.Set).ElementType, containerSet)
+	case primitive.DataTypeCodeMap:
+		return w.walkMap(source, dt.(*datatype.Map))
+	case primitive.DataTypeCodeTuple:
+		return w.walkTuple(source, dt.(*datatype.Tuple))
+	case primitive.DataTypeCodeUdt:
+		return w.walkUdt(source, dt.(*datatype.UserDefined))
+	}
+	return w.walkSimple(source, dt)
+}
+
+func (w *decodeWalker) walkSimple(source []byte, dt datatype.DataType) error {
+	codec, err := w.registry.NewCodec(dt)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if wasNull, err := codec.Decode(source, &value, w.version); err != nil {
+		return err
+	} else if wasNull {
+		w.formatter.writeNull()
+		return nil
+	}
+	return w.formatter.writeSimple(value, dt)
+}
+
+func (w *decodeWalker) walkCollection(source []byte, elementType datatype.DataType, kind containerKind) error {
+	return w.walkContainer(source, kind, -1, func(reader *bytes.Reader, i int) error {
+		if encodedElem, err := primitive.ReadBytes(reader); err != nil {
+			return errCannotReadElement(i, err)
+		} else if err = w.walk(encodedElem, elementType); err != nil {
+			return errCannotConvertElement(w.verb, i, err)
+		}
+		return nil
+	})
+}
+
+func (w *decodeWalker) walkMap(source []byte, mapType *datatype.Map) error {
+	return w.walkContainer(source, containerMap, -1, func(reader *bytes.Reader, i int) error {
+		w.formatter.startMapKey()
+		if encodedKey, err := primitive.ReadBytes(reader); err != nil {
+			return errCannotReadMapKey(i, err)
+		} else if err = w.walk(encodedKey, mapType.KeyType); err != nil {
+			return errCannotConvertMapKey(w.verb, i, err)
+		}
+		w.formatter.endMapKey()
+		if encodedValue, err := primitive.ReadBytes(reader); err != nil {
+			return errCannotReadMapValue(i, err)
+		} else if err = w.walk(encodedValue, mapType.ValueType); err != nil {
+			return errCannotConvertMapValue(w.verb, i, err)
+		}
+		return nil
+	})
+}
+
+func (w *decodeWalker) walkTuple(source []byte, tupleType *datatype.Tuple) error {
+	return w.walkContainer(source, containerTuple, len(tupleType.FieldTypes), func(reader *bytes.Reader, i int) error {
+		if encodedElem, err := primitive.ReadBytes(reader); err != nil {
+			return errCannotReadElement(i, err)
+		} else if err = w.walk(encodedElem, tupleType.FieldTypes[i]); err != nil {
+			return errCannotConvertElement(w.verb, i, err)
+		}
+		return nil
+	})
+}
+
+func (w *decodeWalker) walkUdt(source []byte, udtType *datatype.UserDefined) error {
+	return w.walkContainer(source, containerUdt, len(udtType.FieldTypes), func(reader *bytes.Reader, i int) error {
+		name := udtType.FieldNames[i]
+		w.formatter.writeFieldName(name)
+		if encodedField, err := primitive.ReadBytes(reader); err != nil {
+			return errCannotReadUdtField(i, name, err)
+		} else if err = w.walk(encodedField, udtType.FieldTypes[i]); err != nil {
+			return errCannotConvertUdtField(w.verb, i, name, err)
+		}
+		return nil
+	})
+}
+
+func (w *decodeWalker) walkVector(source []byte, vectorType *datatype.Vector) error {
+	elementLength := fixedValueLength(vectorType.ElementType)
+	return w.walkContainer(source, containerVector, vectorType.Dimensions, func(reader *bytes.Reader, i int) error {
+		if encodedElem, err := readVectorElement(reader, elementLength); err != nil {
+			return errCannotReadElement(i, err)
+		} else if err = w.walk(encodedElem, vectorType.ElementType); err != nil {
+			return errCannotConvertElement(w.verb, i, err)
+		}
+		return nil
+	})
+}
+
+// walkContainer writes the delimiters and separators of a container of the given kind, and invokes walkItem for each
+// of its items. If size is negative, the number of items is read from the source as a collection size. Empty
+// sources are written as NULL, and the entire source must be consumed.
+func (w *decodeWalker) walkContainer(
+	source []byte,
+	kind containerKind,
+	size int,
+	walkItem func(reader *bytes.Reader, i int) error,
+) (err error) {
+	if len(source) == 0 {
+		w.formatter.writeNull()
+		return nil
+	}
+	reader := bytes.NewReader(source)
+	total := len(source)
+	if size < 0 {
+		if size, err = readCollectionSize(reader, w.version); err != nil {
+			return err
+		}
+	}
+	w.formatter.open(kind)
+	for i := 0; i < size; i++ {
+		if i > 0 {
+			w.formatter.writeSeparator()
+		}
+		if err = walkItem(reader, i); err != nil {
+			return err
+		}
+	}
+	w.formatter.close(kind)
+	if remaining := reader.Len(); remaining != 0 {
+		return errBytesRemaining(total, remaining)
+	}
+	return nil
+}
+
+// valueParser reads the values of a given text format, e.g. CQL literals or JSON, on behalf of an encodeWalker.
+type valueParser interface {
+
+	// parseNull returns true, after consuming it, if the next value is NULL.
+	parseNull() (bool, error)
+
+	// parseSimple parses a value of the given simple CQL type, converts it to a Go value accepted by the built-in codec
+	// for that type, and passes it to encode.
+	parseSimple(dt datatype.DataType, encode func(value interface{}) error) error
+
+	// parseElements parses the elements of a list, set, tuple or vector, and invokes parseElement for each of them. If
+	// maxSize is not negative, an error is returned if there are more than maxSize elements.
+	parseElements(kind containerKind, maxSize int, parseElement func(i int) error) error
+
+	// parseEntries parses the entries of a map of the given type, and invokes parseKey then parseValue for each of them.
+	parseEntries(mapType *datatype.Map, parseKey, parseValue func(i int) error) error
+
+	// parseFields parses the fields of a user-defined type, and invokes parseField for each of them with the index of
+	// the field in the given type.
+	parseFields(udtType *datatype.UserDefined, parseField func(index int) error) error
+}
+
+// encodeWalker builds the tree of an encoded value from the values read by the parser: simple values are encoded with
+// the codecs of the registry, and collections, maps, tuples, user-defined types and vectors are assembled from their
+// encoded elements.
+type encodeWalker struct {
+	registry *Registry
+	version  primitive.ProtocolVersion
+	parser   valueParser
+}
+
+// walk parses a value of the given type and appends its encoded form to dest. If return parameter null is true, the
+// value was NULL and nothing was appended.
+func (w *encodeWalker) walk(dest []byte, dt datatype.DataType) (result []byte, null bool, err error) {
+	if null, err = w.parser.parseNull(); err != nil || null {
+		return dest, null, err
+	} else if vectorType, ok := asVector(dt); ok {
+		result, err = w.walkVector(dest, vectorType)
+		return result, false, err
+	}
+	switch dt.Code() {
+	case primitive.DataTypeCodeList:
+		result, err = w.walkCollection(dest, dt.(*datatype.List).ElementType, containerList)
+	case primitive.DataTypeCodeSet:
+		result, err = w.walkCollection(dest, dt.(*datatype.Set).ElementType, containerSet)
+	case primitive.DataTypeCodeMap:
+		result, err = w.walkMap(dest, dt.(*datatype.Map))
+	case primitive.DataTypeCodeTuple:
+		result, err = w.walkTuple(dest, dt.(*datatype.Tuple))
+	case primitive.DataTypeCodeUdt:
+		result, err = w.walkUdt(dest, dt.(*datatype.UserDefined))
+	default:
+		result, err = w.walkSimple(dest, dt)
+	}
+	return result, false, err
+}
+
+// walkElement parses a value of the given type and appends it to dest as a [bytes] value.
+func (w *encodeWalker) walkElement(dest []byte, dt datatype.DataType) ([]byte, error) {
+	start := len(dest)
+	// reserve space for the length, written once the value is encoded
+	dest = append(dest, 0, 0, 0, 0)
+	dest, null, err := w.walk(dest, dt)
+	if err != nil {
+		return dest[:start], err
+	}
+	length := len(dest) - start - primitive.LengthOfInt
+	if null {
+		length = -1
+	} else if length > math.MaxInt32 {
+		return dest[:start], errValueTooLong(length)
+	}
+	putInt32(dest[start:], int32(length))
+	return dest, nil
+}
+
+func (w *encodeWalker) walkSimple(dest []byte, dt datatype.DataType) ([]byte, error) {
+	codec, err := w.registry.NewCodec(dt)
+	if err != nil {
+		return dest, err
+	}
+	err = w.parser.parseSimple(dt, func(value interface{}) error {
+		encoded, err := codec.Encode(value, w.version)
+		if err == nil {
+			dest = append(dest, encoded...)
+		}
+		return err
+	})
+	return dest, err
+}
+
+func (w *encodeWalker) walkCollection(dest []byte, elementType datatype.DataType, kind containerKind) ([]byte, error) {
+	var elements []byte
+	size := 0
+	err := w.parser.parseElements(kind, -1, func(i int) (err error) {
+		if elements, err = w.walkElement(elements, elementType); err != nil {
+			return errCannotEncodeElement(i, err)
+		}
+		size++
+		return nil
+	})
+	if err != nil {
+		return dest, err
+	}
+	start := len(dest)
+	if dest, err = appendCollectionSize(dest, size, w.version); err != nil {
+		return dest[:start], err
+	}
+	return append(dest, elements...), nil
+}
+
+func (w *encodeWalker) walkMap(dest []byte, mapType *datatype.Map) ([]byte, error) {
+	var entries []byte
+	size := 0
+	err := w.parser.parseEntries(
+		mapType,
+		func(i int) (err error) {
+			if entries, err = w.walkElement(entries, mapType.KeyType); err != nil {
+				return errCannotEncodeMapKey(i, err)
+			}
+			return nil
+		},
+		func(i int) (err error) {
+			if entries, err = w.walkElement(entries, mapType.ValueType); err != nil {
+				return errCannotEncodeMapValue(i, err)
+			}
+			size++
+			return nil
+		},
+	)
+	if err != nil {
+		return dest, err
+	}
+	start := len(dest)
+	if dest, err = appendCollectionSize(dest, size, w.version); err != nil {
+		return dest[:start], err
+	}
+	return append(dest, entries...), nil
+}
+
+func (w *encodeWalker) walkTuple(dest []byte, tupleType *datatype.Tuple) ([]byte, error) {
+	start := len(dest)
+	size := 0
+	err := w.parser.parseElements(containerTuple, len(tupleType.FieldTypes), func(i int) (err error) {
+		if dest, err = w.walkElement(dest, tupleType.FieldTypes[i]); err != nil {
+			return errCannotEncodeElement(i, err)
+		}
+		size++
+		return nil
+	})
+	if err != nil {
+		return dest[:start], err
+	}
+	// missing elements are NULL
+	for ; size < len(tupleType.FieldTypes); size++ {
+		dest = appendInt32(dest, -1)
+	}
+	return dest, nil
+}
+
+func (w *encodeWalker) walkUdt(dest []byte, udtType *datatype.UserDefined) ([]byte, error) {
+	fields := make([][]byte, len(udtType.FieldNames))
+	err := w.parser.parseFields(udtType, func(index int) (err error) {
+		if fields[index], err = w.walkElement([]byte{}, udtType.FieldTypes[index]); err != nil {
+			return errCannotEncodeUdtField(index, udtType.FieldNames[index], err)
+		}
+		return nil
+	})
+	if err != nil {
+		return dest, err
+	}
+	// missing fields are NULL
+	for _, field := range fields {
+		if field == nil {
+			dest = appendInt32(dest, -1)
+		} else {
+			dest = append(dest, field...)
+		}
+	}
+	return dest, nil
+}
+
+func (w *encodeWalker) walkVector(dest []byte, vectorType *datatype.Vector) ([]byte, error) {
+	elementLength := fixedValueLength(vectorType.ElementType)
+	buf := bytes.NewBuffer(dest)
+	size := 0
+	err := w.parser.parseElements(containerVector, vectorType.Dimensions, func(i int) error {
+		element, null, err := w.walk(nil, vectorType.ElementType)
+		if err != nil {
+			return errCannotEncodeElement(i, err)
+		} else if null {
+			return errCannotEncodeElement(i, ErrVectorNullElement)
+		} else if elementLength >= 0 && len(element) != elementLength {
+			return errCannotEncodeElement(i, errWrongFixedLength(elementLength, len(element)))
+		} else if elementLength < 0 {
+			_, _ = primitive.WriteUnsignedVint(uint64(len(element)), buf)
+		}
+		buf.Write(element)
+		size++
+		return nil
+	})
+	if err == nil && size != vectorType.Dimensions {
+		err = errWrongVectorSize(vectorType.Dimensions, size)
+	}
+	if err != nil {
+		return dest, err
+	}
+	return buf.Bytes(), nil
+}
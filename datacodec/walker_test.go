@@ -0,0 +1,71 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacodec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+func TestWalkers_NestedMapKeys(t *testing.T) {
+	// map<frozen<map<int,int>>,int> {{1: 2}: 3}
+	dt := datatype.NewMap(datatype.NewMap(datatype.Int, datatype.Int), datatype.Int)
+	encoded := message.Column{
+		0, 0, 0, 1,
+		0, 0, 0, 20,
+		0, 0, 0, 1, 0, 0, 0, 4, 0, 0, 0, 1, 0, 0, 0, 4, 0, 0, 0, 2,
+		0, 0, 0, 4, 0, 0, 0, 3,
+	}
+	tests := []struct {
+		name   string
+		format func(message.Column, datatype.DataType, primitive.ProtocolVersion) (string, error)
+		parse  func(string, datatype.DataType, primitive.ProtocolVersion) (message.Column, error)
+		text   string
+	}{
+		{
+			"literal",
+			FormatLiteral,
+			ParseLiteral,
+			"{{1: 2}: 3}",
+		},
+		{
+			"JSON",
+			func(column message.Column, dt datatype.DataType, version primitive.ProtocolVersion) (string, error) {
+				data, err := MarshalJSON(column, dt, version)
+				return string(data), err
+			},
+			func(text string, dt datatype.DataType, version primitive.ProtocolVersion) (message.Column, error) {
+				return UnmarshalJSON([]byte(text), dt, version)
+			},
+			`{"{\"1\": 2}": 3}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.format(encoded, dt, primitive.ProtocolVersion5)
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, text)
+			parsed, err := tt.parse(text, dt, primitive.ProtocolVersion5)
+			require.NoError(t, err)
+			assert.Equal(t, encoded, parsed)
+		})
+	}
+}
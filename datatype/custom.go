@@ -17,10 +17,21 @@ package datatype
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/datastax/go-cassandra-native-protocol/primitive"
 )
 
+// DSE geospatial types; these are transmitted as custom types.
+var (
+	Point      = NewCustom("org.apache.cassandra.db.marshal.PointType")
+	LineString = NewCustom("org.apache.cassandra.db.marshal.LineStringType")
+	Polygon    = NewCustom("org.apache.cassandra.db.marshal.PolygonType")
+)
+
+// DateRange is the DSE date range type; it is transmitted as a custom type.
+var DateRange = NewCustom("org.apache.cassandra.db.marshal.DateRangeType")
+
 // Custom is a data type that represents a CQL custom type.
 // +k8s:deepcopy-gen=true
 // +k8s:deepcopy-gen:interfaces=github.com/datastax/go-cassandra-native-protocol/datatype.DataType
@@ -44,24 +55,39 @@ func (t *Custom) AsCql() string {
 	return fmt.Sprintf("'%v'", t.ClassName)
 }
 
+// AsVector returns this custom type as a vector type, if its class name denotes a valid vector type. Note that vector
+// types are decoded from the wire as custom types, so this is how a decoded vector type can be recognized.
+func (t *Custom) AsVector() (*Vector, bool) {
+	if !strings.HasPrefix(strings.TrimPrefix(t.ClassName, MarshalPackage), marshalVectorType+"(") {
+		return nil, false
+	} else if parsed, err := ParseMarshalType(t.ClassName); err != nil {
+		return nil, false
+	} else {
+		vectorType, ok := parsed.(*Vector)
+		return vectorType, ok
+	}
+}
+
 func writeCustomType(t DataType, dest io.Writer, _ primitive.ProtocolVersion) (err error) {
-	if customType, ok := t.(*Custom); !ok {
-		return fmt.Errorf("expected *Custom, got %T", t)
-	} else if err = primitive.WriteString(customType.ClassName, dest); err != nil {
+	if className, err := customTypeClassName(t); err != nil {
+		return err
+	} else if err = primitive.WriteString(className, dest); err != nil {
 		return fmt.Errorf("cannot write custom type class name: %w", err)
 	}
 	return nil
 }
 
 func lengthOfCustomType(t DataType, _ primitive.ProtocolVersion) (length int, err error) {
-	if customType, ok := t.(*Custom); !ok {
-		return -1, fmt.Errorf("expected *Custom, got %T", t)
+	if className, err := customTypeClassName(t); err != nil {
+		return -1, err
 	} else {
-		length += primitive.LengthOfString(customType.ClassName)
+		length += primitive.LengthOfString(className)
 	}
 	return length, nil
 }
 
+// readCustomType reads a custom type. Vectors are transmitted as custom types too, but are returned as *Custom to
+// preserve the decoded type; use Custom.AsVector to convert them.
 func readCustomType(source io.Reader, _ primitive.ProtocolVersion) (t DataType, err error) {
 	customType := &Custom{}
 	if customType.ClassName, err = primitive.ReadString(source); err != nil {
@@ -69,3 +95,16 @@ func readCustomType(source io.Reader, _ primitive.ProtocolVersion) (t DataType,
 	}
 	return customType, nil
 }
+
+// customTypeClassName returns the class name to use on the wire for the given type, which must be either a Custom
+// or a Vector type.
+func customTypeClassName(t DataType) (string, error) {
+	switch customType := t.(type) {
+	case *Custom:
+		return customType.ClassName, nil
+	case *Vector:
+		return customType.ClassName()
+	default:
+		return "", fmt.Errorf("expected *Custom, got %T", t)
+	}
+}
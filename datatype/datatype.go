@@ -27,6 +27,60 @@ type DataType interface {
 	DeepCopyDataType() DataType
 }
 
+// IsFrozen returns true if the given data type is a list, set, map, tuple or user-defined type marked as frozen.
+// Frozenness is not transmitted by the protocol, so data types read from the wire are never frozen; it is however
+// produced by the CQL type and marshal class name parsers, reflected by AsCql, and honored by type comparisons.
+func IsFrozen(t DataType) bool {
+	switch t := t.(type) {
+	case *List:
+		return t.Frozen
+	case *Set:
+		return t.Frozen
+	case *Map:
+		return t.Frozen
+	case *Tuple:
+		return t.Frozen
+	case *UserDefined:
+		return t.Frozen
+	}
+	return false
+}
+
+// Freeze returns a frozen copy of the given data type, which must be a list, set, map, tuple or user-defined type.
+// The original data type is not modified; only the top-level type is copied.
+func Freeze(t DataType) (DataType, error) {
+	switch t := t.(type) {
+	case *List:
+		frozen := *t
+		frozen.Frozen = true
+		return &frozen, nil
+	case *Set:
+		frozen := *t
+		frozen.Frozen = true
+		return &frozen, nil
+	case *Map:
+		frozen := *t
+		frozen.Frozen = true
+		return &frozen, nil
+	case *Tuple:
+		frozen := *t
+		frozen.Frozen = true
+		return &frozen, nil
+	case *UserDefined:
+		frozen := *t
+		frozen.Frozen = true
+		return &frozen, nil
+	}
+	return nil, fmt.Errorf("data type %v cannot be frozen", t)
+}
+
+func frozenCql(cql string, frozen bool) string {
+	if frozen {
+		return "frozen<" + cql + ">"
+	}
+	return cql
+}
+
 func WriteDataType(t DataType, dest io.Writer, version primitive.ProtocolVersion) (err error) {
 	if t == nil {
 		return fmt.Errorf("DataType can not be nil")
@@ -0,0 +1,78 @@
+// Copyright 2020 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatype
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+func TestFreeze(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    DataType
+		expected DataType
+		cql      string
+	}{
+		{"list", NewList(Int), &List{ElementType: Int, Frozen: true}, "frozen<list<int>>"},
+		{"set", NewSet(Int), &Set{ElementType: Int, Frozen: true}, "frozen<set<int>>"},
+		{"map", NewMap(Int, Varchar), &Map{KeyType: Int, ValueType: Varchar, Frozen: true}, "frozen<map<int,varchar>>"},
+		{"tuple", NewTuple(Int), &Tuple{FieldTypes: []DataType{Int}, Frozen: true}, "frozen<tuple<int>>"},
+		{
+			"udt",
+			udt1,
+			&UserDefined{Keyspace: "ks1", Name: "udt1", FieldNames: udt1.FieldNames, FieldTypes: udt1.FieldTypes, Frozen: true},
+			"frozen<ks1.udt1<f1:varchar,f2:int>>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.False(t, IsFrozen(tt.input))
+			actual, err := Freeze(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+			assert.True(t, IsFrozen(actual))
+			assert.False(t, IsFrozen(tt.input))
+			assert.Equal(t, tt.cql, actual.AsCql())
+		})
+	}
+	t.Run("primitive", func(t *testing.T) {
+		actual, err := Freeze(Int)
+		assert.Nil(t, actual)
+		assert.EqualError(t, err, "data type int cannot be frozen")
+		assert.False(t, IsFrozen(Int))
+	})
+}
+
+func TestFrozenNotWritten(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			frozen := &List{ElementType: Int, Frozen: true}
+			dest := &bytes.Buffer{}
+			require.NoError(t, WriteDataType(frozen, dest, version))
+			expected := &bytes.Buffer{}
+			require.NoError(t, WriteDataType(NewList(Int), expected, version))
+			assert.Equal(t, expected.Bytes(), dest.Bytes())
+			actual, err := ReadDataType(dest, version)
+			require.NoError(t, err)
+			assert.False(t, IsFrozen(actual))
+		})
+	}
+}
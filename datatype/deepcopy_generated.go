@@ -221,3 +221,30 @@ func (in *UserDefined) DeepCopyDataType() DataType {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Vector) DeepCopyInto(out *Vector) {
+	*out = *in
+	if in.ElementType != nil {
+		out.ElementType = in.ElementType.DeepCopyDataType()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Vector.
+func (in *Vector) DeepCopy() *Vector {
+	if in == nil {
+		return nil
+	}
+	out := new(Vector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyDataType is an autogenerated deepcopy function, copying the receiver, creating a new DataType.
+func (in *Vector) DeepCopyDataType() DataType {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
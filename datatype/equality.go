@@ -0,0 +1,228 @@
+// Copyright 2020 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatype
+
+import (
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// Equal returns true if the given data types are structurally equal. Unlike reflect.DeepEqual, this function treats
+// the legacy text type code (DataTypeCodeText) as an alias for varchar, compares vectors with custom types carrying
+// the equivalent class name, and does not depend on pointer identity. Frozenness is taken into account. Two nil data
+// types are considered equal.
+func Equal(a DataType, b DataType) bool {
+	return equal(a, b, false)
+}
+
+// EqualIgnoringFrozen is like Equal, but ignores frozenness, at any nesting level; e.g. frozen<list<int>> and
+// list<int> are considered equal. This is useful when comparing a type declared in a schema with a type decoded from
+// the wire, since frozenness is never transmitted.
+func EqualIgnoringFrozen(a DataType, b DataType) bool {
+	return equal(a, b, true)
+}
+
+func equal(a DataType, b DataType, ignoreFrozen bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	} else if normalizeCode(a.Code()) != normalizeCode(b.Code()) {
+		return false
+	}
+	switch ta := a.(type) {
+	case *PrimitiveType:
+		return true
+	case *Custom:
+		if tb, ok := b.(*Vector); ok {
+			return vectorEqualsCustom(tb, ta, ignoreFrozen)
+		} else if tb, ok := b.(*Custom); ok {
+			return ta.ClassName == tb.ClassName
+		}
+	case *Vector:
+		if tb, ok := b.(*Custom); ok {
+			return vectorEqualsCustom(ta, tb, ignoreFrozen)
+		} else if tb, ok := b.(*Vector); ok {
+			return ta.Dimensions == tb.Dimensions && equal(ta.ElementType, tb.ElementType, ignoreFrozen)
+		}
+	case *List:
+		if tb, ok := b.(*List); ok {
+			return (ignoreFrozen || ta.Frozen == tb.Frozen) && equal(ta.ElementType, tb.ElementType, ignoreFrozen)
+		}
+	case *Set:
+		if tb, ok := b.(*Set); ok {
+			return (ignoreFrozen || ta.Frozen == tb.Frozen) && equal(ta.ElementType, tb.ElementType, ignoreFrozen)
+		}
+	case *Map:
+		if tb, ok := b.(*Map); ok {
+			return (ignoreFrozen || ta.Frozen == tb.Frozen) &&
+				equal(ta.KeyType, tb.KeyType, ignoreFrozen) &&
+				equal(ta.ValueType, tb.ValueType, ignoreFrozen)
+		}
+	case *Tuple:
+		if tb, ok := b.(*Tuple); ok {
+			return (ignoreFrozen || ta.Frozen == tb.Frozen) && equalTypes(ta.FieldTypes, tb.FieldTypes, ignoreFrozen)
+		}
+	case *UserDefined:
+		if tb, ok := b.(*UserDefined); ok {
+			return (ignoreFrozen || ta.Frozen == tb.Frozen) &&
+				ta.Keyspace == tb.Keyspace &&
+				ta.Name == tb.Name &&
+				equalNames(ta.FieldNames, tb.FieldNames) &&
+				equalTypes(ta.FieldTypes, tb.FieldTypes, ignoreFrozen)
+		}
+	default:
+		// unknown DataType implementation: codes are equal, which is enough if the other type is a primitive one,
+		// otherwise compare CQL representations
+		if _, ok := b.(*PrimitiveType); ok {
+			return true
+		}
+		return a.AsCql() == b.AsCql()
+	}
+	return false
+}
+
+// IsCompatible returns true if values serialized with the source data type can be safely deserialized with the
+// target data type. This is the case when both types are equal, ignoring frozenness (which does not affect the
+// serialized form), but also in the following situations:
+//
+//  - The source type is ascii and the target type is varchar, since any ascii string is a valid UTF-8 string;
+//  - The source type is timeuuid and the target type is uuid;
+//  - The source and target types are bigint, counter or timestamp;
+//  - The source type is an integer type (tinyint, smallint, int or bigint) and the target type is varint;
+//  - The target type is blob;
+//  - The source type is a user-defined type with the same name and the target type has the same fields, plus
+//    zero or more fields added at the end;
+//  - The source type is a tuple and its fields are a prefix of the fields of the target type;
+//  - The source and target types are collections or vectors with compatible elements.
+//
+// Values of the target type may not be deserializable with the source type: this function is not symmetrical.
+func IsCompatible(source DataType, target DataType) bool {
+	if source == nil || target == nil {
+		return false
+	}
+	sourceCode := normalizeCode(source.Code())
+	targetCode := normalizeCode(target.Code())
+	if targetCode == primitive.DataTypeCodeBlob {
+		return true
+	}
+	switch ts := source.(type) {
+	case *PrimitiveType:
+		if sourceCode == targetCode {
+			return true
+		}
+		return isPrimitiveCompatible(sourceCode, targetCode)
+	case *List:
+		if tt, ok := target.(*List); ok {
+			return IsCompatible(ts.ElementType, tt.ElementType)
+		}
+	case *Set:
+		if tt, ok := target.(*Set); ok {
+			return IsCompatible(ts.ElementType, tt.ElementType)
+		}
+	case *Map:
+		if tt, ok := target.(*Map); ok {
+			return IsCompatible(ts.KeyType, tt.KeyType) && IsCompatible(ts.ValueType, tt.ValueType)
+		}
+	case *Tuple:
+		if tt, ok := target.(*Tuple); ok {
+			return isPrefixCompatible(ts.FieldTypes, tt.FieldTypes)
+		}
+	case *UserDefined:
+		if tt, ok := target.(*UserDefined); ok {
+			return ts.Keyspace == tt.Keyspace &&
+				ts.Name == tt.Name &&
+				len(ts.FieldNames) <= len(tt.FieldNames) &&
+				equalNames(ts.FieldNames, tt.FieldNames[:len(ts.FieldNames)]) &&
+				isPrefixCompatible(ts.FieldTypes, tt.FieldTypes)
+		}
+	case *Vector:
+		if tt, ok := target.(*Vector); ok {
+			return ts.Dimensions == tt.Dimensions && IsCompatible(ts.ElementType, tt.ElementType)
+		}
+		return Equal(source, target)
+	default:
+		return Equal(source, target)
+	}
+	return false
+}
+
+func isPrimitiveCompatible(source primitive.DataTypeCode, target primitive.DataTypeCode) bool {
+	switch target {
+	case primitive.DataTypeCodeVarchar:
+		return source == primitive.DataTypeCodeAscii
+	case primitive.DataTypeCodeUuid:
+		return source == primitive.DataTypeCodeTimeuuid
+	case primitive.DataTypeCodeBigint, primitive.DataTypeCodeCounter, primitive.DataTypeCodeTimestamp:
+		return source == primitive.DataTypeCodeBigint ||
+			source == primitive.DataTypeCodeCounter ||
+			source == primitive.DataTypeCodeTimestamp
+	case primitive.DataTypeCodeVarint:
+		return source == primitive.DataTypeCodeTinyint ||
+			source == primitive.DataTypeCodeSmallint ||
+			source == primitive.DataTypeCodeInt ||
+			source == primitive.DataTypeCodeBigint
+	}
+	return false
+}
+
+// isPrefixCompatible returns true if the source types are compatible with the first len(source) target types.
+func isPrefixCompatible(source []DataType, target []DataType) bool {
+	if len(source) > len(target) {
+		return false
+	}
+	for i, sourceType := range source {
+		if !IsCompatible(sourceType, target[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func vectorEqualsCustom(v *Vector, c *Custom, ignoreFrozen bool) bool {
+	if parsedVector, ok := c.AsVector(); ok {
+		return equal(v, parsedVector, ignoreFrozen)
+	}
+	return false
+}
+
+func equalTypes(a []DataType, b []DataType, ignoreFrozen bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equal(a[i], b[i], ignoreFrozen) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalNames(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeCode maps the legacy text type code to the varchar type code.
+func normalizeCode(code primitive.DataTypeCode) primitive.DataTypeCode {
+	if code == primitive.DataTypeCodeText {
+		return primitive.DataTypeCodeVarchar
+	}
+	return code
+}
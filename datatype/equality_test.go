@@ -0,0 +1,143 @@
+// Copyright 2020 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// legacyText is a DataType implementation using the legacy text type code.
+type legacyText struct{}
+
+func (t *legacyText) Code() primitive.DataTypeCode { return primitive.DataTypeCodeText }
+func (t *legacyText) AsCql() string                { return "text" }
+func (t *legacyText) DeepCopyDataType() DataType   { return t }
+
+func TestEqual(t *testing.T) {
+	udt3, _ := NewUserDefined("ks1", "udt1", []string{"f1", "f2"}, []DataType{Varchar, Int})
+	tests := []struct {
+		name     string
+		a        DataType
+		b        DataType
+		expected bool
+	}{
+		{"nil nil", nil, nil, true},
+		{"nil int", nil, Int, false},
+		{"int nil", Int, nil, false},
+		{"int int", Int, Int, true},
+		{"int bigint", Int, Bigint, false},
+		{"varchar text alias", Varchar, &legacyText{}, true},
+		{"text alias varchar", &legacyText{}, Varchar, true},
+		{"varchar ascii", Varchar, Ascii, false},
+		{"custom custom", NewCustom("a.B"), NewCustom("a.B"), true},
+		{"custom different", NewCustom("a.B"), NewCustom("a.C"), false},
+		{"list list", NewList(Int), NewList(Int), true},
+		{"list different elements", NewList(Int), NewList(Varchar), false},
+		{"list set", NewList(Int), NewSet(Int), false},
+		{"list frozen", NewList(Int), &List{ElementType: Int, Frozen: true}, false},
+		{"set set", NewSet(NewList(Int)), NewSet(NewList(Int)), true},
+		{"map map", NewMap(Int, Varchar), NewMap(Int, Varchar), true},
+		{"map different values", NewMap(Int, Varchar), NewMap(Int, Int), false},
+		{"tuple tuple", NewTuple(Int, Varchar), NewTuple(Int, Varchar), true},
+		{"tuple prefix", NewTuple(Int), NewTuple(Int, Varchar), false},
+		{"udt udt", udt1, udt3, true},
+		{"udt different name", udt1, &UserDefined{Keyspace: "ks1", Name: "udt2", FieldNames: udt1.FieldNames, FieldTypes: udt1.FieldTypes}, false},
+		{"udt different field names", udt1, &UserDefined{Keyspace: "ks1", Name: "udt1", FieldNames: []string{"f1", "f3"}, FieldTypes: udt1.FieldTypes}, false},
+		{"udt frozen", udt1, &UserDefined{Keyspace: "ks1", Name: "udt1", FieldNames: udt1.FieldNames, FieldTypes: udt1.FieldTypes, Frozen: true}, false},
+		{"vector vector", NewVector(Float, 3), NewVector(Float, 3), true},
+		{"vector different dimensions", NewVector(Float, 3), NewVector(Float, 4), false},
+		{"vector custom", NewVector(Float, 3), NewCustom("org.apache.cassandra.db.marshal.VectorType(org.apache.cassandra.db.marshal.FloatType,3)"), true},
+		{"custom vector", NewCustom("org.apache.cassandra.db.marshal.VectorType(org.apache.cassandra.db.marshal.FloatType,3)"), NewVector(Float, 3), true},
+		{"vector other custom", NewVector(Float, 3), NewCustom("a.B"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Equal(tt.a, tt.b))
+		})
+	}
+}
+
+func TestEqualIgnoringFrozen(t *testing.T) {
+	frozenList := &List{ElementType: Int, Frozen: true}
+	tests := []struct {
+		name     string
+		a        DataType
+		b        DataType
+		expected bool
+	}{
+		{"nil nil", nil, nil, true},
+		{"int int", Int, Int, true},
+		{"list frozen", NewList(Int), frozenList, true},
+		{"list different elements", frozenList, NewList(Varchar), false},
+		{"set nested frozen", NewSet(frozenList), &Set{ElementType: NewList(Int), Frozen: true}, true},
+		{"map nested frozen", NewMap(Int, frozenList), NewMap(Int, NewList(Int)), true},
+		{"tuple nested frozen", NewTuple(frozenList), &Tuple{FieldTypes: []DataType{NewList(Int)}, Frozen: true}, true},
+		{"udt frozen", udt1, &UserDefined{Keyspace: "ks1", Name: "udt1", FieldNames: udt1.FieldNames, FieldTypes: udt1.FieldTypes, Frozen: true}, true},
+		{"vector nested frozen", NewVector(frozenList, 2), NewVector(NewList(Int), 2), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, EqualIgnoringFrozen(tt.a, tt.b))
+		})
+	}
+}
+
+func TestIsCompatible(t *testing.T) {
+	udtV1, _ := NewUserDefined("ks1", "address", []string{"street"}, []DataType{Varchar})
+	udtV2, _ := NewUserDefined("ks1", "address", []string{"street", "zip"}, []DataType{Varchar, Int})
+	udtOther, _ := NewUserDefined("ks1", "address", []string{"city", "zip"}, []DataType{Varchar, Int})
+	tests := []struct {
+		name     string
+		source   DataType
+		target   DataType
+		expected bool
+	}{
+		{"nil", nil, Int, false},
+		{"int int", Int, Int, true},
+		{"ascii varchar", Ascii, Varchar, true},
+		{"varchar ascii", Varchar, Ascii, false},
+		{"ascii text alias", Ascii, &legacyText{}, true},
+		{"text alias varchar", &legacyText{}, Varchar, true},
+		{"timeuuid uuid", Timeuuid, Uuid, true},
+		{"uuid timeuuid", Uuid, Timeuuid, false},
+		{"bigint timestamp", Bigint, Timestamp, true},
+		{"counter bigint", Counter, Bigint, true},
+		{"int varint", Int, Varint, true},
+		{"varint int", Varint, Int, false},
+		{"int bigint", Int, Bigint, false},
+		{"anything blob", NewMap(Int, Varchar), Blob, true},
+		{"list frozen", NewList(Ascii), &List{ElementType: Varchar, Frozen: true}, true},
+		{"set list", NewSet(Int), NewList(Int), false},
+		{"map", NewMap(Timeuuid, Ascii), NewMap(Uuid, Varchar), true},
+		{"map incompatible", NewMap(Uuid, Ascii), NewMap(Timeuuid, Varchar), false},
+		{"tuple prefix", NewTuple(Int), NewTuple(Int, Varchar), true},
+		{"tuple longer", NewTuple(Int, Varchar), NewTuple(Int), false},
+		{"udt added fields", udtV1, udtV2, true},
+		{"udt removed fields", udtV2, udtV1, false},
+		{"udt renamed fields", udtV1, udtOther, false},
+		{"vector", NewVector(Int, 2), NewVector(Varint, 2), true},
+		{"vector dimensions", NewVector(Int, 2), NewVector(Int, 3), false},
+		{"custom", NewCustom("a.B"), NewCustom("a.B"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsCompatible(tt.source, tt.target))
+		})
+	}
+}
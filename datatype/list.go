@@ -26,6 +26,8 @@ import (
 // +k8s:deepcopy-gen:interfaces=github.com/datastax/go-cassandra-native-protocol/datatype.DataType
 type List struct {
 	ElementType DataType
+	// Frozen indicates whether this is a frozen list; see IsFrozen.
+	Frozen bool
 }
 
 func NewList(elementType DataType) *List {
@@ -41,7 +43,7 @@ func (t *List) String() string {
 }
 
 func (t *List) AsCql() string {
-	return fmt.Sprintf("list<%v>", t.ElementType.AsCql())
+	return frozenCql(fmt.Sprintf("list<%v>", t.ElementType.AsCql()), t.Frozen)
 }
 
 func writeListType(t DataType, dest io.Writer, version primitive.ProtocolVersion) (err error) {
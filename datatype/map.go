@@ -27,6 +27,8 @@ import (
 type Map struct {
 	KeyType   DataType
 	ValueType DataType
+	// Frozen indicates whether this is a frozen map; see IsFrozen.
+	Frozen bool
 }
 
 func (t *Map) Code() primitive.DataTypeCode {
@@ -38,7 +40,7 @@ func (t *Map) String() string {
 }
 
 func (t *Map) AsCql() string {
-	return fmt.Sprintf("map<%v,%v>", t.KeyType.AsCql(), t.ValueType.AsCql())
+	return frozenCql(fmt.Sprintf("map<%v,%v>", t.KeyType.AsCql(), t.ValueType.AsCql()), t.Frozen)
 }
 
 func NewMap(keyType DataType, valueType DataType) *Map {
@@ -0,0 +1,359 @@
+// Copyright 2020 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatype
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// MarshalPackage is the Java package where Cassandra's marshal classes are declared.
+const MarshalPackage = "org.apache.cassandra.db.marshal."
+
+const (
+	marshalFrozenType   = "FrozenType"
+	marshalReversedType = "ReversedType"
+	marshalListType     = "ListType"
+	marshalSetType      = "SetType"
+	marshalMapType      = "MapType"
+	marshalTupleType    = "TupleType"
+	marshalUserType     = "UserType"
+	marshalVectorType   = "VectorType"
+)
+
+var primitiveTypesByMarshalClass = map[string]DataType{
+	"AsciiType":         Ascii,
+	"LongType":          Bigint,
+	"BytesType":         Blob,
+	"BooleanType":       Boolean,
+	"CounterColumnType": Counter,
+	"SimpleDateType":    Date,
+	"DecimalType":       Decimal,
+	"DoubleType":        Double,
+	"DurationType":      Duration,
+	"FloatType":         Float,
+	"InetAddressType":   Inet,
+	"Int32Type":         Int,
+	"ShortType":         Smallint,
+	"TimeType":          Time,
+	"TimestampType":     Timestamp,
+	"DateType":          Timestamp, // legacy name for TimestampType
+	"TimeUUIDType":      Timeuuid,
+	"ByteType":          Tinyint,
+	"UUIDType":          Uuid,
+	"UTF8Type":          Varchar,
+	"IntegerType":       Varint,
+}
+
+var marshalClassesByPrimitiveType = map[primitive.DataTypeCode]string{
+	primitive.DataTypeCodeAscii:     "AsciiType",
+	primitive.DataTypeCodeBigint:    "LongType",
+	primitive.DataTypeCodeBlob:      "BytesType",
+	primitive.DataTypeCodeBoolean:   "BooleanType",
+	primitive.DataTypeCodeCounter:   "CounterColumnType",
+	primitive.DataTypeCodeDate:      "SimpleDateType",
+	primitive.DataTypeCodeDecimal:   "DecimalType",
+	primitive.DataTypeCodeDouble:    "DoubleType",
+	primitive.DataTypeCodeDuration:  "DurationType",
+	primitive.DataTypeCodeFloat:     "FloatType",
+	primitive.DataTypeCodeInet:      "InetAddressType",
+	primitive.DataTypeCodeInt:       "Int32Type",
+	primitive.DataTypeCodeSmallint:  "ShortType",
+	primitive.DataTypeCodeTime:      "TimeType",
+	primitive.DataTypeCodeTimestamp: "TimestampType",
+	primitive.DataTypeCodeTimeuuid:  "TimeUUIDType",
+	primitive.DataTypeCodeTinyint:   "ByteType",
+	primitive.DataTypeCodeUuid:      "UUIDType",
+	primitive.DataTypeCodeVarchar:   "UTF8Type",
+	primitive.DataTypeCodeText:      "UTF8Type",
+	primitive.DataTypeCodeVarint:    "IntegerType",
+}
+
+// ParseMarshalType parses the given Cassandra marshal class name, e.g.
+// "org.apache.cassandra.db.marshal.MapType(org.apache.cassandra.db.marshal.UTF8Type,org.apache.cassandra.db.marshal.Int32Type)",
+// into a DataType. Class names may be fully-qualified or simple names relative to MarshalPackage. ReversedType
+// wrappers are discarded since clustering order is not part of the data type. Classes that do not denote a known CQL
+// type are returned as Custom types. This function can be used to resolve the class name of a Custom type into its
+// actual data type.
+func ParseMarshalType(className string) (DataType, error) {
+	if dt, err := parseMarshalType(strings.TrimSpace(className)); err != nil {
+		return nil, fmt.Errorf("cannot parse marshal type %q: %w", className, err)
+	} else {
+		return dt, nil
+	}
+}
+
+// FormatMarshalType returns the fully-qualified Cassandra marshal class name for the given DataType. This is the
+// inverse operation of ParseMarshalType.
+func FormatMarshalType(dt DataType) (string, error) {
+	sb := &strings.Builder{}
+	if err := formatMarshalType(dt, sb); err != nil {
+		return "", fmt.Errorf("cannot format marshal type for %v: %w", dt, err)
+	}
+	return sb.String(), nil
+}
+
+func parseMarshalType(className string) (DataType, error) {
+	name, params, err := splitMarshalClassName(className)
+	if err != nil {
+		return nil, err
+	}
+	simpleName := strings.TrimPrefix(name, MarshalPackage)
+	if params == nil {
+		if dt, found := primitiveTypesByMarshalClass[simpleName]; found {
+			return dt, nil
+		}
+		return NewCustom(className), nil
+	}
+	switch simpleName {
+	case marshalFrozenType:
+		if types, err := parseMarshalTypeParameters(simpleName, params, 1, 1); err != nil {
+			return nil, err
+		} else {
+			return Freeze(types[0])
+		}
+	case marshalReversedType:
+		if types, err := parseMarshalTypeParameters(simpleName, params, 1, 1); err != nil {
+			return nil, err
+		} else {
+			return types[0], nil
+		}
+	case marshalListType:
+		if types, err := parseMarshalTypeParameters(simpleName, params, 1, 1); err != nil {
+			return nil, err
+		} else {
+			return NewList(types[0]), nil
+		}
+	case marshalSetType:
+		if types, err := parseMarshalTypeParameters(simpleName, params, 1, 1); err != nil {
+			return nil, err
+		} else {
+			return NewSet(types[0]), nil
+		}
+	case marshalMapType:
+		if types, err := parseMarshalTypeParameters(simpleName, params, 2, 2); err != nil {
+			return nil, err
+		} else {
+			return NewMap(types[0], types[1]), nil
+		}
+	case marshalTupleType:
+		if types, err := parseMarshalTypeParameters(simpleName, params, 1, -1); err != nil {
+			return nil, err
+		} else {
+			return NewTuple(types...), nil
+		}
+	case marshalUserType:
+		return parseMarshalUserType(params)
+	case marshalVectorType:
+		return parseMarshalVectorType(params)
+	}
+	return NewCustom(className), nil
+}
+
+func parseMarshalTypeParameters(name string, params []string, min int, max int) ([]DataType, error) {
+	if len(params) < min || (max >= 0 && len(params) > max) {
+		if min == max {
+			return nil, fmt.Errorf("%v expects %d parameter(s), got %d", name, min, len(params))
+		}
+		return nil, fmt.Errorf("%v expects at least %d parameter(s), got %d", name, min, len(params))
+	}
+	types := make([]DataType, len(params))
+	for i, param := range params {
+		var err error
+		if types[i], err = parseMarshalType(param); err != nil {
+			return nil, err
+		}
+	}
+	return types, nil
+}
+
+// parseMarshalUserType parses UserType parameters: keyspace, hex-encoded type name, and a list of fields in the
+// form hex-encoded-name:type.
+func parseMarshalUserType(params []string) (DataType, error) {
+	if len(params) < 2 {
+		return nil, fmt.Errorf("%v expects at least 2 parameter(s), got %d", marshalUserType, len(params))
+	}
+	name, err := hex.DecodeString(params[1])
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %v name %q: %w", marshalUserType, params[1], err)
+	}
+	udt := &UserDefined{Keyspace: params[0], Name: string(name)}
+	for _, param := range params[2:] {
+		sep := strings.IndexByte(param, ':')
+		if sep < 0 {
+			return nil, fmt.Errorf("invalid %v field definition: %q", marshalUserType, param)
+		}
+		fieldName, err := hex.DecodeString(param[:sep])
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode %v field name %q: %w", marshalUserType, param[:sep], err)
+		}
+		fieldType, err := parseMarshalType(param[sep+1:])
+		if err != nil {
+			return nil, err
+		}
+		udt.FieldNames = append(udt.FieldNames, string(fieldName))
+		udt.FieldTypes = append(udt.FieldTypes, fieldType)
+	}
+	return udt, nil
+}
+
+// parseMarshalVectorType parses VectorType parameters: element type and number of dimensions.
+func parseMarshalVectorType(params []string) (DataType, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("%v expects 2 parameter(s), got %d", marshalVectorType, len(params))
+	}
+	elementType, err := parseMarshalType(params[0])
+	if err != nil {
+		return nil, err
+	}
+	dimensions, err := strconv.Atoi(params[1])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %v dimensions %q: %w", marshalVectorType, params[1], err)
+	} else if dimensions <= 0 {
+		return nil, fmt.Errorf("%v dimensions must be strictly positive, got %d", marshalVectorType, dimensions)
+	}
+	return NewVector(elementType, dimensions), nil
+}
+
+// splitMarshalClassName splits the given class name into its name and its top-level parameters; if the class name
+// has no parameters, the returned slice is nil.
+func splitMarshalClassName(className string) (name string, params []string, err error) {
+	if className == "" {
+		return "", nil, fmt.Errorf("empty class name")
+	}
+	open := strings.IndexByte(className, '(')
+	if open < 0 {
+		if strings.ContainsAny(className, ",)") {
+			return "", nil, fmt.Errorf("unexpected character in class name %q", className)
+		}
+		return className, nil, nil
+	}
+	if className[len(className)-1] != ')' {
+		return "", nil, fmt.Errorf("missing closing parenthesis in %q", className)
+	}
+	name = strings.TrimSpace(className[:open])
+	params = []string{}
+	depth := 0
+	start := open + 1
+	for i := open + 1; i < len(className)-1; i++ {
+		switch className[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return "", nil, fmt.Errorf("unbalanced parentheses in %q", className)
+			}
+		case ',':
+			if depth == 0 {
+				params = append(params, strings.TrimSpace(className[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return "", nil, fmt.Errorf("unbalanced parentheses in %q", className)
+	}
+	if last := strings.TrimSpace(className[start : len(className)-1]); last != "" || len(params) > 0 {
+		params = append(params, last)
+	}
+	for _, param := range params {
+		if param == "" {
+			return "", nil, fmt.Errorf("empty parameter in %q", className)
+		}
+	}
+	return name, params, nil
+}
+
+func formatMarshalType(dt DataType, sb *strings.Builder) error {
+	if dt == nil {
+		return fmt.Errorf("data type cannot be nil")
+	}
+	if IsFrozen(dt) {
+		sb.WriteString(MarshalPackage)
+		sb.WriteString(marshalFrozenType)
+		sb.WriteByte('(')
+		defer sb.WriteByte(')')
+	}
+	switch t := dt.(type) {
+	case *Custom:
+		sb.WriteString(t.ClassName)
+	case *List:
+		return formatMarshalCollection(marshalListType, sb, t.ElementType)
+	case *Set:
+		return formatMarshalCollection(marshalSetType, sb, t.ElementType)
+	case *Map:
+		return formatMarshalCollection(marshalMapType, sb, t.KeyType, t.ValueType)
+	case *Tuple:
+		return formatMarshalCollection(marshalTupleType, sb, t.FieldTypes...)
+	case *UserDefined:
+		if len(t.FieldNames) != len(t.FieldTypes) {
+			return fmt.Errorf("invalid user-defined type: length of field names is not equal to length of field types")
+		}
+		sb.WriteString(MarshalPackage)
+		sb.WriteString(marshalUserType)
+		sb.WriteByte('(')
+		sb.WriteString(t.Keyspace)
+		sb.WriteByte(',')
+		sb.WriteString(hex.EncodeToString([]byte(t.Name)))
+		for i, fieldType := range t.FieldTypes {
+			sb.WriteByte(',')
+			sb.WriteString(hex.EncodeToString([]byte(t.FieldNames[i])))
+			sb.WriteByte(':')
+			if err := formatMarshalType(fieldType, sb); err != nil {
+				return err
+			}
+		}
+		sb.WriteByte(')')
+	case *Vector:
+		sb.WriteString(MarshalPackage)
+		sb.WriteString(marshalVectorType)
+		sb.WriteByte('(')
+		if err := formatMarshalType(t.ElementType, sb); err != nil {
+			return err
+		}
+		sb.WriteByte(',')
+		sb.WriteString(strconv.Itoa(t.Dimensions))
+		sb.WriteByte(')')
+	default:
+		className, found := marshalClassesByPrimitiveType[dt.Code()]
+		if !found {
+			return fmt.Errorf("unknown data type: %v", dt)
+		}
+		sb.WriteString(MarshalPackage)
+		sb.WriteString(className)
+	}
+	return nil
+}
+
+func formatMarshalCollection(name string, sb *strings.Builder, params ...DataType) error {
+	sb.WriteString(MarshalPackage)
+	sb.WriteString(name)
+	sb.WriteByte('(')
+	for i, param := range params {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := formatMarshalType(param, sb); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(')')
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMarshalType(t *testing.T) {
+	address, _ := NewUserDefined("ks1", "address", []string{"street", "zip"}, []DataType{Varchar, Int})
+	tests := []struct {
+		name     string
+		input    string
+		expected DataType
+	}{
+		{"ascii", "org.apache.cassandra.db.marshal.AsciiType", Ascii},
+		{"bigint", "org.apache.cassandra.db.marshal.LongType", Bigint},
+		{"blob", "org.apache.cassandra.db.marshal.BytesType", Blob},
+		{"boolean", "org.apache.cassandra.db.marshal.BooleanType", Boolean},
+		{"counter", "org.apache.cassandra.db.marshal.CounterColumnType", Counter},
+		{"date", "org.apache.cassandra.db.marshal.SimpleDateType", Date},
+		{"decimal", "org.apache.cassandra.db.marshal.DecimalType", Decimal},
+		{"double", "org.apache.cassandra.db.marshal.DoubleType", Double},
+		{"duration", "org.apache.cassandra.db.marshal.DurationType", Duration},
+		{"float", "org.apache.cassandra.db.marshal.FloatType", Float},
+		{"inet", "org.apache.cassandra.db.marshal.InetAddressType", Inet},
+		{"int", "org.apache.cassandra.db.marshal.Int32Type", Int},
+		{"smallint", "org.apache.cassandra.db.marshal.ShortType", Smallint},
+		{"time", "org.apache.cassandra.db.marshal.TimeType", Time},
+		{"timestamp", "org.apache.cassandra.db.marshal.TimestampType", Timestamp},
+		{"timestamp legacy", "org.apache.cassandra.db.marshal.DateType", Timestamp},
+		{"timeuuid", "org.apache.cassandra.db.marshal.TimeUUIDType", Timeuuid},
+		{"tinyint", "org.apache.cassandra.db.marshal.ByteType", Tinyint},
+		{"uuid", "org.apache.cassandra.db.marshal.UUIDType", Uuid},
+		{"varchar", "org.apache.cassandra.db.marshal.UTF8Type", Varchar},
+		{"varint", "org.apache.cassandra.db.marshal.IntegerType", Varint},
+		{"simple name", "UTF8Type", Varchar},
+		{"list", "org.apache.cassandra.db.marshal.ListType(org.apache.cassandra.db.marshal.Int32Type)", NewList(Int)},
+		{"set", "org.apache.cassandra.db.marshal.SetType(org.apache.cassandra.db.marshal.UTF8Type)", NewSet(Varchar)},
+		{
+			"map",
+			"org.apache.cassandra.db.marshal.MapType(org.apache.cassandra.db.marshal.UTF8Type,org.apache.cassandra.db.marshal.ReversedType(org.apache.cassandra.db.marshal.LongType))",
+			NewMap(Varchar, Bigint),
+		},
+		{
+			"tuple",
+			"org.apache.cassandra.db.marshal.TupleType(org.apache.cassandra.db.marshal.Int32Type,org.apache.cassandra.db.marshal.UTF8Type)",
+			NewTuple(Int, Varchar),
+		},
+		{
+			"frozen",
+			"org.apache.cassandra.db.marshal.FrozenType(org.apache.cassandra.db.marshal.ListType(org.apache.cassandra.db.marshal.Int32Type))",
+			&List{ElementType: Int, Frozen: true},
+		},
+		{
+			"reversed",
+			"org.apache.cassandra.db.marshal.ReversedType(org.apache.cassandra.db.marshal.TimeUUIDType)",
+			Timeuuid,
+		},
+		{
+			"udt",
+			"org.apache.cassandra.db.marshal.UserType(ks1,61646472657373,737472656574:org.apache.cassandra.db.marshal.UTF8Type,7a6970:org.apache.cassandra.db.marshal.Int32Type)",
+			address,
+		},
+		{
+			"spaces",
+			" ListType( Int32Type ) ",
+			NewList(Int),
+		},
+		{"custom", "com.example.FooType", NewCustom("com.example.FooType")},
+		{
+			"custom with params",
+			"org.apache.cassandra.db.marshal.DynamicCompositeType(s=>org.apache.cassandra.db.marshal.UTF8Type)",
+			NewCustom("org.apache.cassandra.db.marshal.DynamicCompositeType(s=>org.apache.cassandra.db.marshal.UTF8Type)"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseMarshalType(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseMarshalType_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", `cannot parse marshal type "": empty class name`},
+		{"unbalanced", "ListType(ListType(Int32Type)", `cannot parse marshal type "ListType(ListType(Int32Type)": unbalanced parentheses in "ListType(ListType(Int32Type)"`},
+		{"unclosed", "ListType(Int32Type", `cannot parse marshal type "ListType(Int32Type": missing closing parenthesis in "ListType(Int32Type"`},
+		{"wrong arity", "MapType(Int32Type)", `cannot parse marshal type "MapType(Int32Type)": MapType expects 2 parameter(s), got 1`},
+		{"empty param", "TupleType(Int32Type,)", `cannot parse marshal type "TupleType(Int32Type,)": empty parameter in "TupleType(Int32Type,)"`},
+		{"frozen primitive", "FrozenType(Int32Type)", `cannot parse marshal type "FrozenType(Int32Type)": data type int cannot be frozen`},
+		{"bad udt name", "UserType(ks1,zz)", `cannot parse marshal type "UserType(ks1,zz)": cannot decode UserType name "zz": encoding/hex: invalid byte: U+007A 'z'`},
+		{"bad udt field", "UserType(ks1,61,62)", `cannot parse marshal type "UserType(ks1,61,62)": invalid UserType field definition: "62"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseMarshalType(tt.input)
+			assert.Nil(t, actual)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestFormatMarshalType(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    DataType
+		expected string
+	}{
+		{"int", Int, "org.apache.cassandra.db.marshal.Int32Type"},
+		{"varchar", Varchar, "org.apache.cassandra.db.marshal.UTF8Type"},
+		{"custom", NewCustom("com.example.FooType"), "com.example.FooType"},
+		{
+			"map",
+			NewMap(Varchar, NewList(Timeuuid)),
+			"org.apache.cassandra.db.marshal.MapType(org.apache.cassandra.db.marshal.UTF8Type,org.apache.cassandra.db.marshal.ListType(org.apache.cassandra.db.marshal.TimeUUIDType))",
+		},
+		{
+			"frozen",
+			NewList(&UserDefined{Keyspace: "ks1", Name: "a", FieldNames: []string{"b"}, FieldTypes: []DataType{Int}, Frozen: true}),
+			"org.apache.cassandra.db.marshal.ListType(org.apache.cassandra.db.marshal.FrozenType(org.apache.cassandra.db.marshal.UserType(ks1,61,62:org.apache.cassandra.db.marshal.Int32Type)))",
+		},
+		{
+			"udt",
+			udt2,
+			"org.apache.cassandra.db.marshal.UserType(ks1,75647432,6631:org.apache.cassandra.db.marshal.UserType(ks1,75647431,6631:org.apache.cassandra.db.marshal.UTF8Type,6632:org.apache.cassandra.db.marshal.Int32Type))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := FormatMarshalType(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+			roundTrip, err := ParseMarshalType(actual)
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, roundTrip)
+		})
+	}
+	t.Run("nil", func(t *testing.T) {
+		_, err := FormatMarshalType(nil)
+		assert.EqualError(t, err, "cannot format marshal type for <nil>: data type cannot be nil")
+	})
+}
@@ -0,0 +1,304 @@
+// Copyright 2020 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatype
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/datastax/go-cassandra-native-protocol/internal/lexer"
+)
+
+// UserDefinedResolver is a function that resolves a user-defined type referenced by name in a CQL type literal.
+// The keyspace is the one that qualified the type name in the literal, or the parser's default keyspace if the
+// name was unqualified. Resolvers should return an error if the type cannot be found.
+type UserDefinedResolver func(keyspace string, name string) (*UserDefined, error)
+
+// ParseCqlType parses the given CQL type literal, e.g. "map<text,frozen<list<int>>>", into a DataType. User-defined
+// types referenced by name are returned as field-less UserDefined instances carrying only their keyspace and name;
+// use ParseCqlTypeWithResolver to obtain fully-resolved user-defined types. This function also accepts the output
+// of DataType.AsCql, including the inline field definitions that UserDefined.AsCql produces.
+func ParseCqlType(literal string) (DataType, error) {
+	return ParseCqlTypeWithResolver(literal, "", nil)
+}
+
+// ParseCqlTypeWithResolver parses the given CQL type literal into a DataType. Unqualified user-defined type names are
+// assumed to belong to defaultKeyspace. If resolver is not nil, it is invoked for every user-defined type referenced
+// by name (but not for user-defined types with inline field definitions).
+func ParseCqlTypeWithResolver(literal string, defaultKeyspace string, resolver UserDefinedResolver) (DataType, error) {
+	p := &cqlTypeParser{Lexer: lexer.Lexer{Input: literal}, defaultKeyspace: defaultKeyspace, resolver: resolver}
+	dt, err := p.parseType()
+	if err == nil {
+		p.SkipSpaces()
+		if !p.EOF() {
+			err = p.Errorf("unexpected trailing input")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse CQL type %q: %w", literal, err)
+	}
+	return dt, nil
+}
+
+var primitiveTypesByName = map[string]DataType{
+	"ascii":     Ascii,
+	"bigint":    Bigint,
+	"blob":      Blob,
+	"boolean":   Boolean,
+	"counter":   Counter,
+	"date":      Date,
+	"decimal":   Decimal,
+	"double":    Double,
+	"duration":  Duration,
+	"float":     Float,
+	"inet":      Inet,
+	"int":       Int,
+	"smallint":  Smallint,
+	"text":      Varchar,
+	"time":      Time,
+	"timestamp": Timestamp,
+	"timeuuid":  Timeuuid,
+	"tinyint":   Tinyint,
+	"uuid":      Uuid,
+	"varchar":   Varchar,
+	"varint":    Varint,
+}
+
+type cqlTypeParser struct {
+	lexer.Lexer
+	defaultKeyspace string
+	resolver        UserDefinedResolver
+}
+
+func (p *cqlTypeParser) parseType() (DataType, error) {
+	p.SkipSpaces()
+	if p.EOF() {
+		return nil, p.Errorf("expecting type, got end of input")
+	}
+	if p.Peek() == '\'' {
+		className, err := p.ParseQuoted('\'')
+		if err != nil {
+			return nil, err
+		} else if className == "" {
+			return nil, p.Errorf("empty quoted string")
+		}
+		return NewCustom(className), nil
+	}
+	start := p.Pos
+	name, quoted, err := p.ParseIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	if !quoted {
+		if dt, found := primitiveTypesByName[name]; found {
+			return dt, nil
+		}
+		switch name {
+		case "list":
+			return p.parseList()
+		case "set":
+			return p.parseSet()
+		case "map":
+			return p.parseMap()
+		case "tuple":
+			return p.parseTuple()
+		case "frozen":
+			return p.parseFrozen()
+		case "vector":
+			return p.parseVector()
+		}
+	}
+	p.Pos = start
+	return p.parseUserDefined()
+}
+
+func (p *cqlTypeParser) parseList() (DataType, error) {
+	if params, err := p.parseTypeParameters(1, 1); err != nil {
+		return nil, err
+	} else {
+		return NewList(params[0]), nil
+	}
+}
+
+func (p *cqlTypeParser) parseSet() (DataType, error) {
+	if params, err := p.parseTypeParameters(1, 1); err != nil {
+		return nil, err
+	} else {
+		return NewSet(params[0]), nil
+	}
+}
+
+func (p *cqlTypeParser) parseMap() (DataType, error) {
+	if params, err := p.parseTypeParameters(2, 2); err != nil {
+		return nil, err
+	} else {
+		return NewMap(params[0], params[1]), nil
+	}
+}
+
+func (p *cqlTypeParser) parseTuple() (DataType, error) {
+	if params, err := p.parseTypeParameters(1, -1); err != nil {
+		return nil, err
+	} else {
+		return NewTuple(params...), nil
+	}
+}
+
+func (p *cqlTypeParser) parseFrozen() (DataType, error) {
+	if params, err := p.parseTypeParameters(1, 1); err != nil {
+		return nil, err
+	} else if frozen, err := Freeze(params[0]); err != nil {
+		return nil, p.Errorf("%v", err)
+	} else {
+		return frozen, nil
+	}
+}
+
+func (p *cqlTypeParser) parseVector() (DataType, error) {
+	if err := p.Expect('<'); err != nil {
+		return nil, err
+	}
+	elementType, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.Expect(','); err != nil {
+		return nil, err
+	}
+	p.SkipSpaces()
+	start := p.Pos
+	for !p.EOF() && p.Peek() >= '0' && p.Peek() <= '9' {
+		p.Pos++
+	}
+	dimensions, err := strconv.Atoi(p.Input[start:p.Pos])
+	if err != nil || dimensions <= 0 {
+		return nil, p.Errorf("expecting strictly positive vector dimensions")
+	}
+	if err = p.Expect('>'); err != nil {
+		return nil, err
+	}
+	return NewVector(elementType, dimensions), nil
+}
+
+// parseTypeParameters parses a list of comma-separated types enclosed in angle brackets; if max is negative, the
+// number of parameters is unbounded.
+func (p *cqlTypeParser) parseTypeParameters(min int, max int) ([]DataType, error) {
+	if err := p.Expect('<'); err != nil {
+		return nil, err
+	}
+	var params []DataType
+	for {
+		param, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+		if done, err := p.parseListSeparator(); err != nil {
+			return nil, err
+		} else if done {
+			break
+		}
+	}
+	if len(params) < min || (max >= 0 && len(params) > max) {
+		if min == max {
+			return nil, p.Errorf("expecting %d type parameter(s), got %d", min, len(params))
+		}
+		return nil, p.Errorf("expecting at least %d type parameter(s), got %d", min, len(params))
+	}
+	return params, nil
+}
+
+func (p *cqlTypeParser) parseUserDefined() (DataType, error) {
+	keyspace := p.defaultKeyspace
+	name, _, err := p.ParseIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	p.SkipSpaces()
+	if !p.EOF() && p.Peek() == '.' {
+		p.Pos++
+		keyspace = name
+		if name, _, err = p.ParseIdentifier(); err != nil {
+			return nil, err
+		}
+	}
+	p.SkipSpaces()
+	if !p.EOF() && p.Peek() == '<' {
+		// inline field definitions, as produced by UserDefined.AsCql
+		return p.parseUserDefinedFields(keyspace, name)
+	}
+	if p.resolver == nil {
+		return &UserDefined{Keyspace: keyspace, Name: name}, nil
+	}
+	if udt, err := p.resolver(keyspace, name); err != nil {
+		return nil, fmt.Errorf("cannot resolve user-defined type %v.%v: %w", keyspace, name, err)
+	} else if udt == nil {
+		return nil, fmt.Errorf("cannot resolve user-defined type %v.%v", keyspace, name)
+	} else {
+		return udt, nil
+	}
+}
+
+func (p *cqlTypeParser) parseUserDefinedFields(keyspace string, name string) (DataType, error) {
+	if err := p.Expect('<'); err != nil {
+		return nil, err
+	}
+	udt := &UserDefined{Keyspace: keyspace, Name: name}
+	p.SkipSpaces()
+	if !p.EOF() && p.Peek() == '>' {
+		p.Pos++
+		return udt, nil
+	}
+	for {
+		fieldName, _, err := p.ParseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.Expect(':'); err != nil {
+			return nil, err
+		}
+		fieldType, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		udt.FieldNames = append(udt.FieldNames, fieldName)
+		udt.FieldTypes = append(udt.FieldTypes, fieldType)
+		if done, err := p.parseListSeparator(); err != nil {
+			return nil, err
+		} else if done {
+			break
+		}
+	}
+	return udt, nil
+}
+
+// parseListSeparator consumes either a comma, in which case it returns false, or a closing angle bracket, in which
+// case it returns true.
+func (p *cqlTypeParser) parseListSeparator() (bool, error) {
+	p.SkipSpaces()
+	if p.EOF() {
+		return false, p.Errorf("expecting ',' or '>', got end of input")
+	}
+	switch c := p.Peek(); c {
+	case ',':
+		p.Pos++
+		return false, nil
+	case '>':
+		p.Pos++
+		return true, nil
+	default:
+		return false, p.Errorf("expecting ',' or '>', got '%c'", c)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatype
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCqlType(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected DataType
+	}{
+		{"ascii", "ascii", Ascii},
+		{"bigint", "bigint", Bigint},
+		{"blob", "blob", Blob},
+		{"boolean", "boolean", Boolean},
+		{"counter", "counter", Counter},
+		{"date", "date", Date},
+		{"decimal", "decimal", Decimal},
+		{"double", "double", Double},
+		{"duration", "duration", Duration},
+		{"float", "float", Float},
+		{"inet", "inet", Inet},
+		{"int", "int", Int},
+		{"smallint", "smallint", Smallint},
+		{"text", "text", Varchar},
+		{"time", "time", Time},
+		{"timestamp", "timestamp", Timestamp},
+		{"timeuuid", "timeuuid", Timeuuid},
+		{"tinyint", "tinyint", Tinyint},
+		{"uuid", "uuid", Uuid},
+		{"varchar", "varchar", Varchar},
+		{"varint", "varint", Varint},
+		{"upper case", "BIGINT", Bigint},
+		{"spaces", "  int\t", Int},
+		{"list", "list<int>", NewList(Int)},
+		{"set", "set<text>", NewSet(Varchar)},
+		{"map", "map<text, int>", NewMap(Varchar, Int)},
+		{"tuple", "tuple<int,text,boolean>", NewTuple(Int, Varchar, Boolean)},
+		{"frozen list", "frozen<list<int>>", &List{ElementType: Int, Frozen: true}},
+		{"frozen set", "frozen<set<int>>", &Set{ElementType: Int, Frozen: true}},
+		{"frozen map", "FROZEN<map<int,text>>", &Map{KeyType: Int, ValueType: Varchar, Frozen: true}},
+		{"frozen tuple", "frozen<tuple<int>>", &Tuple{FieldTypes: []DataType{Int}, Frozen: true}},
+		{"frozen udt", "frozen<ks1.udt1>", &UserDefined{Keyspace: "ks1", Name: "udt1", Frozen: true}},
+		{"custom", "'org.apache.cassandra.db.marshal.BytesType'", NewCustom("org.apache.cassandra.db.marshal.BytesType")},
+		{"custom escaped", "'foo''bar'", NewCustom("foo'bar")},
+		{"udt qualified", "ks1.udt1", &UserDefined{Keyspace: "ks1", Name: "udt1"}},
+		{"udt unqualified", "udt1", &UserDefined{Name: "udt1"}},
+		{"udt quoted", `"Ks1"."My""Udt"`, &UserDefined{Keyspace: "Ks1", Name: `My"Udt`}},
+		{"udt inline fields", "ks1.udt1<f1:varchar,f2:int>", udt1},
+		{"udt nested inline fields", "ks1.udt2<f1:ks1.udt1<f1:varchar,f2:int>>", udt2},
+		{
+			"complex",
+			"map<text, frozen<list<tuple<int, udt_ks.address>>>>",
+			NewMap(Varchar, &List{ElementType: NewTuple(Int, &UserDefined{Keyspace: "udt_ks", Name: "address"}), Frozen: true}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseCqlType(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseCqlType_RoundTrip(t *testing.T) {
+	tests := []DataType{
+		Int,
+		Varchar,
+		NewList(Int),
+		NewSet(NewTuple(Int, Uuid)),
+		NewMap(Varchar, NewMap(Int, Blob)),
+		NewTuple(Int, NewList(Varchar), NewCustom("foo.bar")),
+		udt1,
+		udt2,
+		NewMap(udt1, NewList(udt2)),
+		&List{ElementType: &Set{ElementType: Int, Frozen: true}},
+		&Map{KeyType: &Tuple{FieldTypes: []DataType{Int}, Frozen: true}, ValueType: Int, Frozen: true},
+		&UserDefined{Keyspace: "ks1", Name: "udt3", FieldNames: []string{"f1"}, FieldTypes: []DataType{Int}, Frozen: true},
+	}
+	for _, expected := range tests {
+		t.Run(expected.AsCql(), func(t *testing.T) {
+			actual, err := ParseCqlType(expected.AsCql())
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+			assert.Equal(t, expected.AsCql(), actual.AsCql())
+		})
+	}
+}
+
+func TestParseCqlTypeWithResolver(t *testing.T) {
+	address, _ := NewUserDefined("ks1", "address", []string{"street", "zip"}, []DataType{Varchar, Int})
+	resolver := func(keyspace string, name string) (*UserDefined, error) {
+		if keyspace == "ks1" && name == "address" {
+			return address, nil
+		}
+		return nil, errors.New("not found")
+	}
+	t.Run("qualified", func(t *testing.T) {
+		actual, err := ParseCqlTypeWithResolver("list<frozen<ks1.address>>", "", resolver)
+		require.NoError(t, err)
+		frozenAddress, _ := Freeze(address)
+		assert.Equal(t, NewList(frozenAddress), actual)
+		assert.False(t, address.Frozen)
+	})
+	t.Run("default keyspace", func(t *testing.T) {
+		actual, err := ParseCqlTypeWithResolver("map<int,address>", "ks1", resolver)
+		require.NoError(t, err)
+		assert.Equal(t, NewMap(Int, address), actual)
+	})
+	t.Run("not found", func(t *testing.T) {
+		actual, err := ParseCqlTypeWithResolver("ks2.address", "ks1", resolver)
+		assert.Nil(t, actual)
+		assert.EqualError(t, err, `cannot parse CQL type "ks2.address": cannot resolve user-defined type ks2.address: not found`)
+	})
+}
+
+func TestParseCqlType_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", `cannot parse CQL type "": expecting type, got end of input at position 0`},
+		{"missing parameters", "list", `cannot parse CQL type "list": expecting '<', got end of input at position 4`},
+		{"unclosed", "list<int", `cannot parse CQL type "list<int": expecting ',' or '>', got end of input at position 8`},
+		{"too many parameters", "list<int,int>", `cannot parse CQL type "list<int,int>": expecting 1 type parameter(s), got 2 at position 13`},
+		{"too few parameters", "map<int>", `cannot parse CQL type "map<int>": expecting 2 type parameter(s), got 1 at position 8`},
+		{"trailing input", "int int", `cannot parse CQL type "int int": unexpected trailing input at position 4`},
+		{"unterminated custom", "'foo", `cannot parse CQL type "'foo": unterminated quoted string at position 4`},
+		{"empty custom", "''", `cannot parse CQL type "''": empty quoted string at position 2`},
+		{"frozen primitive", "frozen<int>", `cannot parse CQL type "frozen<int>": data type int cannot be frozen at position 11`},
+		{"invalid char", "list<#>", `cannot parse CQL type "list<#>": expecting identifier, got '#' at position 5`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseCqlType(tt.input)
+			assert.Nil(t, actual)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -26,6 +26,8 @@ import (
 // +k8s:deepcopy-gen:interfaces=github.com/datastax/go-cassandra-native-protocol/datatype.DataType
 type Set struct {
 	ElementType DataType
+	// Frozen indicates whether this is a frozen set; see IsFrozen.
+	Frozen bool
 }
 
 func (t *Set) Code() primitive.DataTypeCode {
@@ -37,7 +39,7 @@ func (t *Set) String() string {
 }
 
 func (t *Set) AsCql() string {
-	return fmt.Sprintf("set<%v>", t.ElementType.AsCql())
+	return frozenCql(fmt.Sprintf("set<%v>", t.ElementType.AsCql()), t.Frozen)
 }
 
 func NewSet(elementType DataType) *Set {
@@ -27,6 +27,8 @@ import (
 // +k8s:deepcopy-gen:interfaces=github.com/datastax/go-cassandra-native-protocol/datatype.DataType
 type Tuple struct {
 	FieldTypes []DataType
+	// Frozen indicates whether this tuple was explicitly declared as frozen; see IsFrozen.
+	Frozen bool
 }
 
 func NewTuple(fieldTypes ...DataType) *Tuple {
@@ -51,7 +53,7 @@ func (t *Tuple) AsCql() string {
 		buf.WriteString(elementType.AsCql())
 	}
 	buf.WriteString(">")
-	return buf.String()
+	return frozenCql(buf.String(), t.Frozen)
 }
 
 func writeTupleType(t DataType, dest io.Writer, version primitive.ProtocolVersion) (err error) {
@@ -31,6 +31,9 @@ type UserDefined struct {
 	FieldNames []string
 	FieldTypes []DataType
 	// Note: field names and field types are not modeled as a map because iteration order matters.
+
+	// Frozen indicates whether this is a frozen user-defined type; see IsFrozen.
+	Frozen bool
 }
 
 func NewUserDefined(keyspace string, name string, fieldNames []string, fieldTypes []DataType) (*UserDefined, error) {
@@ -65,7 +68,7 @@ func (t *UserDefined) AsCql() string {
 		buf.WriteString(fieldType.AsCql())
 	}
 	buf.WriteString(">")
-	return buf.String()
+	return frozenCql(buf.String(), t.Frozen)
 }
 
 func writeUserDefinedType(t DataType, dest io.Writer, version primitive.ProtocolVersion) (err error) {
@@ -0,0 +1,52 @@
+// Copyright 2020 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatype
+
+import (
+	"fmt"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// Vector is a data type that represents a CQL vector type, introduced in Cassandra 5. There is no dedicated type code
+// for vectors in the protocol: they are transmitted as custom types whose class name is
+// org.apache.cassandra.db.marshal.VectorType, parameterized by the element type and the number of dimensions.
+// +k8s:deepcopy-gen=true
+// +k8s:deepcopy-gen:interfaces=github.com/datastax/go-cassandra-native-protocol/datatype.DataType
+type Vector struct {
+	ElementType DataType
+	Dimensions  int
+}
+
+func NewVector(elementType DataType, dimensions int) *Vector {
+	return &Vector{ElementType: elementType, Dimensions: dimensions}
+}
+
+func (t *Vector) Code() primitive.DataTypeCode {
+	return primitive.DataTypeCodeCustom
+}
+
+func (t *Vector) String() string {
+	return t.AsCql()
+}
+
+func (t *Vector) AsCql() string {
+	return fmt.Sprintf("vector<%v,%d>", t.ElementType.AsCql(), t.Dimensions)
+}
+
+// ClassName returns the Cassandra marshal class name for this vector type, as it is transmitted on the wire.
+func (t *Vector) ClassName() (string, error) {
+	return FormatMarshalType(t)
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatype
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+const vectorOfFloat3 = "org.apache.cassandra.db.marshal.VectorType(org.apache.cassandra.db.marshal.FloatType,3)"
+
+func TestVectorType(t *testing.T) {
+	vectorType := NewVector(Float, 3)
+	assert.Equal(t, primitive.DataTypeCodeCustom, vectorType.Code())
+	assert.Equal(t, Float, vectorType.ElementType)
+	assert.Equal(t, 3, vectorType.Dimensions)
+	assert.Equal(t, "vector<float,3>", vectorType.AsCql())
+	className, err := vectorType.ClassName()
+	assert.NoError(t, err)
+	assert.Equal(t, vectorOfFloat3, className)
+}
+
+func TestVectorTypeDeepCopy(t *testing.T) {
+	vectorType := NewVector(NewList(Int), 3)
+	cloned := vectorType.DeepCopy()
+	assert.Equal(t, vectorType, cloned)
+	cloned.ElementType.(*List).ElementType = Varchar
+	cloned.Dimensions = 5
+	assert.NotEqual(t, vectorType, cloned)
+	assert.Equal(t, NewList(Int), vectorType.ElementType)
+	assert.Equal(t, 3, vectorType.Dimensions)
+	assert.Equal(t, NewList(Varchar), cloned.ElementType)
+	assert.Equal(t, 5, cloned.Dimensions)
+}
+
+func TestWriteReadVectorType(t *testing.T) {
+	expected := []byte{0, byte(primitive.DataTypeCodeCustom)}
+	expected = append(expected, 0, byte(len(vectorOfFloat3)))
+	expected = append(expected, vectorOfFloat3...)
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			dest := &bytes.Buffer{}
+			err := WriteDataType(NewVector(Float, 3), dest, version)
+			require.NoError(t, err)
+			assert.Equal(t, expected, dest.Bytes())
+			length, err := LengthOfDataType(NewVector(Float, 3), version)
+			require.NoError(t, err)
+			assert.Equal(t, len(expected), length)
+			actual, err := ReadDataType(bytes.NewReader(expected), version)
+			require.NoError(t, err)
+			assert.Equal(t, NewCustom(vectorOfFloat3), actual)
+			vectorType, ok := actual.(*Custom).AsVector()
+			assert.True(t, ok)
+			assert.Equal(t, NewVector(Float, 3), vectorType)
+		})
+	}
+}
+
+func TestCustomAsVector(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *Custom
+		expected *Vector
+	}{
+		{"vector", NewCustom(vectorOfFloat3), NewVector(Float, 3)},
+		{"vector short name", NewCustom("VectorType(UTF8Type,16)"), NewVector(Varchar, 16)},
+		{"invalid vector", NewCustom("VectorType(FloatType,abc)"), nil},
+		{"other custom", NewCustom("org.apache.cassandra.db.marshal.PointType"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := tt.input.AsVector()
+			assert.Equal(t, tt.expected != nil, ok)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseVectorType(t *testing.T) {
+	tests := []struct {
+		name     string
+		parse    func(string) (DataType, error)
+		input    string
+		expected DataType
+	}{
+		{"cql", ParseCqlType, "vector<float, 3>", NewVector(Float, 3)},
+		{"cql nested", ParseCqlType, "vector<list<text>,2>", NewVector(NewList(Varchar), 2)},
+		{"marshal", ParseMarshalType, vectorOfFloat3, NewVector(Float, 3)},
+		{"marshal simple", ParseMarshalType, "VectorType(UTF8Type,16)", NewVector(Varchar, 16)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+	t.Run("cql invalid dimensions", func(t *testing.T) {
+		_, err := ParseCqlType("vector<float,0>")
+		assert.EqualError(t, err, `cannot parse CQL type "vector<float,0>": expecting strictly positive vector dimensions at position 14`)
+	})
+	t.Run("marshal invalid dimensions", func(t *testing.T) {
+		_, err := ParseMarshalType("VectorType(FloatType,abc)")
+		assert.EqualError(t, err, `cannot parse marshal type "VectorType(FloatType,abc)": cannot parse VectorType dimensions "abc": strconv.Atoi: parsing "abc": invalid syntax`)
+	})
+}
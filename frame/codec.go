@@ -28,6 +28,20 @@ type Encoder interface {
 	EncodeFrame(frame *Frame, dest io.Writer) error
 }
 
+// StrictEncoder is an optional interface implemented by the codecs created by this package, e.g. with NewCodec or
+// NewRawCodec; use a type assertion to access it.
+type StrictEncoder interface {
+
+	// SetStrict enables or disables the strict mode, which is disabled by default. In strict mode, frame bodies are
+	// validated with message.Validate against the frame protocol version before being encoded, and encoding fails
+	// with an error wrapping a *message.ValidationError if the message contains fields that cannot be encoded with
+	// that version, instead of failing later or silently dropping them.
+	SetStrict(strict bool)
+
+	// IsStrict returns whether the strict mode is enabled, see SetStrict.
+	IsStrict() bool
+}
+
 type RawEncoder interface {
 
 	// EncodeRawFrame encodes the given RawFrame.
@@ -49,6 +63,16 @@ type Decoder interface {
 	DecodeFrame(source io.Reader) (*Frame, error)
 }
 
+// BytesDecoder is an optional interface implemented by the codecs created by this package, e.g. with NewCodec or
+// NewRawCodec; use a type assertion to access it.
+type BytesDecoder interface {
+
+	// DecodeFrameFromBytes decodes the entire frame contained in the given slice, decompressing the body if needed.
+	// The slice must contain exactly one frame. This is functionally equivalent to DecodeFrame, but considerably
+	// faster, since all primitives are read directly from the slice, see primitive.Cursor.
+	DecodeFrameFromBytes(source []byte) (*Frame, error)
+}
+
 type RawDecoder interface {
 
 	// DecodeRawFrame decodes a RawFrame from the given source.
@@ -101,8 +125,9 @@ type RawCodec interface {
 }
 
 type codec struct {
-	messageCodecs map[primitive.OpCode]message.Codec
-	compressor    BodyCompressor
+	registry   *message.CodecRegistry
+	compressor BodyCompressor
+	strict     bool
 }
 
 func NewCodec(messageCodecs ...message.Codec) Codec {
@@ -117,18 +142,24 @@ func NewRawCodec(messageCodecs ...message.Codec) RawCodec {
 	return NewRawCodecWithCompression(nil, messageCodecs...)
 }
 
+// NewRawCodecWithCompression creates a new RawCodec using the default message codecs, see
+// message.NewDefaultCodecRegistry. The given message codecs, if any, override the default ones for all protocol
+// versions and directions.
 func NewRawCodecWithCompression(compressor BodyCompressor, messageCodecs ...message.Codec) RawCodec {
-	frameCodec := &codec{
-		compressor:    compressor,
-		messageCodecs: make(map[primitive.OpCode]message.Codec, len(message.DefaultMessageCodecs)+len(messageCodecs)),
-	}
-	for _, messageCodec := range message.DefaultMessageCodecs {
-		frameCodec.messageCodecs[messageCodec.GetOpCode()] = messageCodec
-	}
+	registry := message.NewDefaultCodecRegistry()
 	for _, messageCodec := range messageCodecs {
-		frameCodec.messageCodecs[messageCodec.GetOpCode()] = messageCodec
+		registry.Register(messageCodec)
+	}
+	return NewRawCodecWithRegistry(compressor, registry)
+}
+
+// NewRawCodecWithRegistry creates a new RawCodec using the message codecs of the given registry. Frames whose opcode
+// has no registered codec for the frame protocol version and direction are decoded into a message.RawMessage.
+func NewRawCodecWithRegistry(compressor BodyCompressor, registry *message.CodecRegistry) RawCodec {
+	return &codec{
+		registry:   registry,
+		compressor: compressor,
 	}
-	return frameCodec
 }
 
 func (c *codec) GetBodyCompressor() BodyCompressor {
@@ -139,9 +170,17 @@ func (c *codec) SetBodyCompressor(compressor BodyCompressor) {
 	c.compressor = compressor
 }
 
-func (c *codec) findMessageCodec(opCode primitive.OpCode) (message.Codec, error) {
-	if encoder, found := c.messageCodecs[opCode]; !found {
-		return nil, fmt.Errorf("unsupported opcode %d", opCode)
+func (c *codec) SetStrict(strict bool) {
+	c.strict = strict
+}
+
+func (c *codec) IsStrict() bool {
+	return c.strict
+}
+
+func (c *codec) findMessageEncoder(msg message.Message, version primitive.ProtocolVersion) (message.Codec, error) {
+	if encoder := c.registry.FindEncoder(msg, version); encoder == nil {
+		return nil, fmt.Errorf("unsupported opcode %d for %v", msg.GetOpCode(), version)
 	} else {
 		return encoder, nil
 	}
@@ -0,0 +1,123 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frame
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// Compares DecodeFrame, reading from a bytes.Buffer, with DecodeFrameFromBytes, for a few typical messages.
+// Run with: go test ./frame -run NONE -bench Decode -benchmem
+func BenchmarkDecodeFrame(b *testing.B) {
+	codec := NewCodec()
+	for _, bb := range createBenchmarkMessages() {
+		encodedFrame := &bytes.Buffer{}
+		if err := codec.EncodeFrame(NewFrame(primitive.ProtocolVersion4, 1, bb.msg), encodedFrame); err != nil {
+			b.Fatal(err)
+		}
+		encoded := encodedFrame.Bytes()
+		b.Run(bb.name, func(b *testing.B) {
+			b.Run("DecodeFrame", func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(encoded)))
+				for i := 0; i < b.N; i++ {
+					if _, err := codec.DecodeFrame(bytes.NewBuffer(encoded)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run("DecodeFrameFromBytes", func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(encoded)))
+				for i := 0; i < b.N; i++ {
+					if _, err := codec.(BytesDecoder).DecodeFrameFromBytes(encoded); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func createBenchmarkMessages() []struct {
+	name string
+	msg  message.Message
+} {
+	values := make([]*primitive.Value, 10)
+	for i := range values {
+		values[i] = primitive.NewValue([]byte{0, 0, 0, byte(i)})
+	}
+	columns := make([]*message.ColumnMetadata, 5)
+	for i := range columns {
+		columns[i] = &message.ColumnMetadata{Keyspace: "ks1", Table: "table1", Name: fmt.Sprint("col", i), Type: datatype.Varchar}
+	}
+	rows := make(message.RowSet, 100)
+	for i := range rows {
+		rows[i] = make(message.Row, len(columns))
+		for j := range rows[i] {
+			rows[i][j] = []byte(fmt.Sprint("cell", i, j))
+		}
+	}
+	children := make([]*message.BatchChild, 10)
+	for i := range children {
+		children[i] = &message.BatchChild{Query: "INSERT INTO ks1.table1 (pk, cc, v) VALUES (?, ?, ?)", Values: values[:3]}
+	}
+	return []struct {
+		name string
+		msg  message.Message
+	}{
+		{"Startup", message.NewStartup()},
+		{"Query", &message.Query{
+			Query: "SELECT * FROM ks1.table1 WHERE pk = ? AND cc IN (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			Options: &message.QueryOptions{
+				Consistency:      primitive.ConsistencyLevelLocalQuorum,
+				PositionalValues: values,
+				PageSize:         5000,
+				PagingState:      []byte{0xca, 0xfe, 0xba, 0xbe},
+			},
+		}},
+		{"Execute", &message.Execute{
+			QueryId: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			Options: &message.QueryOptions{Consistency: primitive.ConsistencyLevelLocalQuorum, PositionalValues: values},
+		}},
+		{"Batch", &message.Batch{Children: children, Consistency: primitive.ConsistencyLevelLocalQuorum}},
+		{"RowsResult", &message.RowsResult{
+			Metadata: &message.RowsMetadata{ColumnCount: int32(len(columns)), Columns: columns},
+			Data:     rows,
+		}},
+		{"PreparedResult", &message.PreparedResult{
+			PreparedQueryId:   []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			VariablesMetadata: &message.VariablesMetadata{PkIndices: []uint16{0}, Columns: columns},
+			ResultMetadata:    &message.RowsMetadata{ColumnCount: int32(len(columns)), Columns: columns},
+		}},
+		{"Unavailable", &message.Unavailable{
+			ErrorMessage: "Cannot achieve consistency level LOCAL_QUORUM",
+			Consistency:  primitive.ConsistencyLevelLocalQuorum,
+			Required:     2,
+			Alive:        1,
+		}},
+		{"Supported", &message.Supported{Options: map[string][]string{
+			"CQL_VERSION":       {"3.4.5"},
+			"COMPRESSION":       {"lz4", "snappy"},
+			"PROTOCOL_VERSIONS": {"3/v3", "4/v4", "5/v5-beta"},
+		}}},
+	}
+}
@@ -16,6 +16,7 @@ package frame
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -61,6 +62,108 @@ func TestFrameEncodeDecode(t *testing.T) {
 	}
 }
 
+func TestEncodeFrame_TracedRequestBodyLength(t *testing.T) {
+	codec := NewCodec()
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			// requests never contain a tracing id, even when tracing is requested
+			request := NewFrame(version, 1, &message.Query{Query: "SELECT", Options: &message.QueryOptions{}})
+			request.RequestTracingId(true)
+			encoded := &bytes.Buffer{}
+			require.NoError(t, codec.EncodeFrame(request, encoded))
+			header, err := NewRawCodec().DecodeHeader(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, int32(encoded.Len()), header.BodyLength)
+		})
+	}
+}
+
+func TestDecodeFrameFromBytes(t *testing.T) {
+	codecs := createCodecs()
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			request, response := createFrames(version)
+			for algorithm, codec := range codecs {
+				t.Run(algorithm, func(t *testing.T) {
+					for _, frame := range []*Frame{request, response} {
+						encodedFrame := bytes.Buffer{}
+						require.NoError(t, codec.EncodeFrame(frame, &encodedFrame))
+						encoded := encodedFrame.Bytes()
+						bytesDecoder, ok := codec.(BytesDecoder)
+						require.True(t, ok)
+						decodedFrame, err := bytesDecoder.DecodeFrameFromBytes(encoded)
+						require.NoError(t, err)
+						require.Equal(t, frame, decodedFrame)
+						expectedFrame, err := codec.DecodeFrame(bytes.NewBuffer(encoded))
+						require.NoError(t, err)
+						require.Equal(t, expectedFrame, decodedFrame)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestDecodeFrameFromBytes_Errors(t *testing.T) {
+	codec := NewCodec()
+	encodedFrame := bytes.Buffer{}
+	require.NoError(t, codec.EncodeFrame(NewFrame(primitive.ProtocolVersion4, 1, message.NewStartup()), &encodedFrame))
+	encoded := encodedFrame.Bytes()
+	tests := []struct {
+		name   string
+		source []byte
+		err    string
+	}{
+		{"empty", []byte{}, "cannot decode frame header: cannot decode header version and direction: cannot read [byte]: EOF"},
+		{"truncated header", encoded[:5], "cannot decode frame header"},
+		{"truncated body", encoded[:len(encoded)-1], "cannot decode frame body: expected 22 bytes, got: 21"},
+		{"trailing bytes", append(append([]byte{}, encoded...), 0), "cannot decode frame body: expected 22 bytes, got: 23"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := codec.(BytesDecoder).DecodeFrameFromBytes(tt.source)
+			assert.Nil(t, decoded)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestCodec_Strict(t *testing.T) {
+	query := &message.Query{
+		Query:   "SELECT * FROM table1",
+		Options: &message.QueryOptions{Keyspace: "ks1", NowInSeconds: new(int32)},
+	}
+	for algorithm, codec := range createCodecs() {
+		t.Run(algorithm, func(t *testing.T) {
+			strict, ok := codec.(StrictEncoder)
+			require.True(t, ok)
+			assert.False(t, strict.IsStrict())
+			// lenient mode: the fields are written anyway with protocol version 4
+			require.NoError(t, codec.EncodeFrame(NewFrame(primitive.ProtocolVersion4, 1, query), &bytes.Buffer{}))
+			strict.SetStrict(true)
+			defer strict.SetStrict(false)
+			assert.True(t, strict.IsStrict())
+			frame := NewFrame(primitive.ProtocolVersion4, 1, query)
+			frame.SetCompress(algorithm != "NONE")
+			encoded := &bytes.Buffer{}
+			err := codec.EncodeFrame(frame, encoded)
+			var validationErr *message.ValidationError
+			require.True(t, errors.As(err, &validationErr))
+			assert.Len(t, validationErr.Violations, 2)
+			assert.EqualError(t, err, "cannot encode body message: invalid OpCode QUERY [0x07] message for ProtocolVersion OSS 4: "+
+				"Options.Keyspace: not supported; Options.NowInSeconds: not supported")
+			assert.Zero(t, encoded.Len())
+			_, err = codec.ConvertToRawFrame(frame)
+			assert.True(t, errors.As(err, &validationErr))
+			err = codec.EncodeBody(frame.Header, frame.Body, encoded)
+			assert.True(t, errors.As(err, &validationErr))
+			// valid messages are encoded as usual
+			require.NoError(t, codec.EncodeFrame(NewFrame(primitive.ProtocolVersion5, 1, query), encoded))
+		})
+	}
+}
+
 func TestRawFrameEncodeDecode(t *testing.T) {
 	codecs := createCodecs()
 	for _, version := range primitive.SupportedProtocolVersions() {
@@ -135,6 +238,44 @@ func TestConvertToRawFrame(t *testing.T) {
 	}
 }
 
+func TestLazyResultCodec(t *testing.T) {
+	codec := NewRawCodec(message.LazyResultCodec)
+	rows := &message.RowsResult{
+		Metadata: &message.RowsMetadata{ColumnCount: 2},
+		Data: message.RowSet{
+			{{0, 0, 0, 1}, {0xca, 0xfe}},
+			{{0, 0, 0, 2}, nil},
+		},
+	}
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			rawFrame, err := NewRawCodec().ConvertToRawFrame(NewFrame(version, 1, rows))
+			require.NoError(t, err)
+			t.Run("stream", func(t *testing.T) {
+				encoded := &bytes.Buffer{}
+				require.NoError(t, codec.EncodeRawFrame(rawFrame, encoded))
+				decoded, err := codec.DecodeFrame(encoded)
+				require.NoError(t, err)
+				materialized, err := decoded.Body.Message.(*message.LazyRowsResult).ToRowsResult()
+				require.NoError(t, err)
+				assert.Equal(t, rows.Data, materialized.Data)
+			})
+			t.Run("raw frame", func(t *testing.T) {
+				decoded, err := codec.ConvertFromRawFrame(rawFrame)
+				require.NoError(t, err)
+				lazy, ok := decoded.Body.Message.(*message.LazyRowsResult)
+				require.True(t, ok)
+				materialized, err := lazy.ToRowsResult()
+				require.NoError(t, err)
+				assert.Equal(t, rows, materialized)
+				// cells are slices of the raw frame body
+				materialized.Data[0][1][0] = 0xba
+				assert.Contains(t, string(rawFrame.Body), string([]byte{0xba, 0xfe}))
+			})
+		})
+	}
+}
+
 func createCodecs() map[string]RawCodec {
 	codecs := map[string]RawCodec{
 		"NONE":   NewRawCodec(),
@@ -160,3 +301,72 @@ func createFrames(version primitive.ProtocolVersion) (*Frame, *Frame) {
 	}
 	return request, response
 }
+
+func TestRawMessage(t *testing.T) {
+	codecs := createCodecs()
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			request, response := createFrames(version)
+			request.Body.Message = &message.RawMessage{OpCode: primitive.OpCode(0x42), Body: []byte{1, 2, 3}}
+			request.Header.OpCode = primitive.OpCode(0x42)
+			response.Body.Message = &message.RawMessage{OpCode: primitive.OpCode(0x43), Response: true, Body: []byte{4, 5}}
+			response.Header.OpCode = primitive.OpCode(0x43)
+			for algorithm, codec := range codecs {
+				t.Run(algorithm, func(t *testing.T) {
+					encoded := &bytes.Buffer{}
+					require.NoError(t, codec.EncodeFrame(request, encoded))
+					encodedRequest := append([]byte{}, encoded.Bytes()...)
+					require.NoError(t, codec.EncodeFrame(response, encoded))
+					// both frames are decoded from the same stream: the raw message must not read past its frame
+					decodedRequest, err := codec.DecodeFrame(encoded)
+					require.NoError(t, err)
+					assert.Equal(t, request, decodedRequest)
+					decodedResponse, err := codec.DecodeFrame(encoded)
+					require.NoError(t, err)
+					assert.Equal(t, response, decodedResponse)
+					decodedRequest, err = codec.(BytesDecoder).DecodeFrameFromBytes(encodedRequest)
+					require.NoError(t, err)
+					assert.Equal(t, request, decodedRequest)
+				})
+			}
+		})
+	}
+}
+
+func TestNewRawCodecWithRegistry(t *testing.T) {
+	opaque := NewRawCodecWithRegistry(nil, message.NewCodecRegistry())
+	codec := NewRawCodec()
+	original := NewFrame(primitive.ProtocolVersion4, 1, &message.Query{Query: "SELECT", Options: &message.QueryOptions{}})
+	encoded := &bytes.Buffer{}
+	require.NoError(t, codec.EncodeFrame(original, encoded))
+	decoded, err := opaque.DecodeFrame(encoded)
+	require.NoError(t, err)
+	raw, ok := decoded.Body.Message.(*message.RawMessage)
+	require.True(t, ok)
+	assert.Equal(t, primitive.OpCodeQuery, raw.GetOpCode())
+	assert.False(t, raw.IsResponse())
+	// raw messages are forwarded as is, even if a codec is registered for their opcode
+	require.NoError(t, codec.EncodeFrame(decoded, encoded))
+	decoded, err = codec.DecodeFrame(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, original, decoded)
+	_, err = opaque.ConvertToRawFrame(original)
+	assert.EqualError(t, err, "cannot encode body: unsupported opcode 7 for ProtocolVersion OSS 4")
+}
+
+func TestCodec_Revise(t *testing.T) {
+	codec := NewRawCodec()
+	revise := &message.Revise{RevisionType: primitive.DseRevisionTypeCancelContinuousPaging, TargetStreamId: 12}
+	rawFrame, err := codec.ConvertToRawFrame(NewFrame(primitive.ProtocolVersionDse2, 1, revise))
+	require.NoError(t, err)
+	decoded, err := codec.ConvertFromRawFrame(rawFrame)
+	require.NoError(t, err)
+	assert.Equal(t, revise, decoded.Body.Message)
+	// opcode 0xFF is only registered for DSE protocol versions
+	rawFrame.Header.Version = primitive.ProtocolVersion4
+	decoded, err = codec.ConvertFromRawFrame(rawFrame)
+	require.NoError(t, err)
+	assert.Equal(t, &message.RawMessage{OpCode: primitive.OpCodeDseRevise, Body: rawFrame.Body}, decoded.Body.Message)
+	_, err = codec.ConvertToRawFrame(NewFrame(primitive.ProtocolVersion4, 1, revise))
+	assert.EqualError(t, err, "cannot encode body: unsupported opcode 255 for ProtocolVersion OSS 4")
+}
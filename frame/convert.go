@@ -17,6 +17,8 @@ package frame
 import (
 	"bytes"
 	"fmt"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
 )
 
 func (c *codec) ConvertToRawFrame(frame *Frame) (*RawFrame, error) {
@@ -32,7 +34,7 @@ func (c *codec) ConvertToRawFrame(frame *Frame) (*RawFrame, error) {
 }
 
 func (c *codec) ConvertFromRawFrame(frame *RawFrame) (*Frame, error) {
-	if body, err := c.DecodeBody(frame.Header, bytes.NewBuffer(frame.Body)); err != nil {
+	if body, err := c.DecodeBody(frame.Header, primitive.NewCursor(frame.Body)); err != nil {
 		return nil, fmt.Errorf("cannot decode body: %w", err)
 	} else {
 		return &Frame{
@@ -21,6 +21,7 @@ import (
 	"io"
 	"io/ioutil"
 
+	"github.com/datastax/go-cassandra-native-protocol/message"
 	"github.com/datastax/go-cassandra-native-protocol/primitive"
 )
 
@@ -34,6 +35,23 @@ func (c *codec) DecodeFrame(source io.Reader) (*Frame, error) {
 	}
 }
 
+func (c *codec) DecodeFrameFromBytes(source []byte) (*Frame, error) {
+	cursor := primitive.NewCursor(source)
+	header, err := c.DecodeHeader(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode frame header: %w", err)
+	} else if header.BodyLength < 0 {
+		return nil, fmt.Errorf("cannot decode frame body: invalid body length: %d", header.BodyLength)
+	} else if cursor.Len() != int(header.BodyLength) {
+		return nil, fmt.Errorf("cannot decode frame body: expected %d bytes, got: %d", header.BodyLength, cursor.Len())
+	}
+	body, err := c.DecodeBody(header, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode frame body: %w", err)
+	}
+	return &Frame{Header: header, Body: body}, nil
+}
+
 func (c *codec) DecodeRawFrame(source io.Reader) (*RawFrame, error) {
 	if header, err := c.DecodeHeader(source); err != nil {
 		return nil, fmt.Errorf("cannot decode frame header: %w", err)
@@ -76,8 +94,9 @@ func (c *codec) DecodeHeader(source io.Reader) (*Header, error) {
 		}
 		header.Flags = primitive.HeaderFlag(flags)
 		header.OpCode = primitive.OpCode(opCode)
-		if err := primitive.CheckValidOpCode(header.OpCode); err != nil {
-			return nil, err
+		// unknown opcodes are accepted, their bodies will be decoded as raw messages
+		if !header.OpCode.IsValid() {
+			return header, nil
 		} else if isResponse {
 			if err := primitive.CheckResponseOpCode(header.OpCode); err != nil {
 				return nil, err
@@ -100,7 +119,7 @@ func (c *codec) DecodeBody(header *Header, source io.Reader) (body *Body, err er
 			if err := c.compressor.DecompressWithLength(io.LimitReader(source, int64(header.BodyLength)), decompressedBody); err != nil {
 				return nil, fmt.Errorf("cannot decompress body: %w", err)
 			} else {
-				source = decompressedBody
+				source = primitive.NewCursor(decompressedBody.Bytes())
 			}
 		}
 	}
@@ -120,14 +139,44 @@ func (c *codec) DecodeBody(header *Header, source io.Reader) (body *Body, err er
 			return nil, fmt.Errorf("cannot decode body warnings: %w", err)
 		}
 	}
-	if decoder, err := c.findMessageCodec(header.OpCode); err != nil {
-		return nil, err
+	if decoder := c.registry.Find(header.OpCode, header.Version, header.IsResponse); decoder == nil {
+		if body.Message, err = decodeRawMessage(header, body, source); err != nil {
+			return nil, fmt.Errorf("cannot decode body raw message: %w", err)
+		}
 	} else if body.Message, err = decoder.Decode(source, header.Version); err != nil {
 		return nil, fmt.Errorf("cannot decode body message: %w", err)
 	}
 	return body, err
 }
 
+// decodeRawMessage reads the rest of the body, which must be exactly consumed since its length is not encoded.
+func decodeRawMessage(header *Header, body *Body, source io.Reader) (*message.RawMessage, error) {
+	var length int
+	if header.Flags.Contains(primitive.HeaderFlagCompressed) {
+		// the source contains exactly the decompressed body
+		length = source.(*primitive.Cursor).Len()
+	} else {
+		length = int(header.BodyLength)
+		if header.IsResponse && header.Flags.Contains(primitive.HeaderFlagTracing) {
+			length -= primitive.LengthOfUuid
+		}
+		if header.Flags.Contains(primitive.HeaderFlagCustomPayload) {
+			length -= primitive.LengthOfBytesMap(body.CustomPayload)
+		}
+		if header.IsResponse && header.Flags.Contains(primitive.HeaderFlagWarning) {
+			length -= primitive.LengthOfStringList(body.Warnings)
+		}
+		if length < 0 {
+			return nil, fmt.Errorf("invalid message length: %d", length)
+		}
+	}
+	raw := &message.RawMessage{OpCode: header.OpCode, Response: header.IsResponse, Body: make([]byte, length)}
+	if _, err := io.ReadFull(source, raw.Body); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
 func (c *codec) DecodeRawBody(header *Header, source io.Reader) (body []byte, err error) {
 	if header.BodyLength < 0 {
 		return nil, fmt.Errorf("invalid body length: %d", header.BodyLength)
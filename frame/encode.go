@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/datastax/go-cassandra-native-protocol/message"
 	"github.com/datastax/go-cassandra-native-protocol/primitive"
 )
 
 func (c *codec) EncodeFrame(frame *Frame, dest io.Writer) error {
-	if frame.Header.Flags.Contains(primitive.HeaderFlagCompressed) {
+	if err := c.validateBody(frame.Header, frame.Body); err != nil {
+		return err
+	} else if frame.Header.Flags.Contains(primitive.HeaderFlagCompressed) {
 		return c.encodeFrameCompressed(frame, dest)
 	} else {
 		return c.encodeFrameUncompressed(frame, dest)
@@ -39,7 +42,7 @@ func (c *codec) encodeFrameUncompressed(frame *Frame, dest io.Writer) error {
 	}
 	if err := c.EncodeHeader(frame.Header, dest); err != nil {
 		return fmt.Errorf("cannot encode frame header: %w", err)
-	} else if err := c.EncodeBody(frame.Header, frame.Body, dest); err != nil {
+	} else if err := c.encodeBody(frame.Header, frame.Body, dest); err != nil {
 		return fmt.Errorf("cannot encode frame body: %w", err)
 	}
 	return nil
@@ -47,7 +50,7 @@ func (c *codec) encodeFrameUncompressed(frame *Frame, dest io.Writer) error {
 
 func (c *codec) encodeFrameCompressed(frame *Frame, dest io.Writer) error {
 	compressedBody := bytes.Buffer{}
-	if err := c.EncodeBody(frame.Header, frame.Body, &compressedBody); err != nil {
+	if err := c.encodeBody(frame.Header, frame.Body, &compressedBody); err != nil {
 		return fmt.Errorf("cannot encode frame body: %w", err)
 	} else {
 		frame.Header.BodyLength = int32(compressedBody.Len())
@@ -101,6 +104,23 @@ func (c *codec) EncodeHeader(header *Header, dest io.Writer) error {
 }
 
 func (c *codec) EncodeBody(header *Header, body *Body, dest io.Writer) error {
+	if err := c.validateBody(header, body); err != nil {
+		return err
+	}
+	return c.encodeBody(header, body, dest)
+}
+
+// validateBody validates the body message against the header protocol version, if strict mode is enabled.
+func (c *codec) validateBody(header *Header, body *Body) error {
+	if c.strict {
+		if err := message.Validate(body.Message, header.Version); err != nil {
+			return fmt.Errorf("cannot encode body message: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *codec) encodeBody(header *Header, body *Body, dest io.Writer) error {
 	if header.OpCode != body.Message.GetOpCode() {
 		return fmt.Errorf("opcode mismatch between header and body: %d != %d", header.OpCode, body.Message.GetOpCode())
 	} else if header.Flags.Contains(primitive.HeaderFlagCompressed) {
@@ -142,7 +162,7 @@ func (c *codec) encodeBodyUncompressed(header *Header, body *Body, dest io.Write
 			return fmt.Errorf("cannot encode body warnings: %w", err)
 		}
 	}
-	if encoder, err := c.findMessageCodec(body.Message.GetOpCode()); err != nil {
+	if encoder, err := c.findMessageEncoder(body.Message, header.Version); err != nil {
 		return err
 	} else if err = encoder.Encode(body.Message, dest, header.Version); err != nil {
 		return fmt.Errorf("cannot encode body message: %w", err)
@@ -151,12 +171,12 @@ func (c *codec) encodeBodyUncompressed(header *Header, body *Body, dest io.Write
 }
 
 func (c *codec) uncompressedBodyLength(header *Header, body *Body) (length int, err error) {
-	if encoder, err := c.findMessageCodec(body.Message.GetOpCode()); err != nil {
+	if encoder, err := c.findMessageEncoder(body.Message, header.Version); err != nil {
 		return -1, err
 	} else if length, err = encoder.EncodedLength(body.Message, header.Version); err != nil {
 		return -1, fmt.Errorf("cannot compute message length: %w", err)
 	}
-	if header.Flags.Contains(primitive.HeaderFlagTracing) {
+	if header.Flags.Contains(primitive.HeaderFlagTracing) && body.Message.IsResponse() {
 		length += primitive.LengthOfUuid
 	}
 	if header.Flags.Contains(primitive.HeaderFlagCustomPayload) {
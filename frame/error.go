@@ -0,0 +1,84 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frame
+
+import (
+	"fmt"
+
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// ResponseError is a Go error representing an ERROR response frame. Besides the error message itself, it carries the
+// frame-level information that came with it, such as warnings and tracing id. ResponseError wraps its error message,
+// which can thus be inspected with errors.Is and errors.As:
+//
+//	var readTimeout *message.ReadTimeout
+//	if errors.As(err, &readTimeout) {
+//	  ...
+//	}
+type ResponseError struct {
+	Version   primitive.ProtocolVersion
+	StreamId  int16
+	TracingId *primitive.UUID
+	// The custom payload, or nil if no custom payload was sent.
+	CustomPayload map[string][]byte
+	// Query warnings, if any.
+	Warnings []string
+	// The error message sent by the server; never nil.
+	Message message.Error
+}
+
+// NewResponseError converts the given ERROR response frame into a ResponseError. It returns nil if the frame is not
+// an ERROR response, which allows it to be used as follows:
+//
+//	if err := frame.NewResponseError(response); err != nil {
+//	  return err
+//	}
+func NewResponseError(frame *Frame) error {
+	if frame == nil || frame.Body == nil {
+		return nil
+	}
+	msg, ok := frame.Body.Message.(message.Error)
+	if !ok {
+		return nil
+	}
+	return &ResponseError{
+		Version:       frame.Header.Version,
+		StreamId:      frame.Header.StreamId,
+		TracingId:     frame.Body.TracingId,
+		CustomPayload: frame.Body.CustomPayload,
+		Warnings:      frame.Body.Warnings,
+		Message:       msg,
+	}
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("server replied with %v to request on stream %d", e.Message, e.StreamId)
+}
+
+// Unwrap returns the wrapped error message, or nil if it does not implement the error interface, which is the case
+// of custom Error implementations only.
+func (e *ResponseError) Unwrap() error {
+	if err, ok := e.Message.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// GetErrorCode returns the error code of the wrapped error message.
+func (e *ResponseError) GetErrorCode() primitive.ErrorCode {
+	return e.Message.GetErrorCode()
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frame
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+func TestNewResponseError(t *testing.T) {
+	unavailable := &message.Unavailable{ErrorMessage: "unavailable", Consistency: primitive.ConsistencyLevelQuorum, Required: 2, Alive: 1}
+	f := NewFrame(primitive.ProtocolVersion4, 12, unavailable)
+	f.SetTracingId(&primitive.UUID{0x01, 0x02})
+	f.SetWarnings([]string{"warning"})
+	f.SetCustomPayload(map[string][]byte{"key": {0x01}})
+	err := NewResponseError(f)
+	require.Error(t, err)
+	var responseErr *ResponseError
+	require.True(t, errors.As(err, &responseErr))
+	assert.Equal(t, &ResponseError{
+		Version:       primitive.ProtocolVersion4,
+		StreamId:      12,
+		TracingId:     &primitive.UUID{0x01, 0x02},
+		CustomPayload: map[string][]byte{"key": {0x01}},
+		Warnings:      []string{"warning"},
+		Message:       unavailable,
+	}, responseErr)
+	assert.Equal(t, primitive.ErrorCodeUnavailable, responseErr.GetErrorCode())
+	var actual *message.Unavailable
+	require.True(t, errors.As(err, &actual))
+	assert.Same(t, unavailable, actual)
+	assert.True(t, errors.Is(err, &message.Unavailable{}))
+	assert.False(t, errors.Is(err, &message.Overloaded{}))
+	code, ok := message.GetErrorCode(err)
+	assert.True(t, ok)
+	assert.True(t, code.IsRetryable())
+	assert.EqualError(t, err, "server replied with "+unavailable.String()+" to request on stream 12")
+}
+
+func TestNewResponseError_NotAnError(t *testing.T) {
+	assert.NoError(t, NewResponseError(nil))
+	assert.NoError(t, NewResponseError(NewFrame(primitive.ProtocolVersion4, 1, &message.Ready{})))
+	assert.NoError(t, NewResponseError(&Frame{Header: &Header{}}))
+}
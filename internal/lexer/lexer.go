@@ -0,0 +1,125 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lexer contains the scanning primitives shared by the CQL type parser of the datatype package and the CQL
+// literal parser of the datacodec package.
+package lexer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lexer scans a CQL input string. Parsers embed it and advance Pos as they consume the input.
+type Lexer struct {
+	Input string
+	Pos   int
+}
+
+// ParseIdentifier parses an unquoted or double-quoted CQL identifier. Unquoted identifiers are case-insensitive and
+// are returned in lower case; quoted identifiers are returned verbatim, with doubled quotes unescaped, and cannot be
+// empty.
+func (l *Lexer) ParseIdentifier() (identifier string, quoted bool, err error) {
+	l.SkipSpaces()
+	if l.EOF() {
+		return "", false, l.Errorf("expecting identifier, got end of input")
+	} else if l.Peek() == '"' {
+		if identifier, err = l.ParseQuoted('"'); err == nil && identifier == "" {
+			err = l.Errorf("empty quoted string")
+		}
+		return identifier, true, err
+	}
+	start := l.Pos
+	for !l.EOF() && IsIdentifierChar(l.Peek()) {
+		l.Pos++
+	}
+	if start == l.Pos {
+		return "", false, l.Errorf("expecting identifier, got '%c'", l.Peek())
+	}
+	return strings.ToLower(l.Input[start:l.Pos]), false, nil
+}
+
+// ParseQuoted parses a string enclosed in the given quote character; the quote character can be escaped by doubling
+// it.
+func (l *Lexer) ParseQuoted(quote byte) (string, error) {
+	if err := l.Expect(quote); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for {
+		if l.EOF() {
+			return "", l.Errorf("unterminated quoted string")
+		}
+		c := l.Input[l.Pos]
+		l.Pos++
+		if c == quote {
+			if !l.EOF() && l.Peek() == quote {
+				l.Pos++
+			} else {
+				break
+			}
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String(), nil
+}
+
+// Accept consumes the given character if it is the next non-space character, and returns whether it did.
+func (l *Lexer) Accept(c byte) bool {
+	l.SkipSpaces()
+	if !l.EOF() && l.Peek() == c {
+		l.Pos++
+		return true
+	}
+	return false
+}
+
+// Expect consumes the given character, which must be the next non-space character.
+func (l *Lexer) Expect(c byte) error {
+	l.SkipSpaces()
+	if l.EOF() {
+		return l.Errorf("expecting '%c', got end of input", c)
+	} else if actual := l.Peek(); actual != c {
+		return l.Errorf("expecting '%c', got '%c'", c, actual)
+	}
+	l.Pos++
+	return nil
+}
+
+func (l *Lexer) SkipSpaces() {
+	for !l.EOF() && IsSpace(l.Peek()) {
+		l.Pos++
+	}
+}
+
+func (l *Lexer) Peek() byte {
+	return l.Input[l.Pos]
+}
+
+func (l *Lexer) EOF() bool {
+	return l.Pos >= len(l.Input)
+}
+
+// Errorf returns an error with the given message, followed by the current position.
+func (l *Lexer) Errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w at position %d", fmt.Errorf(format, args...), l.Pos)
+}
+
+func IsIdentifierChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+func IsSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
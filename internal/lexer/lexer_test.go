@@ -0,0 +1,73 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLexer_ParseIdentifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		identifier string
+		quoted     bool
+		err        string
+	}{
+		{"unquoted", "  Foo_1>", "foo_1", false, ""},
+		{"quoted", `"Foo ""bar"""`, `Foo "bar"`, true, ""},
+		{"empty quoted", `""`, "", true, "empty quoted string at position 2"},
+		{"unterminated", `"foo`, "", true, "unterminated quoted string at position 4"},
+		{"end of input", " ", "", false, "expecting identifier, got end of input at position 1"},
+		{"invalid char", "#", "", false, "expecting identifier, got '#' at position 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Lexer{Input: tt.input}
+			identifier, quoted, err := l.ParseIdentifier()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.identifier, identifier)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+			assert.Equal(t, tt.quoted, quoted)
+		})
+	}
+}
+
+func TestLexer_ParseQuoted(t *testing.T) {
+	l := &Lexer{Input: " 'it''s' ''"}
+	actual, err := l.ParseQuoted('\'')
+	assert.NoError(t, err)
+	assert.Equal(t, "it's", actual)
+	actual, err = l.ParseQuoted('\'')
+	assert.NoError(t, err)
+	assert.Equal(t, "", actual)
+	assert.True(t, l.EOF())
+	_, err = l.ParseQuoted('\'')
+	assert.EqualError(t, err, "expecting ''', got end of input at position 11")
+}
+
+func TestLexer_AcceptAndExpect(t *testing.T) {
+	l := &Lexer{Input: " ( ,"}
+	assert.False(t, l.Accept(','))
+	assert.True(t, l.Accept('('))
+	assert.EqualError(t, l.Expect(')'), "expecting ')', got ',' at position 3")
+	assert.NoError(t, l.Expect(','))
+	assert.True(t, l.EOF())
+}
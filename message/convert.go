@@ -0,0 +1,517 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// ConvertVersion converts the given message, decoded from (or meant to be encoded with) the source protocol version,
+// into an equivalent message that can be encoded with the target protocol version. The given message is not modified;
+// the returned message is always a deep copy, even when both versions are equal. The returned message is valid for the
+// target version, see Validate.
+//
+// Fields that do not exist in the target version are dropped, and fields that are mandatory in the target version are
+// emulated whenever possible, following what Cassandra itself does when talking to clients using older protocol
+// versions:
+//
+//   - the result metadata id of PreparedResult is computed from its result metadata, and the result metadata id of
+//     Execute is set to a placeholder value, the prepared query id, which forces the server to send the result
+//     metadata back;
+//   - data types that do not exist in the target version are converted to custom types, and converted back when
+//     possible;
+//   - collection values in RowsResult and LazyRowsResult are re-encoded when converting from or to protocol version 2;
+//   - ReadFailure, WriteFailure and FunctionFailure errors are converted to ReadTimeout, WriteTimeout and Invalid errors
+//     respectively when converting to protocol versions lesser than 4, and failure reason maps are converted to failure
+//     counts (and vice versa) when converting from (or to) protocol version 5 or higher;
+//   - schema changes affecting objects that do not exist in the target version are converted to keyspace updates.
+//
+// The returned violations report all the fields that could not be represented exactly in the target version. When no
+// violations are returned, converting the returned message back to the source version yields the original message.
+//
+// An error is returned if the message cannot be represented at all in the target version, e.g. a DSE Revise request
+// converted to an OSS protocol version, or a query with unset values converted to protocol version 3. Bound values of
+// Query, Execute and Batch messages are not re-encoded, since their types are unknown; when converting from or to
+// protocol version 2, collection values, if any, thus need to be converted by the caller.
+func ConvertVersion(msg Message, source primitive.ProtocolVersion, target primitive.ProtocolVersion) (converted Message, lost []*Violation, err error) {
+	if err = primitive.CheckSupportedProtocolVersion(source); err != nil {
+		return nil, nil, err
+	} else if err = primitive.CheckSupportedProtocolVersion(target); err != nil {
+		return nil, nil, err
+	}
+	converted = msg.DeepCopyMessage()
+	if source == target {
+		return converted, nil, nil
+	}
+	c := &versionConversion{source: source, target: target}
+	switch m := converted.(type) {
+	case *Query:
+		err = c.queryOptions("Options.", m.Options)
+	case *Prepare:
+		c.drop("Keyspace", &m.Keyspace, target.SupportsPrepareFlags())
+	case *Execute:
+		if target.SupportsResultMetadataId() {
+			if len(m.ResultMetadataId) == 0 {
+				m.ResultMetadataId = append([]byte{}, m.QueryId...)
+			}
+		} else if len(m.ResultMetadataId) > 0 {
+			m.ResultMetadataId = nil
+			c.lose("ResultMetadataId", "dropped")
+		}
+		err = c.queryOptions("Options.", m.Options)
+	case *Batch:
+		err = c.batch(m)
+	case *Revise:
+		if !target.IsDse() {
+			err = errors.New("DSE protocol version required")
+		} else if !target.SupportsDseRevisionType(m.RevisionType) {
+			err = fmt.Errorf("RevisionType: %v not supported", m.RevisionType)
+		}
+	case *SchemaChangeEvent:
+		c.schemaChange(&m.ChangeType, &m.Target, &m.Keyspace, &m.Object, &m.Arguments)
+	case *TopologyChangeEvent:
+		if !target.SupportsTopologyChangeType(m.ChangeType) {
+			err = fmt.Errorf("ChangeType: %v not supported", m.ChangeType)
+		}
+	case *SchemaChangeResult:
+		c.schemaChange(&m.ChangeType, &m.Target, &m.Keyspace, &m.Object, &m.Arguments)
+	case *PreparedResult:
+		err = c.preparedResult(m)
+	case *RowsResult:
+		err = c.rowsResult(m)
+	case *LazyRowsResult:
+		err = c.lazyRowsResult(m)
+	case *WriteTimeout:
+		if m.Contentions != 0 && !(target.SupportsWriteTimeoutContentions() && m.WriteType == primitive.WriteTypeCas) {
+			m.Contentions = 0
+			c.lose("Contentions", "dropped")
+		}
+	case *ReadFailure:
+		if target < primitive.ProtocolVersion4 {
+			c.lose("", "READ FAILURE converted to READ TIMEOUT")
+			converted = &ReadTimeout{
+				ErrorMessage: m.ErrorMessage,
+				Consistency:  m.Consistency,
+				Received:     m.Received,
+				BlockFor:     m.BlockFor,
+				DataPresent:  m.DataPresent,
+			}
+		} else {
+			c.failures(&m.NumFailures, &m.FailureReasons)
+		}
+	case *WriteFailure:
+		if target < primitive.ProtocolVersion4 {
+			c.lose("", "WRITE FAILURE converted to WRITE TIMEOUT")
+			converted = &WriteTimeout{
+				ErrorMessage: m.ErrorMessage,
+				Consistency:  m.Consistency,
+				Received:     m.Received,
+				BlockFor:     m.BlockFor,
+				WriteType:    m.WriteType,
+			}
+		} else {
+			c.failures(&m.NumFailures, &m.FailureReasons)
+		}
+	case *FunctionFailure:
+		if target < primitive.ProtocolVersion4 {
+			c.lose("", "FUNCTION FAILURE converted to INVALID")
+			converted = &Invalid{ErrorMessage: m.ErrorMessage}
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot convert %v message from %v to %v: %w", msg.GetOpCode(), source, target, err)
+	}
+	return converted, c.lost, nil
+}
+
+// versionConversion accumulates the fields that could not be represented exactly when converting a message.
+type versionConversion struct {
+	source primitive.ProtocolVersion
+	target primitive.ProtocolVersion
+	lost   []*Violation
+}
+
+func (c *versionConversion) lose(field string, reason string) {
+	c.lost = append(c.lost, &Violation{Field: field, Reason: reason})
+}
+
+// drop clears the given string field if it is not supported.
+func (c *versionConversion) drop(field string, value *string, supported bool) {
+	if *value != "" && !supported {
+		*value = ""
+		c.lose(field, "dropped")
+	}
+}
+
+// collectionLengthChanged returns true if the encoding of collection values differs between both versions.
+func (c *versionConversion) collectionLengthChanged() bool {
+	return c.source.Uses4BytesCollectionLength() != c.target.Uses4BytesCollectionLength()
+}
+
+func (c *versionConversion) values(field string, values []*primitive.Value) error {
+	for i, value := range values {
+		if value != nil && value.Type == primitive.ValueTypeUnset && !c.target.SupportsUnsetValues() {
+			return fmt.Errorf("%s[%d]: unset values not supported", field, i)
+		}
+	}
+	if len(values) > 0 && c.collectionLengthChanged() {
+		c.lose(field, "collection values, if any, not converted")
+	}
+	return nil
+}
+
+func (c *versionConversion) queryOptions(prefix string, options *QueryOptions) error {
+	if options == nil {
+		return nil
+	}
+	if err := c.values(prefix+"PositionalValues", options.PositionalValues); err != nil {
+		return err
+	}
+	if options.PositionalValues == nil && options.NamedValues != nil {
+		if !c.target.SupportsQueryFlag(primitive.QueryFlagValueNames) {
+			return fmt.Errorf("%sNamedValues: named values not supported", prefix)
+		}
+		for name, value := range options.NamedValues {
+			if value != nil && value.Type == primitive.ValueTypeUnset && !c.target.SupportsUnsetValues() {
+				return fmt.Errorf("%sNamedValues[%s]: unset values not supported", prefix, name)
+			}
+		}
+		if c.collectionLengthChanged() {
+			c.lose(prefix+"NamedValues", "collection values, if any, not converted")
+		}
+	}
+	if options.PageSizeInBytes && !c.target.SupportsQueryFlag(primitive.QueryFlagDsePageSizeBytes) {
+		options.PageSizeInBytes = false
+		c.lose(prefix+"PageSizeInBytes", "dropped")
+	}
+	if options.DefaultTimestamp != nil && !c.target.SupportsQueryFlag(primitive.QueryFlagDefaultTimestamp) {
+		options.DefaultTimestamp = nil
+		c.lose(prefix+"DefaultTimestamp", "dropped")
+	}
+	c.drop(prefix+"Keyspace", &options.Keyspace, c.target.SupportsQueryFlag(primitive.QueryFlagWithKeyspace))
+	if options.NowInSeconds != nil && !c.target.SupportsQueryFlag(primitive.QueryFlagNowInSeconds) {
+		options.NowInSeconds = nil
+		c.lose(prefix+"NowInSeconds", "dropped")
+	}
+	if options.ContinuousPagingOptions != nil {
+		if !c.target.SupportsQueryFlag(primitive.QueryFlagDseWithContinuousPagingOptions) {
+			options.ContinuousPagingOptions = nil
+			c.lose(prefix+"ContinuousPagingOptions", "dropped")
+		} else if options.ContinuousPagingOptions.NextPages != 0 && c.target < primitive.ProtocolVersionDse2 {
+			options.ContinuousPagingOptions.NextPages = 0
+			c.lose(prefix+"ContinuousPagingOptions.NextPages", "dropped")
+		}
+	}
+	return nil
+}
+
+func (c *versionConversion) batch(batch *Batch) error {
+	for i, child := range batch.Children {
+		if child == nil {
+			continue
+		}
+		if err := c.values(fmt.Sprintf("Children[%d].Values", i), child.Values); err != nil {
+			return err
+		}
+	}
+	if batch.SerialConsistency != nil && !c.target.SupportsBatchQueryFlags() {
+		batch.SerialConsistency = nil
+		c.lose("SerialConsistency", "dropped")
+	}
+	if batch.DefaultTimestamp != nil && !c.target.SupportsQueryFlag(primitive.QueryFlagDefaultTimestamp) {
+		batch.DefaultTimestamp = nil
+		c.lose("DefaultTimestamp", "dropped")
+	}
+	c.drop("Keyspace", &batch.Keyspace, c.target.SupportsQueryFlag(primitive.QueryFlagWithKeyspace))
+	if batch.NowInSeconds != nil && !c.target.SupportsQueryFlag(primitive.QueryFlagNowInSeconds) {
+		batch.NowInSeconds = nil
+		c.lose("NowInSeconds", "dropped")
+	}
+	return nil
+}
+
+// schemaChange converts changes to schema objects that do not exist in the target version into keyspace updates.
+func (c *versionConversion) schemaChange(
+	changeType *primitive.SchemaChangeType,
+	target *primitive.SchemaChangeTarget,
+	keyspace *string,
+	object *string,
+	arguments *[]string,
+) {
+	if target.IsValid() && !c.target.SupportsSchemaChangeTarget(*target) {
+		c.lose("Target", fmt.Sprintf("%v %v converted to %v %v",
+			*target, *changeType, primitive.SchemaChangeTargetKeyspace, primitive.SchemaChangeTypeUpdated))
+		*changeType = primitive.SchemaChangeTypeUpdated
+		*target = primitive.SchemaChangeTargetKeyspace
+		*object = ""
+		*arguments = nil
+	} else if len(*arguments) > 0 && c.target < primitive.ProtocolVersion4 {
+		*arguments = nil
+		c.lose("Arguments", "dropped")
+	}
+}
+
+// failures converts failure reason maps to failure counts, and vice versa.
+func (c *versionConversion) failures(numFailures *int32, failureReasons *[]*primitive.FailureReason) {
+	if c.target.SupportsReadWriteFailureReasonMap() {
+		if *numFailures != 0 {
+			for i := int32(0); i < *numFailures; i++ {
+				*failureReasons = append(*failureReasons, &primitive.FailureReason{Endpoint: net.IPv4zero.To4(), Code: primitive.FailureCodeUnknown})
+			}
+			*numFailures = 0
+			c.lose("NumFailures", "converted to FailureReasons with unknown endpoints")
+		}
+	} else if len(*failureReasons) > 0 {
+		*numFailures = int32(len(*failureReasons))
+		*failureReasons = nil
+		c.lose("FailureReasons", "converted to NumFailures")
+	}
+}
+
+func (c *versionConversion) preparedResult(result *PreparedResult) (err error) {
+	if result.VariablesMetadata != nil {
+		if result.VariablesMetadata.PkIndices != nil && c.target < primitive.ProtocolVersion4 {
+			if len(result.VariablesMetadata.PkIndices) > 0 {
+				c.lose("VariablesMetadata.PkIndices", "dropped")
+			}
+			result.VariablesMetadata.PkIndices = nil
+		}
+		if err = c.columns("VariablesMetadata.Columns", result.VariablesMetadata.Columns); err != nil {
+			return err
+		}
+	}
+	if err = c.rowsMetadata("ResultMetadata.", result.ResultMetadata); err != nil {
+		return err
+	}
+	if c.target.SupportsResultMetadataId() {
+		if len(result.ResultMetadataId) == 0 {
+			// as Cassandra does, use a hash of the result metadata
+			buf := &bytes.Buffer{}
+			if err = encodeRowsMetadata(result.ResultMetadata, buf, c.target); err != nil {
+				return fmt.Errorf("ResultMetadata: cannot compute result metadata id: %w", err)
+			}
+			id := md5.Sum(buf.Bytes())
+			result.ResultMetadataId = id[:]
+		}
+	} else if len(result.ResultMetadataId) > 0 {
+		result.ResultMetadataId = nil
+		c.lose("ResultMetadataId", "dropped")
+	}
+	return nil
+}
+
+func (c *versionConversion) rowsMetadata(prefix string, metadata *RowsMetadata) error {
+	if metadata == nil {
+		return nil
+	}
+	if len(metadata.NewResultMetadataId) > 0 && !c.target.SupportsResultMetadataId() {
+		metadata.NewResultMetadataId = nil
+		c.lose(prefix+"NewResultMetadataId", "dropped")
+	}
+	if !c.target.IsDse() {
+		if metadata.ContinuousPageNumber != 0 {
+			metadata.ContinuousPageNumber = 0
+			c.lose(prefix+"ContinuousPageNumber", "dropped")
+		}
+		if metadata.LastContinuousPage {
+			metadata.LastContinuousPage = false
+			c.lose(prefix+"LastContinuousPage", "dropped")
+		}
+	}
+	return c.columns(prefix+"Columns", metadata.Columns)
+}
+
+func (c *versionConversion) columns(prefix string, columns []*ColumnMetadata) (err error) {
+	for i, column := range columns {
+		if column == nil {
+			continue
+		}
+		if column.Type, err = c.dataType(column.Type); err != nil {
+			return fmt.Errorf("%s[%d].Type: %w", prefix, i, err)
+		}
+	}
+	return nil
+}
+
+// dataType converts data types that do not exist in the target version to custom types, and custom types that were
+// only used because the source version lacked the actual type back to that type. The given type is modified in place.
+func (c *versionConversion) dataType(dt datatype.DataType) (converted datatype.DataType, err error) {
+	if dt == nil {
+		return nil, nil
+	}
+	if custom, ok := dt.(*datatype.Custom); ok {
+		if actual, err := datatype.ParseMarshalType(custom.ClassName); err == nil &&
+			actual.Code() != primitive.DataTypeCodeCustom &&
+			!c.source.SupportsDataType(actual.Code()) &&
+			c.target.SupportsDataType(actual.Code()) {
+			return c.dataType(actual)
+		}
+		return dt, nil
+	} else if !c.target.SupportsDataType(dt.Code()) {
+		className, err := datatype.FormatMarshalType(dt)
+		if err != nil {
+			return nil, err
+		}
+		return datatype.NewCustom(className), nil
+	}
+	switch t := dt.(type) {
+	case *datatype.List:
+		t.ElementType, err = c.dataType(t.ElementType)
+	case *datatype.Set:
+		t.ElementType, err = c.dataType(t.ElementType)
+	case *datatype.Map:
+		if t.KeyType, err = c.dataType(t.KeyType); err == nil {
+			t.ValueType, err = c.dataType(t.ValueType)
+		}
+	case *datatype.Tuple:
+		err = c.dataTypes(t.FieldTypes)
+	case *datatype.UserDefined:
+		err = c.dataTypes(t.FieldTypes)
+	}
+	return dt, err
+}
+
+func (c *versionConversion) dataTypes(types []datatype.DataType) (err error) {
+	for i, dt := range types {
+		if types[i], err = c.dataType(dt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *versionConversion) rowsResult(result *RowsResult) (err error) {
+	// collection values must be converted using the source types
+	var types []datatype.DataType
+	if result.Metadata != nil && len(result.Metadata.Columns) > 0 && c.collectionLengthChanged() {
+		types = make([]datatype.DataType, len(result.Metadata.Columns))
+		for i, column := range result.Metadata.Columns {
+			if column != nil {
+				types[i] = column.Type.DeepCopyDataType()
+			}
+		}
+	}
+	if err = c.rowsMetadata("Metadata.", result.Metadata); err != nil {
+		return err
+	}
+	return c.rows(result.Data, types)
+}
+
+func (c *versionConversion) lazyRowsResult(result *LazyRowsResult) (err error) {
+	if !c.collectionLengthChanged() || result.rowsCount == 0 {
+		return c.rowsMetadata("Metadata.", result.Metadata)
+	}
+	var rows *RowsResult
+	if rows, err = result.ToRowsResult(); err != nil {
+		return err
+	} else if err = c.rowsResult(rows); err != nil {
+		return err
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(result.data)))
+	for _, row := range rows.Data {
+		for _, cell := range row {
+			_ = primitive.WriteBytes(cell, buf)
+		}
+	}
+	result.data = buf.Bytes()
+	return nil
+}
+
+// rows converts collection values in the given rows in place, if needed.
+func (c *versionConversion) rows(rows RowSet, types []datatype.DataType) (err error) {
+	if !c.collectionLengthChanged() || len(rows) == 0 {
+		return nil
+	} else if types == nil {
+		c.lose("Data", "collection values, if any, not converted: no column metadata")
+		return nil
+	}
+	for i, row := range rows {
+		for j, cell := range row {
+			if j < len(types) {
+				if row[j], err = c.value(cell, types[j]); err != nil {
+					return fmt.Errorf("Data[%d][%d]: %w", i, j, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// value converts the given value of the given type, which only differs between versions for collections.
+func (c *versionConversion) value(value []byte, dt datatype.DataType) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch t := dt.(type) {
+	case *datatype.List:
+		return c.collection(value, t.ElementType)
+	case *datatype.Set:
+		return c.collection(value, t.ElementType)
+	case *datatype.Map:
+		return c.collection(value, t.KeyType, t.ValueType)
+	}
+	return value, nil
+}
+
+// collection converts the size of the given collection value, then its elements. Elements of maps are alternatively
+// of the key and value types.
+func (c *versionConversion) collection(value []byte, elementTypes ...datatype.DataType) ([]byte, error) {
+	source := primitive.NewCursor(value)
+	var size int
+	if c.source.Uses4BytesCollectionLength() {
+		size32, err := primitive.ReadInt(source)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read collection size: %w", err)
+		}
+		size = int(size32)
+	} else {
+		size16, err := primitive.ReadShort(source)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read collection size: %w", err)
+		}
+		size = int(size16)
+	}
+	dest := &bytes.Buffer{}
+	if c.target.Uses4BytesCollectionLength() {
+		_ = primitive.WriteInt(int32(size), dest)
+	} else if size > math.MaxUint16 {
+		return nil, fmt.Errorf("collection size too large: %d", size)
+	} else {
+		_ = primitive.WriteShort(uint16(size), dest)
+	}
+	for i := 0; i < size; i++ {
+		for _, elementType := range elementTypes {
+			element, err := primitive.ReadBytes(source)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read collection element %d: %w", i, err)
+			} else if element, err = c.value(element, elementType); err != nil {
+				return nil, fmt.Errorf("cannot convert collection element %d: %w", i, err)
+			}
+			_ = primitive.WriteBytes(element, dest)
+		}
+	}
+	if source.Len() > 0 {
+		return nil, fmt.Errorf("collection value has %d trailing bytes", source.Len())
+	}
+	return dest.Bytes(), nil
+}
@@ -0,0 +1,381 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// encodeCollection encodes a collection value containing the given elements for the given version.
+func encodeCollection(version primitive.ProtocolVersion, size int, elements ...[]byte) []byte {
+	buf := &bytes.Buffer{}
+	if version.Uses4BytesCollectionLength() {
+		_ = primitive.WriteInt(int32(size), buf)
+	} else {
+		_ = primitive.WriteShort(uint16(size), buf)
+	}
+	for _, element := range elements {
+		_ = primitive.WriteBytes(element, buf)
+	}
+	return buf.Bytes()
+}
+
+var (
+	convertInt1 = []byte{0, 0, 0, 1}
+	convertInt2 = []byte{0, 0, 0, 2}
+)
+
+func convertRowsMetadata() *RowsMetadata {
+	return &RowsMetadata{
+		ColumnCount: 3,
+		Columns: []*ColumnMetadata{
+			{Keyspace: "ks1", Table: "t1", Name: "c1", Type: datatype.NewList(datatype.Int)},
+			{Keyspace: "ks1", Table: "t1", Name: "c2", Type: datatype.NewMap(datatype.Varchar, datatype.NewSet(datatype.Int))},
+			{Keyspace: "ks1", Table: "t1", Name: "c3", Type: datatype.Int},
+		},
+	}
+}
+
+func convertRowsData(version primitive.ProtocolVersion) RowSet {
+	return RowSet{
+		{
+			encodeCollection(version, 2, convertInt1, convertInt2),
+			encodeCollection(version, 1, []byte("a"), encodeCollection(version, 2, convertInt1, convertInt2)),
+			convertInt1,
+		},
+		{
+			encodeCollection(version, 0),
+			nil,
+			convertInt2,
+		},
+	}
+}
+
+// convertCorpus returns messages exercising all the conversions, encoded for the given version where relevant.
+func convertCorpus(t *testing.T, version primitive.ProtocolVersion) []Message {
+	unset := &primitive.Value{Type: primitive.ValueTypeUnset}
+	udt, err := datatype.NewUserDefined("ks1", "udt1", []string{"f1"}, []datatype.DataType{datatype.Smallint})
+	require.NoError(t, err)
+	rows := &RowsResult{Metadata: convertRowsMetadata(), Data: convertRowsData(version)}
+	encodedRows := &bytes.Buffer{}
+	require.NoError(t, LazyResultCodec.Encode(rows, encodedRows, version))
+	lazyRows, err := LazyResultCodec.Decode(encodedRows, version)
+	require.NoError(t, err)
+	return []Message{
+		&Startup{Options: map[string]string{"CQL_VERSION": "3.0.0"}},
+		&Query{Query: "SELECT", Options: &QueryOptions{Consistency: primitive.ConsistencyLevelOne}},
+		&Query{Query: "SELECT", Options: &QueryOptions{
+			PositionalValues: []*primitive.Value{primitive.NewValue(convertInt1)},
+			PageSize:         100,
+			DefaultTimestamp: int64Ptr(123),
+			Keyspace:         "ks1",
+			NowInSeconds:     int32Ptr(456),
+		}},
+		&Query{Query: "SELECT", Options: &QueryOptions{NamedValues: map[string]*primitive.Value{"a": unset}}},
+		&Query{Query: "SELECT", Options: &QueryOptions{
+			PageSize:                100,
+			PageSizeInBytes:         true,
+			ContinuousPagingOptions: &ContinuousPagingOptions{MaxPages: 10, PagesPerSecond: 2, NextPages: 3},
+		}},
+		&Prepare{Query: "SELECT", Keyspace: "ks1"},
+		&Execute{QueryId: []byte{1}, Options: &QueryOptions{Keyspace: "ks1"}},
+		&Execute{QueryId: []byte{1}, ResultMetadataId: []byte{2}},
+		&Batch{
+			Children:          []*BatchChild{{Query: "INSERT", Values: []*primitive.Value{primitive.NewValue(convertInt1)}}, {Id: []byte{1}}},
+			Consistency:       primitive.ConsistencyLevelQuorum,
+			SerialConsistency: consistencyLevelPtr(primitive.ConsistencyLevelLocalSerial),
+			DefaultTimestamp:  int64Ptr(123),
+			Keyspace:          "ks1",
+			NowInSeconds:      int32Ptr(456),
+		},
+		&Batch{Children: []*BatchChild{{Query: "INSERT", Values: []*primitive.Value{unset}}}},
+		&Revise{RevisionType: primitive.DseRevisionTypeCancelContinuousPaging, TargetStreamId: 1},
+		&Revise{RevisionType: primitive.DseRevisionTypeMoreContinuousPages, TargetStreamId: 1, NextPages: 2},
+		&SchemaChangeEvent{ChangeType: primitive.SchemaChangeTypeCreated, Target: primitive.SchemaChangeTargetTable, Keyspace: "ks1", Object: "t1"},
+		&SchemaChangeEvent{ChangeType: primitive.SchemaChangeTypeCreated, Target: primitive.SchemaChangeTargetFunction, Keyspace: "ks1", Object: "f1", Arguments: []string{"int"}},
+		&SchemaChangeResult{ChangeType: primitive.SchemaChangeTypeDropped, Target: primitive.SchemaChangeTargetType, Keyspace: "ks1", Object: "udt1"},
+		&TopologyChangeEvent{ChangeType: primitive.TopologyChangeTypeMovedNode, Address: &primitive.Inet{Addr: net.IPv4(127, 0, 0, 1), Port: 9042}},
+		&PreparedResult{
+			PreparedQueryId: []byte{1},
+			VariablesMetadata: &VariablesMetadata{
+				PkIndices: []uint16{0},
+				Columns:   []*ColumnMetadata{{Keyspace: "ks1", Table: "t1", Name: "c1", Type: datatype.Date}},
+			},
+			ResultMetadata: &RowsMetadata{
+				ColumnCount: 2,
+				Columns: []*ColumnMetadata{
+					{Keyspace: "ks1", Table: "t1", Name: "c1", Type: datatype.NewList(datatype.Duration)},
+					{Keyspace: "ks1", Table: "t1", Name: "c2", Type: datatype.NewTuple(udt, datatype.Tinyint)},
+				},
+			},
+		},
+		&PreparedResult{PreparedQueryId: []byte{1}, ResultMetadataId: []byte{2}, ResultMetadata: &RowsMetadata{NewResultMetadataId: []byte{3}}},
+		rows,
+		&RowsResult{Metadata: &RowsMetadata{ColumnCount: 1, ContinuousPageNumber: 2, LastContinuousPage: true}},
+		lazyRows,
+		&WriteTimeout{ErrorMessage: "cas", WriteType: primitive.WriteTypeCas, Contentions: 2},
+		&ReadFailure{ErrorMessage: "read", Received: 1, BlockFor: 2, NumFailures: 1, DataPresent: true},
+		&ReadFailure{ErrorMessage: "read", Received: 1, BlockFor: 2, FailureReasons: []*primitive.FailureReason{{Endpoint: net.IPv4(127, 0, 0, 1), Code: primitive.FailureCodeTooManyTombstonesRead}}},
+		&WriteFailure{ErrorMessage: "write", WriteType: primitive.WriteTypeSimple, NumFailures: 2},
+		&FunctionFailure{ErrorMessage: "function", Keyspace: "ks1", Function: "f1", Arguments: []string{"int"}},
+	}
+}
+
+// convertEncodeDecode checks that the given message can be encoded and decoded with the given version.
+func convertEncodeDecode(t *testing.T, msg Message, version primitive.ProtocolVersion) {
+	var codec Encoder = LazyResultCodec
+	var decoder Decoder = LazyResultCodec
+	if _, lazy := msg.(*LazyRowsResult); !lazy {
+		for _, c := range DefaultMessageCodecs {
+			if c.GetOpCode() == msg.GetOpCode() {
+				codec, decoder = c, c
+			}
+		}
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, codec.Encode(msg, buf, version))
+	_, err := decoder.Decode(buf, version)
+	require.NoError(t, err)
+}
+
+func TestConvertVersion_Matrix(t *testing.T) {
+	for _, source := range primitive.SupportedProtocolVersions() {
+		for i, original := range convertCorpus(t, source) {
+			if Validate(original, source) != nil {
+				continue
+			}
+			for _, target := range primitive.SupportedProtocolVersions() {
+				t.Run(fmt.Sprintf("%v %v to %v", i, source, target), func(t *testing.T) {
+					converted, lost, err := ConvertVersion(original, source, target)
+					if err != nil {
+						assert.Nil(t, converted)
+						assert.Nil(t, lost)
+						return
+					}
+					require.NoError(t, Validate(converted, target))
+					convertEncodeDecode(t, converted, target)
+					back, _, err := ConvertVersion(converted, target, source)
+					require.NoError(t, err)
+					require.NoError(t, Validate(back, source))
+					if len(lost) == 0 {
+						assert.Equal(t, original, back)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestConvertVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      Message
+		source   primitive.ProtocolVersion
+		target   primitive.ProtocolVersion
+		expected Message
+		lost     []string
+	}{
+		{
+			"query v5 to v4",
+			&Query{Query: "SELECT", Options: &QueryOptions{Keyspace: "ks1", NowInSeconds: int32Ptr(1), DefaultTimestamp: int64Ptr(2)}},
+			primitive.ProtocolVersion5,
+			primitive.ProtocolVersion4,
+			&Query{Query: "SELECT", Options: &QueryOptions{DefaultTimestamp: int64Ptr(2)}},
+			[]string{"Options.Keyspace: dropped", "Options.NowInSeconds: dropped"},
+		},
+		{
+			"query values v3 to v2",
+			&Query{Query: "SELECT", Options: &QueryOptions{PositionalValues: []*primitive.Value{primitive.NewValue(convertInt1)}}},
+			primitive.ProtocolVersion3,
+			primitive.ProtocolVersion2,
+			&Query{Query: "SELECT", Options: &QueryOptions{PositionalValues: []*primitive.Value{primitive.NewValue(convertInt1)}}},
+			[]string{"Options.PositionalValues: collection values, if any, not converted"},
+		},
+		{
+			"query continuous paging DSE v2 to DSE v1",
+			&Query{Query: "SELECT", Options: &QueryOptions{ContinuousPagingOptions: &ContinuousPagingOptions{MaxPages: 1, NextPages: 2}}},
+			primitive.ProtocolVersionDse2,
+			primitive.ProtocolVersionDse1,
+			&Query{Query: "SELECT", Options: &QueryOptions{ContinuousPagingOptions: &ContinuousPagingOptions{MaxPages: 1}}},
+			[]string{"Options.ContinuousPagingOptions.NextPages: dropped"},
+		},
+		{
+			"execute v4 to v5",
+			&Execute{QueryId: []byte{1}},
+			primitive.ProtocolVersion4,
+			primitive.ProtocolVersion5,
+			&Execute{QueryId: []byte{1}, ResultMetadataId: []byte{1}},
+			nil,
+		},
+		{
+			"execute v5 to v4",
+			&Execute{QueryId: []byte{1}, ResultMetadataId: []byte{2}},
+			primitive.ProtocolVersion5,
+			primitive.ProtocolVersion4,
+			&Execute{QueryId: []byte{1}},
+			[]string{"ResultMetadataId: dropped"},
+		},
+		{
+			"prepared result v4 to v5",
+			&PreparedResult{PreparedQueryId: []byte{1}},
+			primitive.ProtocolVersion4,
+			primitive.ProtocolVersion5,
+			// MD5 of the encoded empty result metadata (flags 0x0004 and column count 0)
+			&PreparedResult{PreparedQueryId: []byte{1}, ResultMetadataId: []byte{
+				0x43, 0x29, 0x62, 0x4c, 0xe4, 0x27, 0x1d, 0xe8, 0x3f, 0xc7, 0xc4, 0x3f, 0xc9, 0xc7, 0xe1, 0x26,
+			}},
+			nil,
+		},
+		{
+			"prepared result v4 to v3",
+			&PreparedResult{
+				PreparedQueryId:   []byte{1},
+				VariablesMetadata: &VariablesMetadata{PkIndices: []uint16{0}, Columns: []*ColumnMetadata{{Name: "c1", Type: datatype.NewSet(datatype.Time)}}},
+			},
+			primitive.ProtocolVersion4,
+			primitive.ProtocolVersion3,
+			&PreparedResult{
+				PreparedQueryId: []byte{1},
+				VariablesMetadata: &VariablesMetadata{Columns: []*ColumnMetadata{{
+					Name: "c1",
+					Type: datatype.NewSet(datatype.NewCustom("org.apache.cassandra.db.marshal.TimeType")),
+				}}},
+			},
+			[]string{"VariablesMetadata.PkIndices: dropped"},
+		},
+		{
+			"rows result v3 to v2",
+			&RowsResult{Metadata: convertRowsMetadata(), Data: convertRowsData(primitive.ProtocolVersion3)},
+			primitive.ProtocolVersion3,
+			primitive.ProtocolVersion2,
+			&RowsResult{Metadata: convertRowsMetadata(), Data: convertRowsData(primitive.ProtocolVersion2)},
+			nil,
+		},
+		{
+			"rows result without metadata v2 to v3",
+			&RowsResult{Metadata: &RowsMetadata{ColumnCount: 1}, Data: RowSet{{convertInt1}}},
+			primitive.ProtocolVersion2,
+			primitive.ProtocolVersion3,
+			&RowsResult{Metadata: &RowsMetadata{ColumnCount: 1}, Data: RowSet{{convertInt1}}},
+			[]string{"Data: collection values, if any, not converted: no column metadata"},
+		},
+		{
+			"read failure v5 to v4",
+			&ReadFailure{FailureReasons: []*primitive.FailureReason{{Endpoint: net.IPv4(127, 0, 0, 1), Code: primitive.FailureCodeUnknown}}},
+			primitive.ProtocolVersion5,
+			primitive.ProtocolVersion4,
+			&ReadFailure{NumFailures: 1},
+			[]string{"FailureReasons: converted to NumFailures"},
+		},
+		{
+			"write failure v4 to v5",
+			&WriteFailure{NumFailures: 1, WriteType: primitive.WriteTypeSimple},
+			primitive.ProtocolVersion4,
+			primitive.ProtocolVersion5,
+			&WriteFailure{FailureReasons: []*primitive.FailureReason{{Endpoint: net.IPv4zero.To4(), Code: primitive.FailureCodeUnknown}}, WriteType: primitive.WriteTypeSimple},
+			[]string{"NumFailures: converted to FailureReasons with unknown endpoints"},
+		},
+		{
+			"write failure v4 to v3",
+			&WriteFailure{ErrorMessage: "write", Received: 1, BlockFor: 2, NumFailures: 1, WriteType: primitive.WriteTypeSimple},
+			primitive.ProtocolVersion4,
+			primitive.ProtocolVersion3,
+			&WriteTimeout{ErrorMessage: "write", Received: 1, BlockFor: 2, WriteType: primitive.WriteTypeSimple},
+			[]string{"WRITE FAILURE converted to WRITE TIMEOUT"},
+		},
+		{
+			"schema change v4 to v2",
+			&SchemaChangeEvent{ChangeType: primitive.SchemaChangeTypeDropped, Target: primitive.SchemaChangeTargetAggregate, Keyspace: "ks1", Object: "a1", Arguments: []string{"int"}},
+			primitive.ProtocolVersion4,
+			primitive.ProtocolVersion2,
+			&SchemaChangeEvent{ChangeType: primitive.SchemaChangeTypeUpdated, Target: primitive.SchemaChangeTargetKeyspace, Keyspace: "ks1"},
+			[]string{"Target: AGGREGATE DROPPED converted to KEYSPACE UPDATED"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, lost, err := ConvertVersion(tt.msg, tt.source, tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, converted)
+			var lostStrings []string
+			for _, violation := range lost {
+				lostStrings = append(lostStrings, violation.String())
+			}
+			assert.Equal(t, tt.lost, lostStrings)
+		})
+	}
+}
+
+func TestConvertVersion_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    Message
+		source primitive.ProtocolVersion
+		target primitive.ProtocolVersion
+		err    string
+	}{
+		{
+			"unsupported version",
+			&Startup{},
+			primitive.ProtocolVersion4,
+			primitive.ProtocolVersion(1),
+			"invalid protocol version: ProtocolVersion ? [0X01]",
+		},
+		{
+			"unset values",
+			&Query{Query: "SELECT", Options: &QueryOptions{PositionalValues: []*primitive.Value{{Type: primitive.ValueTypeUnset}}}},
+			primitive.ProtocolVersion4,
+			primitive.ProtocolVersion3,
+			"cannot convert OpCode QUERY [0x07] message from ProtocolVersion OSS 4 to ProtocolVersion OSS 3: " +
+				"Options.PositionalValues[0]: unset values not supported",
+		},
+		{
+			"revise",
+			&Revise{RevisionType: primitive.DseRevisionTypeCancelContinuousPaging},
+			primitive.ProtocolVersionDse1,
+			primitive.ProtocolVersion4,
+			"cannot convert OpCode REVISE [0xFF] message from ProtocolVersion DSE 1 to ProtocolVersion OSS 4: " +
+				"DSE protocol version required",
+		},
+		{
+			"collection too large",
+			&RowsResult{
+				Metadata: &RowsMetadata{ColumnCount: 1, Columns: []*ColumnMetadata{{Name: "c1", Type: datatype.NewList(datatype.Int)}}},
+				Data:     RowSet{{encodeCollection(primitive.ProtocolVersion3, 1<<16)}},
+			},
+			primitive.ProtocolVersion3,
+			primitive.ProtocolVersion2,
+			"cannot convert OpCode RESULT [0x08] message from ProtocolVersion OSS 3 to ProtocolVersion OSS 2: " +
+				"Data[0][0]: collection size too large: 65536",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, lost, err := ConvertVersion(tt.msg, tt.source, tt.target)
+			assert.EqualError(t, err, tt.err)
+			assert.Nil(t, converted)
+			assert.Nil(t, lost)
+		})
+	}
+}
@@ -675,6 +675,35 @@ func (in *QueryOptions) DeepCopy() *QueryOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RawMessage) DeepCopyInto(out *RawMessage) {
+	*out = *in
+	if in.Body != nil {
+		in, out := &in.Body, &out.Body
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RawMessage.
+func (in *RawMessage) DeepCopy() *RawMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(RawMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyMessage is an autogenerated deepcopy function, copying the receiver, creating a new Message.
+func (in *RawMessage) DeepCopyMessage() Message {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReadFailure) DeepCopyInto(out *ReadFailure) {
 	*out = *in
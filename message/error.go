@@ -15,18 +15,41 @@
 package message
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
 	"github.com/datastax/go-cassandra-native-protocol/primitive"
 )
 
+// Error is implemented by all ERROR response messages. The error messages of this package also implement the Go error
+// interface: errors.As can be used to extract a specific error message from an error chain, e.g. a *ReadTimeout, and
+// errors.Is reports whether an error chain contains an error message with the same error code as the target, regardless
+// of its other fields:
+//
+//	if errors.Is(err, &message.Unavailable{}) {
+//	  ...
+//	}
 type Error interface {
 	Message
 	GetErrorCode() primitive.ErrorCode
 	GetErrorMessage() string
 }
 
+// GetErrorCode returns the error code of the first Error found in the given error chain, if any.
+func GetErrorCode(err error) (code primitive.ErrorCode, ok bool) {
+	var e Error
+	if errors.As(err, &e) {
+		return e.GetErrorCode(), true
+	}
+	return 0, false
+}
+
+func isErrorCode(target error, code primitive.ErrorCode) bool {
+	e, ok := target.(Error)
+	return ok && e.GetErrorCode() == code
+}
+
 // SERVER ERROR
 
 // ServerError is a server error response.
@@ -52,6 +75,14 @@ func (m *ServerError) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *ServerError) Error() string {
+	return m.String()
+}
+
+func (m *ServerError) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *ServerError) String() string {
 	return fmt.Sprintf("ERROR SERVER ERROR (code=%v, msg=%v)", m.GetErrorCode(), m.ErrorMessage)
 }
@@ -81,6 +112,14 @@ func (m *ProtocolError) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *ProtocolError) Error() string {
+	return m.String()
+}
+
+func (m *ProtocolError) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *ProtocolError) String() string {
 	return fmt.Sprintf("ERROR PROTOCOL ERROR (code=%v, msg=%v)", m.GetErrorCode(), m.ErrorMessage)
 }
@@ -110,6 +149,14 @@ func (m *AuthenticationError) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *AuthenticationError) Error() string {
+	return m.String()
+}
+
+func (m *AuthenticationError) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *AuthenticationError) String() string {
 	return fmt.Sprintf("ERROR AUTHENTICATION ERROR (code=%v, msg=%v)", m.GetErrorCode(), m.ErrorMessage)
 }
@@ -139,6 +186,14 @@ func (m *Overloaded) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *Overloaded) Error() string {
+	return m.String()
+}
+
+func (m *Overloaded) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *Overloaded) String() string {
 	return fmt.Sprintf("ERROR OVERLOADED (code=%v, msg=%v)", m.GetErrorCode(), m.ErrorMessage)
 }
@@ -168,6 +223,14 @@ func (m *IsBootstrapping) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *IsBootstrapping) Error() string {
+	return m.String()
+}
+
+func (m *IsBootstrapping) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *IsBootstrapping) String() string {
 	return fmt.Sprintf("ERROR IS BOOTSTRAPPING (code=%v, msg=%v)", m.GetErrorCode(), m.ErrorMessage)
 }
@@ -197,6 +260,14 @@ func (m *TruncateError) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *TruncateError) Error() string {
+	return m.String()
+}
+
+func (m *TruncateError) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *TruncateError) String() string {
 	return fmt.Sprintf("ERROR TRUNCATE ERROR (code=%v, msg=%v)", m.GetErrorCode(), m.ErrorMessage)
 }
@@ -226,6 +297,14 @@ func (m *SyntaxError) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *SyntaxError) Error() string {
+	return m.String()
+}
+
+func (m *SyntaxError) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *SyntaxError) String() string {
 	return fmt.Sprintf("ERROR SYNTAX ERROR (code=%v, msg=%v)", m.GetErrorCode(), m.ErrorMessage)
 }
@@ -255,6 +334,14 @@ func (m *Unauthorized) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *Unauthorized) Error() string {
+	return m.String()
+}
+
+func (m *Unauthorized) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *Unauthorized) String() string {
 	return fmt.Sprintf("ERROR UNAUTHORIZED (code=%v, msg=%v)", m.GetErrorCode(), m.ErrorMessage)
 }
@@ -284,6 +371,14 @@ func (m *Invalid) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *Invalid) Error() string {
+	return m.String()
+}
+
+func (m *Invalid) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *Invalid) String() string {
 	return fmt.Sprintf("ERROR INVALID (code=%v, msg=%v)", m.GetErrorCode(), m.ErrorMessage)
 }
@@ -313,6 +408,14 @@ func (m *ConfigError) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *ConfigError) Error() string {
+	return m.String()
+}
+
+func (m *ConfigError) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *ConfigError) String() string {
 	return fmt.Sprintf("ERROR CONFIG ERROR (code=%v, msg=%v)", m.GetErrorCode(), m.ErrorMessage)
 }
@@ -349,6 +452,14 @@ func (m *Unavailable) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *Unavailable) Error() string {
+	return m.String()
+}
+
+func (m *Unavailable) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *Unavailable) String() string {
 	return fmt.Sprintf(
 		"ERROR UNAVAILABLE (code=%v, msg=%v, cl=%v, required=%v, alive=%v)",
@@ -397,6 +508,14 @@ func (m *ReadTimeout) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *ReadTimeout) Error() string {
+	return m.String()
+}
+
+func (m *ReadTimeout) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *ReadTimeout) String() string {
 	return fmt.Sprintf(
 		"ERROR READ TIMEOUT (code=%v, msg=%v, cl=%v, received=%v, blockfor=%v, data=%v)",
@@ -445,6 +564,14 @@ func (m *WriteTimeout) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *WriteTimeout) Error() string {
+	return m.String()
+}
+
+func (m *WriteTimeout) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *WriteTimeout) String() string {
 	return fmt.Sprintf(
 		"ERROR WRITE TIMEOUT (code=%v, msg=%v, cl=%v, received=%v, blockfor=%v, type=%v, contentions=%v)",
@@ -497,6 +624,14 @@ func (m *ReadFailure) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *ReadFailure) Error() string {
+	return m.String()
+}
+
+func (m *ReadFailure) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *ReadFailure) String() string {
 	return fmt.Sprintf(
 		"ERROR READ FAILURE (code=%v, msg=%v, cl=%v, received=%v, blockfor=%v, data=%v)",
@@ -548,6 +683,14 @@ func (m *WriteFailure) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *WriteFailure) Error() string {
+	return m.String()
+}
+
+func (m *WriteFailure) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *WriteFailure) String() string {
 	return fmt.Sprintf(
 		"ERROR WRITE FAILURE (code=%v, msg=%v, cl=%v, received=%v, blockfor=%v, type=%v)",
@@ -589,6 +732,14 @@ func (m *FunctionFailure) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *FunctionFailure) Error() string {
+	return m.String()
+}
+
+func (m *FunctionFailure) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *FunctionFailure) String() string {
 	return fmt.Sprintf(
 		"ERROR FUNCTION FAILURE (code=%v, msg=%v, ks=%v, function=%v, args=%v)",
@@ -626,6 +777,14 @@ func (m *Unprepared) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *Unprepared) Error() string {
+	return m.String()
+}
+
+func (m *Unprepared) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *Unprepared) String() string {
 	return fmt.Sprintf(
 		"ERROR UNPREPARED (code=%v, msg=%v, id=%v)",
@@ -662,6 +821,14 @@ func (m *AlreadyExists) GetErrorMessage() string {
 	return m.ErrorMessage
 }
 
+func (m *AlreadyExists) Error() string {
+	return m.String()
+}
+
+func (m *AlreadyExists) Is(target error) bool {
+	return isErrorCode(target, m.GetErrorCode())
+}
+
 func (m *AlreadyExists) String() string {
 	return fmt.Sprintf(
 		"ERROR ALREADY EXISTS (code=%v, msg=%v, ks=%v, table=%v)",
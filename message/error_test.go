@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/datastax/go-cassandra-native-protocol/primitive"
 )
@@ -1379,3 +1380,45 @@ func TestErrorCodec_Decode(test *testing.T) {
 		}
 	})
 }
+
+func TestError_IsAs(t *testing.T) {
+	var err error = fmt.Errorf("query failed: %w", &ReadTimeout{ErrorMessage: "timeout", Received: 1, BlockFor: 2})
+	var readTimeout *ReadTimeout
+	assert.True(t, errors.As(err, &readTimeout))
+	assert.Equal(t, int32(2), readTimeout.BlockFor)
+	var e Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, primitive.ErrorCodeReadTimeout, e.GetErrorCode())
+	var writeTimeout *WriteTimeout
+	assert.False(t, errors.As(err, &writeTimeout))
+	assert.True(t, errors.Is(err, &ReadTimeout{}))
+	assert.False(t, errors.Is(err, &WriteTimeout{}))
+	assert.False(t, errors.Is(err, errors.New("timeout")))
+	assert.EqualError(t, err, "query failed: "+readTimeout.String())
+}
+
+func TestError_AllErrors(t *testing.T) {
+	msgs := []Error{
+		&ServerError{}, &ProtocolError{}, &AuthenticationError{}, &Overloaded{}, &IsBootstrapping{}, &TruncateError{},
+		&SyntaxError{}, &Unauthorized{}, &Invalid{}, &ConfigError{}, &Unavailable{}, &ReadTimeout{}, &WriteTimeout{},
+		&ReadFailure{}, &WriteFailure{}, &FunctionFailure{}, &Unprepared{}, &AlreadyExists{},
+	}
+	for _, msg := range msgs {
+		err, ok := msg.(error)
+		require.True(t, ok, "%v", msg)
+		for _, target := range msgs {
+			assert.Equal(t, msg.GetErrorCode() == target.GetErrorCode(), errors.Is(err, target.(error)), "%v %v", msg, target)
+		}
+		assert.Equal(t, msg.(fmt.Stringer).String(), err.Error())
+	}
+}
+
+func TestGetErrorCode(t *testing.T) {
+	code, ok := GetErrorCode(fmt.Errorf("wrapped: %w", &Unprepared{Id: []byte{1}}))
+	assert.True(t, ok)
+	assert.Equal(t, primitive.ErrorCodeUnprepared, code)
+	_, ok = GetErrorCode(errors.New("not a protocol error"))
+	assert.False(t, ok)
+	_, ok = GetErrorCode(nil)
+	assert.False(t, ok)
+}
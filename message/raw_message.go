@@ -0,0 +1,89 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// RawMessage is an opaque message whose body is kept in its encoded form. Frame codecs decode messages into
+// RawMessage when no codec is registered for their opcode, protocol version and direction, see CodecRegistry; raw
+// messages can also be encoded, which allows proxies to forward messages they do not understand.
+// +k8s:deepcopy-gen=true
+// +k8s:deepcopy-gen:interfaces=github.com/datastax/go-cassandra-native-protocol/message.Message
+type RawMessage struct {
+	OpCode primitive.OpCode
+	// Whether this message is a response (server to client) or a request (client to server).
+	Response bool
+	// The encoded message, excluding the frame header and the frame body tracing id, custom payload and warnings.
+	Body []byte
+}
+
+func (m *RawMessage) IsResponse() bool {
+	return m.Response
+}
+
+func (m *RawMessage) GetOpCode() primitive.OpCode {
+	return m.OpCode
+}
+
+func (m *RawMessage) String() string {
+	return fmt.Sprintf("RAW %v (%d bytes)", m.OpCode, len(m.Body))
+}
+
+type rawMessageCodec struct {
+	opCode   primitive.OpCode
+	response bool
+}
+
+// NewRawMessageCodec returns a codec for RawMessage. Its Decode method reads the whole source, and returns a RawMessage
+// with the given opcode and direction.
+func NewRawMessageCodec(opCode primitive.OpCode, isResponse bool) Codec {
+	return &rawMessageCodec{opCode: opCode, response: isResponse}
+}
+
+func (c *rawMessageCodec) Encode(msg Message, dest io.Writer, _ primitive.ProtocolVersion) error {
+	raw, ok := msg.(*RawMessage)
+	if !ok {
+		return errors.New(fmt.Sprintf("expected *message.RawMessage, got %T", msg))
+	}
+	_, err := dest.Write(raw.Body)
+	return err
+}
+
+func (c *rawMessageCodec) EncodedLength(msg Message, _ primitive.ProtocolVersion) (int, error) {
+	raw, ok := msg.(*RawMessage)
+	if !ok {
+		return -1, errors.New(fmt.Sprintf("expected *message.RawMessage, got %T", msg))
+	}
+	return len(raw.Body), nil
+}
+
+func (c *rawMessageCodec) Decode(source io.Reader, _ primitive.ProtocolVersion) (Message, error) {
+	if body, err := ioutil.ReadAll(source); err != nil {
+		return nil, fmt.Errorf("cannot read raw message body: %w", err)
+	} else {
+		return &RawMessage{OpCode: c.opCode, Response: c.response, Body: body}, nil
+	}
+}
+
+func (c *rawMessageCodec) GetOpCode() primitive.OpCode {
+	return c.opCode
+}
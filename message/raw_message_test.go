@@ -0,0 +1,61 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+func TestRawMessage_DeepCopy(t *testing.T) {
+	msg := &RawMessage{OpCode: primitive.OpCode(0x42), Response: true, Body: []byte{1, 2}}
+	cloned := msg.DeepCopy()
+	assert.Equal(t, msg, cloned)
+	cloned.Body[0] = 3
+	assert.NotEqual(t, msg, cloned)
+	assert.Equal(t, []byte{1, 2}, msg.Body)
+	assert.Equal(t, "RAW OpCode ? [0X42] (2 bytes)", msg.String())
+}
+
+func TestRawMessageCodec(t *testing.T) {
+	codec := NewRawMessageCodec(primitive.OpCode(0x42), true)
+	assert.Equal(t, primitive.OpCode(0x42), codec.GetOpCode())
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			msg := &RawMessage{OpCode: primitive.OpCode(0x42), Response: true, Body: []byte{1, 2, 3}}
+			length, err := codec.EncodedLength(msg, version)
+			require.NoError(t, err)
+			assert.Equal(t, 3, length)
+			dest := &bytes.Buffer{}
+			require.NoError(t, codec.Encode(msg, dest, version))
+			assert.Equal(t, []byte{1, 2, 3}, dest.Bytes())
+			decoded, err := codec.Decode(dest, version)
+			require.NoError(t, err)
+			assert.Equal(t, msg, decoded)
+			decoded, err = codec.Decode(primitive.NewCursor(nil), version)
+			require.NoError(t, err)
+			assert.Equal(t, &RawMessage{OpCode: primitive.OpCode(0x42), Response: true, Body: []byte{}}, decoded)
+			assert.Equal(t, errors.New("expected *message.RawMessage, got *message.Ready"), codec.Encode(&Ready{}, dest, version))
+			_, err = codec.EncodedLength(&Ready{}, version)
+			assert.Equal(t, errors.New("expected *message.RawMessage, got *message.Ready"), err)
+		})
+	}
+}
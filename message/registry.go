@@ -0,0 +1,136 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// Direction restricts a codec registration to requests, responses, or both.
+type Direction uint8
+
+const (
+	DirectionAny      = Direction(0)
+	DirectionRequest  = Direction(1)
+	DirectionResponse = Direction(2)
+)
+
+func (d Direction) matches(isResponse bool) bool {
+	switch d {
+	case DirectionRequest:
+		return !isResponse
+	case DirectionResponse:
+		return isResponse
+	}
+	return true
+}
+
+func (d Direction) String() string {
+	switch d {
+	case DirectionAny:
+		return "Direction Any"
+	case DirectionRequest:
+		return "Direction Request"
+	case DirectionResponse:
+		return "Direction Response"
+	}
+	return fmt.Sprintf("Direction ? [%d]", uint8(d))
+}
+
+const (
+	// MinProtocolVersion and MaxProtocolVersion can be used to register codecs for all protocol versions, including
+	// versions unknown to this library.
+	MinProtocolVersion = primitive.ProtocolVersion(0)
+	MaxProtocolVersion = primitive.ProtocolVersion(math.MaxUint8)
+)
+
+// CodecRegistry holds message codecs keyed by opcode, protocol version range and direction. This allows vendor
+// extensions to use an opcode differently depending on the protocol version, e.g. the DSE Revise request uses the
+// opcode 0xFF, which is only valid with DSE protocol versions.
+//
+// When several registered codecs match a given opcode, version and direction, the most recently registered one wins;
+// this allows default codecs to be overridden. A registry is not safe for concurrent registrations, but is safe for
+// concurrent lookups.
+type CodecRegistry struct {
+	registrations map[primitive.OpCode][]*codecRegistration
+}
+
+type codecRegistration struct {
+	codec      Codec
+	minVersion primitive.ProtocolVersion
+	maxVersion primitive.ProtocolVersion
+	direction  Direction
+}
+
+// NewCodecRegistry returns a new, empty registry.
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{registrations: map[primitive.OpCode][]*codecRegistration{}}
+}
+
+// NewDefaultCodecRegistry returns a new registry containing DefaultMessageCodecs. All default codecs are registered
+// for all protocol versions and directions, except the DSE Revise codec, which is only registered for requests with
+// DSE protocol versions.
+func NewDefaultCodecRegistry() *CodecRegistry {
+	registry := NewCodecRegistry()
+	for _, codec := range DefaultMessageCodecs {
+		if codec.GetOpCode() == primitive.OpCodeDseRevise {
+			registry.RegisterForVersions(codec, primitive.ProtocolVersionDse1, primitive.ProtocolVersionDse2, DirectionRequest)
+		} else {
+			registry.Register(codec)
+		}
+	}
+	return registry
+}
+
+// Register registers the given codec for its opcode, for all protocol versions and directions.
+func (r *CodecRegistry) Register(codec Codec) {
+	r.RegisterForVersions(codec, MinProtocolVersion, MaxProtocolVersion, DirectionAny)
+}
+
+// RegisterForVersions registers the given codec for its opcode, for protocol versions between minVersion and
+// maxVersion inclusive, and for the given direction.
+func (r *CodecRegistry) RegisterForVersions(codec Codec, minVersion, maxVersion primitive.ProtocolVersion, direction Direction) {
+	opCode := codec.GetOpCode()
+	r.registrations[opCode] = append(r.registrations[opCode], &codecRegistration{
+		codec:      codec,
+		minVersion: minVersion,
+		maxVersion: maxVersion,
+		direction:  direction,
+	})
+}
+
+// Find returns the codec registered for the given opcode, protocol version and direction, or nil if there is none.
+func (r *CodecRegistry) Find(opCode primitive.OpCode, version primitive.ProtocolVersion, isResponse bool) Codec {
+	registrations := r.registrations[opCode]
+	for i := len(registrations) - 1; i >= 0; i-- {
+		registration := registrations[i]
+		if version >= registration.minVersion && version <= registration.maxVersion && registration.direction.matches(isResponse) {
+			return registration.codec
+		}
+	}
+	return nil
+}
+
+// FindEncoder returns the codec to use to encode the given message with the given protocol version, or nil if there is
+// none. RawMessage instances are always encoded as is, regardless of the registered codecs.
+func (r *CodecRegistry) FindEncoder(msg Message, version primitive.ProtocolVersion) Codec {
+	if raw, ok := msg.(*RawMessage); ok {
+		return NewRawMessageCodec(raw.OpCode, raw.Response)
+	}
+	return r.Find(msg.GetOpCode(), version, msg.IsResponse())
+}
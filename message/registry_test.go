@@ -0,0 +1,79 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+func TestNewDefaultCodecRegistry(t *testing.T) {
+	registry := NewDefaultCodecRegistry()
+	for _, version := range primitive.SupportedProtocolVersions() {
+		for _, codec := range DefaultMessageCodecs {
+			for _, isResponse := range []bool{false, true} {
+				expected := codec
+				if codec.GetOpCode() == primitive.OpCodeDseRevise && (!version.IsDse() || isResponse) {
+					expected = nil
+				}
+				assert.Equal(t, expected, registry.Find(codec.GetOpCode(), version, isResponse), "%v %v %v", codec.GetOpCode(), version, isResponse)
+			}
+		}
+	}
+	assert.Nil(t, registry.Find(primitive.OpCode(0x42), primitive.ProtocolVersion4, false))
+}
+
+func TestCodecRegistry_Find(t *testing.T) {
+	vendorRequest := NewRawMessageCodec(primitive.OpCode(0x42), false)
+	vendorResponse := NewRawMessageCodec(primitive.OpCode(0x42), true)
+	registry := NewDefaultCodecRegistry()
+	registry.RegisterForVersions(vendorRequest, primitive.ProtocolVersion4, primitive.ProtocolVersion5, DirectionRequest)
+	registry.RegisterForVersions(vendorResponse, primitive.ProtocolVersion5, MaxProtocolVersion, DirectionResponse)
+	registry.RegisterForVersions(LazyResultCodec, primitive.ProtocolVersion5, primitive.ProtocolVersion5, DirectionResponse)
+	tests := []struct {
+		name       string
+		opCode     primitive.OpCode
+		version    primitive.ProtocolVersion
+		isResponse bool
+		expected   Codec
+	}{
+		{"vendor request v3", primitive.OpCode(0x42), primitive.ProtocolVersion3, false, nil},
+		{"vendor request v4", primitive.OpCode(0x42), primitive.ProtocolVersion4, false, vendorRequest},
+		{"vendor request v5", primitive.OpCode(0x42), primitive.ProtocolVersion5, false, vendorRequest},
+		{"vendor request DSE v1", primitive.OpCode(0x42), primitive.ProtocolVersionDse1, false, nil},
+		{"vendor response v4", primitive.OpCode(0x42), primitive.ProtocolVersion4, true, nil},
+		{"vendor response v5", primitive.OpCode(0x42), primitive.ProtocolVersion5, true, vendorResponse},
+		{"vendor response DSE v2", primitive.OpCode(0x42), primitive.ProtocolVersionDse2, true, vendorResponse},
+		{"result v4", primitive.OpCodeResult, primitive.ProtocolVersion4, true, &resultCodec{}},
+		{"result v5 overridden", primitive.OpCodeResult, primitive.ProtocolVersion5, true, LazyResultCodec},
+		{"result v5 request", primitive.OpCodeResult, primitive.ProtocolVersion5, false, &resultCodec{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, registry.Find(tt.opCode, tt.version, tt.isResponse))
+		})
+	}
+}
+
+func TestCodecRegistry_FindEncoder(t *testing.T) {
+	registry := NewDefaultCodecRegistry()
+	assert.Equal(t, &queryCodec{}, registry.FindEncoder(&Query{}, primitive.ProtocolVersion4))
+	assert.Equal(t, NewRawMessageCodec(primitive.OpCodeQuery, false), registry.FindEncoder(&RawMessage{OpCode: primitive.OpCodeQuery}, primitive.ProtocolVersion4))
+	assert.Nil(t, registry.FindEncoder(&Revise{}, primitive.ProtocolVersion4))
+	assert.Nil(t, NewCodecRegistry().FindEncoder(&Query{}, primitive.ProtocolVersion4))
+}
@@ -416,7 +416,12 @@ func (c *resultCodec) Decode(source io.Reader, version primitive.ProtocolVersion
 	if resultType, err = primitive.ReadInt(source); err != nil {
 		return nil, fmt.Errorf("cannot read RESULT type: %w", err)
 	}
-	switch primitive.ResultType(resultType) {
+	return c.decodeResult(primitive.ResultType(resultType), source, version)
+}
+
+// decodeResult decodes the contents of a RESULT message of the given type, that is, everything after the result type.
+func (c *resultCodec) decodeResult(resultType primitive.ResultType, source io.Reader, version primitive.ProtocolVersion) (msg Message, err error) {
+	switch resultType {
 	case primitive.ResultTypeVoid:
 		return &VoidResult{}, nil
 	case primitive.ResultTypeSetKeyspace:
@@ -0,0 +1,303 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// LAZY ROWS
+
+// LazyRowsResult is an alternative representation of a RESULT Rows response message, as decoded by
+// LazyResultCodec. Its metadata is decoded eagerly, but its rows are kept in their encoded form and are only decoded
+// when iterated over with Rows; the decoded cells are sub-slices of the encoded rows, which are themselves, whenever
+// possible, a sub-slice of the frame body. This avoids allocating one []byte per cell, and allows large pages to be
+// streamed and filtered without materializing the whole RowSet.
+//
+// Since cells share their memory with the frame body, they must be copied if they are to be retained after the
+// frame body buffer is reused, or modified.
+type LazyRowsResult struct {
+	Metadata  *RowsMetadata
+	rowsCount int
+	// data contains the encoded rows, that is, a sequence of rowsCount * Metadata.ColumnCount [bytes].
+	data []byte
+}
+
+func (m *LazyRowsResult) IsResponse() bool {
+	return true
+}
+
+func (m *LazyRowsResult) GetOpCode() primitive.OpCode {
+	return primitive.OpCodeResult
+}
+
+func (m *LazyRowsResult) GetResultType() primitive.ResultType {
+	return primitive.ResultTypeRows
+}
+
+func (m *LazyRowsResult) String() string {
+	return fmt.Sprintf("RESULT ROWS (%v rows x %v cols)", m.rowsCount, m.Metadata.ColumnCount)
+}
+
+// DeepCopy returns a deep copy of this message; the encoded rows are copied as well, and thus the copy does not share
+// any memory with the frame body anymore.
+func (m *LazyRowsResult) DeepCopy() *LazyRowsResult {
+	if m == nil {
+		return nil
+	}
+	out := &LazyRowsResult{
+		Metadata:  m.Metadata.DeepCopy(),
+		rowsCount: m.rowsCount,
+	}
+	if m.data != nil {
+		out.data = make([]byte, len(m.data))
+		copy(out.data, m.data)
+	}
+	return out
+}
+
+func (m *LazyRowsResult) DeepCopyMessage() Message {
+	return m.DeepCopy()
+}
+
+// RowsCount returns the number of rows in this result.
+func (m *LazyRowsResult) RowsCount() int {
+	return m.rowsCount
+}
+
+// Rows returns a new iterator over the rows of this result.
+func (m *LazyRowsResult) Rows() *RowIterator {
+	return &RowIterator{
+		data:        m.data,
+		remaining:   m.rowsCount,
+		columnCount: int(m.Metadata.ColumnCount),
+	}
+}
+
+// ToRowsResult materializes all the rows of this result and returns an equivalent RowsResult. The cells of the
+// returned RowsResult share their memory with this result.
+func (m *LazyRowsResult) ToRowsResult() (*RowsResult, error) {
+	rows := &RowsResult{Metadata: m.Metadata, Data: make(RowSet, 0, m.rowsCount)}
+	it := m.Rows()
+	for it.Next() {
+		row := make(Row, len(it.Row()))
+		copy(row, it.Row())
+		rows.Data = append(rows.Data, row)
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return rows, nil
+}
+
+// RowIterator iterates over the rows of a LazyRowsResult. A typical iteration is as follows:
+//
+//  it := result.Rows()
+//  for it.Next() {
+//    row := it.Row()
+//    ...
+//  }
+//  if it.Err() != nil {
+//    ...
+//  }
+type RowIterator struct {
+	data        []byte
+	pos         int
+	remaining   int
+	columnCount int
+	index       int
+	row         Row
+	err         error
+}
+
+// Next advances the iterator to the next row, and returns true if there was one; it returns false when all the rows
+// were read, or if an error occurred, in which case Err returns that error.
+func (it *RowIterator) Next() bool {
+	if it.err != nil || it.remaining <= 0 {
+		return false
+	}
+	if it.row == nil {
+		it.row = make(Row, it.columnCount)
+	} else {
+		it.index++
+	}
+	for j := 0; j < it.columnCount; j++ {
+		var cell Column
+		if cell, it.pos, it.err = sliceBytes(it.data, it.pos); it.err != nil {
+			it.err = fmt.Errorf("cannot read RESULT Rows data row %d col %d: %w", it.index, j, it.err)
+			return false
+		}
+		it.row[j] = cell
+	}
+	it.remaining--
+	return true
+}
+
+// Row returns the current row. The returned Row is reused by subsequent calls to Next, and thus must be copied if it
+// is to be retained; its cells, however, are not.
+func (it *RowIterator) Row() Row {
+	return it.row
+}
+
+// Index returns the zero-based index of the current row.
+func (it *RowIterator) Index() int {
+	return it.index
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+// CODEC
+
+// LazyResultCodec is an alternative codec for RESULT messages that decodes Rows results as LazyRowsResult instead of
+// RowsResult; other results are decoded as usual. It can be passed to frame codec constructors to replace the default
+// RESULT codec, e.g. frame.NewRawCodec(message.LazyResultCodec). It encodes both RowsResult and LazyRowsResult.
+//
+// When decoding from a *primitive.Cursor or a *bytes.Buffer, e.g. when decoding a raw frame, a compressed frame body
+// or a frame decoded with frame.BytesDecoder, the rows are not copied: cells are sub-slices of the buffer contents.
+// When decoding from any other io.Reader, all the rows are copied into a single buffer, which still saves one
+// allocation per cell.
+var LazyResultCodec Codec = &lazyResultCodec{}
+
+type lazyResultCodec struct {
+	resultCodec
+}
+
+func (c *lazyResultCodec) Encode(msg Message, dest io.Writer, version primitive.ProtocolVersion) (err error) {
+	rows, ok := msg.(*LazyRowsResult)
+	if !ok {
+		return c.resultCodec.Encode(msg, dest, version)
+	}
+	if err = primitive.WriteInt(int32(primitive.ResultTypeRows), dest); err != nil {
+		return fmt.Errorf("cannot write RESULT type: %w", err)
+	} else if err = encodeRowsMetadata(rows.Metadata, dest, version); err != nil {
+		return fmt.Errorf("cannot write RESULT Rows metadata: %w", err)
+	} else if err = primitive.WriteInt(int32(rows.rowsCount), dest); err != nil {
+		return fmt.Errorf("cannot write RESULT Rows data length: %w", err)
+	} else if _, err = dest.Write(rows.data); err != nil {
+		return fmt.Errorf("cannot write RESULT Rows data: %w", err)
+	}
+	return nil
+}
+
+func (c *lazyResultCodec) EncodedLength(msg Message, version primitive.ProtocolVersion) (length int, err error) {
+	rows, ok := msg.(*LazyRowsResult)
+	if !ok {
+		return c.resultCodec.EncodedLength(msg, version)
+	}
+	if rows.Metadata == nil {
+		return -1, errors.New("cannot compute length of nil RESULT Rows metadata")
+	}
+	if length, err = lengthOfRowsMetadata(rows.Metadata, version); err != nil {
+		return -1, fmt.Errorf("cannot compute length of RESULT Rows metadata: %w", err)
+	}
+	return primitive.LengthOfInt + length + primitive.LengthOfInt + len(rows.data), nil
+}
+
+func (c *lazyResultCodec) Decode(source io.Reader, version primitive.ProtocolVersion) (msg Message, err error) {
+	var resultType int32
+	if resultType, err = primitive.ReadInt(source); err != nil {
+		return nil, fmt.Errorf("cannot read RESULT type: %w", err)
+	}
+	if primitive.ResultType(resultType) != primitive.ResultTypeRows {
+		return c.decodeResult(primitive.ResultType(resultType), source, version)
+	}
+	rows := &LazyRowsResult{}
+	if rows.Metadata, err = decodeRowsMetadata(source, version); err != nil {
+		return nil, fmt.Errorf("cannot read RESULT Rows metadata: %w", err)
+	}
+	var rowsCount int32
+	if rowsCount, err = primitive.ReadInt(source); err != nil {
+		return nil, fmt.Errorf("cannot read RESULT Rows data length: %w", err)
+	} else if rowsCount < 0 {
+		return nil, fmt.Errorf("invalid RESULT Rows data length: %d", rowsCount)
+	}
+	rows.rowsCount = int(rowsCount)
+	cellsCount := rows.rowsCount * int(rows.Metadata.ColumnCount)
+	var length int
+	switch s := source.(type) {
+	case *primitive.Cursor:
+		if length, err = scanCells(s.Remaining(), cellsCount, int(rows.Metadata.ColumnCount)); err != nil {
+			return nil, err
+		}
+		rows.data, _ = s.Next(length)
+	case *bytes.Buffer:
+		if length, err = scanCells(s.Bytes(), cellsCount, int(rows.Metadata.ColumnCount)); err != nil {
+			return nil, err
+		}
+		rows.data = s.Next(length)
+	default:
+		if rows.data, err = readCells(source, cellsCount, int(rows.Metadata.ColumnCount)); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// scanCells checks that the given data starts with the given number of [bytes] cells, and returns their total length.
+func scanCells(data []byte, cellsCount, columnCount int) (length int, err error) {
+	for i := 0; i < cellsCount; i++ {
+		if _, length, err = sliceBytes(data, length); err != nil {
+			return -1, fmt.Errorf("cannot read RESULT Rows data row %d col %d: %w", i/columnCount, i%columnCount, err)
+		}
+	}
+	return length, nil
+}
+
+// readCells reads the given number of [bytes] cells from the given source, and returns them in their encoded form.
+func readCells(source io.Reader, cellsCount, columnCount int) (data []byte, err error) {
+	data = []byte{}
+	for i := 0; i < cellsCount; i++ {
+		start := len(data)
+		data = append(data, 0, 0, 0, 0)
+		if _, err = io.ReadFull(source, data[start:]); err != nil {
+			return nil, fmt.Errorf("cannot read RESULT Rows data row %d col %d: cannot read [bytes] length: %w", i/columnCount, i%columnCount, err)
+		}
+		length := int32(binary.BigEndian.Uint32(data[start:]))
+		if length <= 0 {
+			continue
+		}
+		start = len(data)
+		data = append(data, make([]byte, length)...)
+		if _, err = io.ReadFull(source, data[start:]); err != nil {
+			return nil, fmt.Errorf("cannot read RESULT Rows data row %d col %d: cannot read [bytes] content: %w", i/columnCount, i%columnCount, err)
+		}
+	}
+	return data, nil
+}
+
+// sliceBytes slices a [bytes] value out of data at the given position, and returns it along with the position of the
+// next value. It returns nil for NULL values, and an empty slice for empty values, just like primitive.ReadBytes.
+func sliceBytes(data []byte, pos int) (value []byte, next int, err error) {
+	if len(data)-pos < primitive.LengthOfInt {
+		return nil, pos, fmt.Errorf("cannot read [bytes] length: %w", io.ErrUnexpectedEOF)
+	}
+	length := int(int32(binary.BigEndian.Uint32(data[pos:])))
+	pos += primitive.LengthOfInt
+	if length < 0 {
+		return nil, pos, nil
+	} else if len(data)-pos < length {
+		return nil, pos, fmt.Errorf("cannot read [bytes] content: %w", io.ErrUnexpectedEOF)
+	}
+	return data[pos : pos+length : pos+length], pos + length, nil
+}
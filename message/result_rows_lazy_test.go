@@ -0,0 +1,187 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+var (
+	lazyRowsMetadata = &RowsMetadata{
+		ColumnCount: 2,
+		Columns: []*ColumnMetadata{
+			{Keyspace: "ks1", Table: "table1", Name: "col1", Index: 0, Type: datatype.Int},
+			{Keyspace: "ks1", Table: "table1", Name: "col2", Index: 0, Type: datatype.Varchar},
+		},
+	}
+	lazyRows = &RowsResult{
+		Metadata: lazyRowsMetadata,
+		Data: RowSet{
+			{Column{0, 0, 0, 1}, Column{h, e, l, l, o}},
+			{Column{0, 0, 0, 2}, nil},
+			{Column{0, 0, 0, 3}, Column{}},
+		},
+	}
+)
+
+func encodeLazyTestRows(t *testing.T, rows *RowsResult, version primitive.ProtocolVersion) []byte {
+	buf := &bytes.Buffer{}
+	require.NoError(t, (&resultCodec{}).Encode(rows, buf, version))
+	return buf.Bytes()
+}
+
+func TestLazyResultCodec_Decode_Rows(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			encoded := encodeLazyTestRows(t, lazyRows, version)
+			t.Run("bytes.Buffer", func(t *testing.T) {
+				msg, err := LazyResultCodec.Decode(bytes.NewBuffer(encoded), version)
+				require.NoError(t, err)
+				lazy, ok := msg.(*LazyRowsResult)
+				require.True(t, ok)
+				assert.Equal(t, lazyRowsMetadata, lazy.Metadata)
+				assert.Equal(t, 3, lazy.RowsCount())
+				assert.Equal(t, "RESULT ROWS (3 rows x 2 cols)", lazy.String())
+				it := lazy.Rows()
+				for i, expected := range lazyRows.Data {
+					require.True(t, it.Next())
+					assert.Equal(t, i, it.Index())
+					assert.Equal(t, expected, it.Row())
+				}
+				assert.False(t, it.Next())
+				assert.NoError(t, it.Err())
+				materialized, err := lazy.ToRowsResult()
+				require.NoError(t, err)
+				assert.Equal(t, lazyRows, materialized)
+			})
+			t.Run("primitive.Cursor", func(t *testing.T) {
+				cursor := primitive.NewCursor(encoded)
+				msg, err := LazyResultCodec.Decode(cursor, version)
+				require.NoError(t, err)
+				assert.Equal(t, 0, cursor.Len())
+				materialized, err := msg.(*LazyRowsResult).ToRowsResult()
+				require.NoError(t, err)
+				assert.Equal(t, lazyRows, materialized)
+			})
+			t.Run("other reader", func(t *testing.T) {
+				msg, err := LazyResultCodec.Decode(iotest.OneByteReader(bytes.NewReader(encoded)), version)
+				require.NoError(t, err)
+				materialized, err := msg.(*LazyRowsResult).ToRowsResult()
+				require.NoError(t, err)
+				assert.Equal(t, lazyRows, materialized)
+			})
+		})
+	}
+}
+
+func TestLazyResultCodec_Decode_ZeroCopy(t *testing.T) {
+	encoded := encodeLazyTestRows(t, lazyRows, primitive.ProtocolVersion4)
+	buf := bytes.NewBuffer(encoded)
+	msg, err := LazyResultCodec.Decode(buf, primitive.ProtocolVersion4)
+	require.NoError(t, err)
+	assert.Equal(t, 0, buf.Len(), "body should be fully consumed")
+	it := msg.(*LazyRowsResult).Rows()
+	require.True(t, it.Next())
+	// cells share their memory with the body
+	it.Row()[1][0] = 'j'
+	assert.Contains(t, string(encoded), "jello")
+	// but deep copies do not
+	copied := msg.DeepCopyMessage().(*LazyRowsResult)
+	it = copied.Rows()
+	require.True(t, it.Next())
+	it.Row()[1][0] = 'y'
+	assert.Contains(t, string(encoded), "jello")
+}
+
+func TestLazyResultCodec_Decode_Errors(t *testing.T) {
+	encoded := encodeLazyTestRows(t, lazyRows, primitive.ProtocolVersion4)
+	tests := []struct {
+		name   string
+		source []byte
+		err    string
+	}{
+		{"missing type", []byte{0, 0}, "cannot read RESULT type"},
+		{"missing rows count", encoded[:len(encoded)-43], "cannot read RESULT Rows data length"},
+		{"negative rows count", append(encoded[:len(encoded)-45:len(encoded)-45], 0xff, 0xff, 0xff, 0xff), "invalid RESULT Rows data length: -1"},
+		{"truncated cell length", encoded[:len(encoded)-2], "cannot read RESULT Rows data row 2 col 1: cannot read [bytes] length"},
+		{"truncated cell content", encoded[:len(encoded)-27], "cannot read RESULT Rows data row 0 col 1: cannot read [bytes] content"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, source := range []io.Reader{bytes.NewBuffer(tt.source), primitive.NewCursor(tt.source), bytes.NewReader(tt.source)} {
+				_, err := LazyResultCodec.Decode(source, primitive.ProtocolVersion4)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
+
+func TestLazyResultCodec_Decode_OtherResults(t *testing.T) {
+	msg, err := LazyResultCodec.Decode(bytes.NewBuffer([]byte{0, 0, 0, 1}), primitive.ProtocolVersion4)
+	require.NoError(t, err)
+	assert.Equal(t, &VoidResult{}, msg)
+	msg, err = LazyResultCodec.Decode(bytes.NewBuffer([]byte{0, 0, 0, 3, 0, 3, k, s, _1}), primitive.ProtocolVersion4)
+	require.NoError(t, err)
+	assert.Equal(t, &SetKeyspaceResult{Keyspace: "ks1"}, msg)
+}
+
+func TestLazyResultCodec_Encode(t *testing.T) {
+	for _, version := range primitive.SupportedProtocolVersions() {
+		t.Run(version.String(), func(t *testing.T) {
+			encoded := encodeLazyTestRows(t, lazyRows, version)
+			msg, err := LazyResultCodec.Decode(bytes.NewBuffer(encoded), version)
+			require.NoError(t, err)
+			buf := &bytes.Buffer{}
+			require.NoError(t, LazyResultCodec.Encode(msg, buf, version))
+			assert.Equal(t, encoded, buf.Bytes())
+			length, err := LazyResultCodec.EncodedLength(msg, version)
+			require.NoError(t, err)
+			assert.Equal(t, len(encoded), length)
+			// eager results are encoded as usual
+			buf.Reset()
+			require.NoError(t, LazyResultCodec.Encode(lazyRows, buf, version))
+			assert.Equal(t, encoded, buf.Bytes())
+			length, err = LazyResultCodec.EncodedLength(lazyRows, version)
+			require.NoError(t, err)
+			assert.Equal(t, len(encoded), length)
+		})
+	}
+}
+
+func TestRowIterator_Errors(t *testing.T) {
+	lazy := &LazyRowsResult{
+		Metadata:  &RowsMetadata{ColumnCount: 1},
+		rowsCount: 2,
+		data:      []byte{0, 0, 0, 1, 42, 0, 0, 0, 2, 1},
+	}
+	it := lazy.Rows()
+	require.True(t, it.Next())
+	assert.Equal(t, Row{{42}}, it.Row())
+	assert.False(t, it.Next())
+	assert.EqualError(t, it.Err(), "cannot read RESULT Rows data row 1 col 0: cannot read [bytes] content: unexpected EOF")
+	assert.False(t, it.Next())
+	_, err := lazy.ToRowsResult()
+	assert.EqualError(t, err, "cannot read RESULT Rows data row 1 col 0: cannot read [bytes] content: unexpected EOF")
+}
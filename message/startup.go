@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/datastax/go-cassandra-native-protocol/primitive"
 )
@@ -78,11 +79,21 @@ func NewStartup(keysAndValues ...string) *Startup {
 	return startup
 }
 
+func (m *Startup) GetCqlVersion() string {
+	return m.Options[StartupOptionCqlVersion]
+}
+
+func (m *Startup) SetCqlVersion(cqlVersion string) {
+	m.setOption(StartupOptionCqlVersion, cqlVersion)
+}
+
+// GetCompression returns the compression algorithm to use. Compression names are case-insensitive, and are
+// normalized to upper case.
 func (m *Startup) GetCompression() primitive.Compression {
 	if compressionStr, found := m.Options[StartupOptionCompression]; !found {
 		return primitive.CompressionNone
 	} else {
-		return primitive.Compression(compressionStr)
+		return primitive.Compression(strings.ToUpper(compressionStr))
 	}
 }
 
@@ -90,7 +101,7 @@ func (m *Startup) SetCompression(compression primitive.Compression) {
 	if compression == primitive.CompressionNone {
 		delete(m.Options, StartupOptionCompression)
 	} else {
-		m.Options[StartupOptionCompression] = string(compression)
+		m.setOption(StartupOptionCompression, string(compression))
 	}
 }
 
@@ -99,7 +110,7 @@ func (m *Startup) GetClientId() string {
 }
 
 func (m *Startup) SetClientId(clientId string) {
-	m.Options[StartupOptionClientId] = clientId
+	m.setOption(StartupOptionClientId, clientId)
 }
 
 func (m *Startup) GetApplicationName() string {
@@ -107,7 +118,7 @@ func (m *Startup) GetApplicationName() string {
 }
 
 func (m *Startup) SetApplicationName(applicationName string) {
-	m.Options[StartupOptionApplicationName] = applicationName
+	m.setOption(StartupOptionApplicationName, applicationName)
 }
 
 func (m *Startup) GetApplicationVersion() string {
@@ -115,7 +126,7 @@ func (m *Startup) GetApplicationVersion() string {
 }
 
 func (m *Startup) SetApplicationVersion(applicationVersion string) {
-	m.Options[StartupOptionApplicationVersion] = applicationVersion
+	m.setOption(StartupOptionApplicationVersion, applicationVersion)
 }
 
 func (m *Startup) GetDriverName() string {
@@ -123,7 +134,7 @@ func (m *Startup) GetDriverName() string {
 }
 
 func (m *Startup) SetDriverName(driverName string) {
-	m.Options[StartupOptionDriverName] = driverName
+	m.setOption(StartupOptionDriverName, driverName)
 }
 
 func (m *Startup) GetDriverVersion() string {
@@ -131,7 +142,7 @@ func (m *Startup) GetDriverVersion() string {
 }
 
 func (m *Startup) SetDriverVersion(driverVersion string) {
-	m.Options[StartupOptionDriverVersion] = driverVersion
+	m.setOption(StartupOptionDriverVersion, driverVersion)
 }
 
 func (m *Startup) IsThrowOnOverload() bool {
@@ -141,10 +152,17 @@ func (m *Startup) IsThrowOnOverload() bool {
 
 func (m *Startup) SetThrowOnOverload(throwOnOverload bool) {
 	if throwOnOverload {
-		m.Options[StartupOptionDriverVersion] = "1"
+		m.setOption(StartupOptionThrowOnOverload, "1")
 	} else {
-		delete(m.Options, StartupOptionDriverVersion)
+		delete(m.Options, StartupOptionThrowOnOverload)
+	}
+}
+
+func (m *Startup) setOption(key string, value string) {
+	if m.Options == nil {
+		m.Options = map[string]string{}
 	}
+	m.Options[key] = value
 }
 
 func (m *Startup) IsResponse() bool {
@@ -48,6 +48,61 @@ func TestStartup_DeepCopy(t *testing.T) {
 	assert.Equal(t, "val6", cloned.Options["opt3"])
 }
 
+func TestStartup_Options(t *testing.T) {
+	startup := NewStartup()
+	assert.Equal(t, "3.0.0", startup.GetCqlVersion())
+	assert.Equal(t, primitive.CompressionNone, startup.GetCompression())
+	startup.SetCqlVersion("3.4.5")
+	startup.SetCompression(primitive.CompressionLz4)
+	startup.SetClientId("client")
+	startup.SetApplicationName("app")
+	startup.SetApplicationVersion("1.0")
+	startup.SetDriverName("driver")
+	startup.SetDriverVersion("2.0")
+	startup.SetThrowOnOverload(true)
+	assert.Equal(t, map[string]string{
+		StartupOptionCqlVersion:         "3.4.5",
+		StartupOptionCompression:        "LZ4",
+		StartupOptionClientId:           "client",
+		StartupOptionApplicationName:    "app",
+		StartupOptionApplicationVersion: "1.0",
+		StartupOptionDriverName:         "driver",
+		StartupOptionDriverVersion:      "2.0",
+		StartupOptionThrowOnOverload:    "1",
+	}, startup.Options)
+	assert.Equal(t, "3.4.5", startup.GetCqlVersion())
+	assert.Equal(t, primitive.CompressionLz4, startup.GetCompression())
+	assert.Equal(t, "client", startup.GetClientId())
+	assert.Equal(t, "app", startup.GetApplicationName())
+	assert.Equal(t, "1.0", startup.GetApplicationVersion())
+	assert.Equal(t, "driver", startup.GetDriverName())
+	assert.Equal(t, "2.0", startup.GetDriverVersion())
+	assert.True(t, startup.IsThrowOnOverload())
+	startup.SetThrowOnOverload(false)
+	startup.SetCompression(primitive.CompressionNone)
+	assert.False(t, startup.IsThrowOnOverload())
+	assert.Equal(t, "2.0", startup.GetDriverVersion())
+	assert.NotContains(t, startup.Options, StartupOptionCompression)
+	startup.Options[StartupOptionCompression] = "snappy"
+	assert.Equal(t, primitive.CompressionSnappy, startup.GetCompression())
+}
+
+func TestStartup_Options_ZeroValue(t *testing.T) {
+	startup := &Startup{}
+	assert.Equal(t, "", startup.GetCqlVersion())
+	assert.Equal(t, primitive.CompressionNone, startup.GetCompression())
+	assert.False(t, startup.IsThrowOnOverload())
+	startup.SetCompression(primitive.CompressionNone)
+	startup.SetThrowOnOverload(false)
+	assert.Nil(t, startup.Options)
+	startup.SetCqlVersion("3.0.0")
+	startup.SetThrowOnOverload(true)
+	assert.Equal(t, map[string]string{StartupOptionCqlVersion: "3.0.0", StartupOptionThrowOnOverload: "1"}, startup.Options)
+	startup = &Startup{}
+	startup.SetCompression(primitive.CompressionLz4)
+	assert.Equal(t, primitive.CompressionLz4, startup.GetCompression())
+}
+
 func TestStartupCodec_Encode(t *testing.T) {
 	codec := &startupCodec{}
 	for _, version := range primitive.SupportedProtocolVersions() {
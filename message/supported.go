@@ -18,6 +18,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/datastax/go-cassandra-native-protocol/primitive"
 )
@@ -28,8 +30,57 @@ const (
 	// slash and the version description. For example: 3/v3, 4/v4, 5/v5-beta. If a version is in beta, it will have the
 	// word "beta" in its description.
 	SupportedProtocolVersions = "PROTOCOL_VERSIONS"
+
+	// SupportedPageUnits is a Supported.Options multimap key returned by DSE. It holds the list of units that can be
+	// used to express page sizes, e.g. "bytes" and "rows"; see QueryOptions.PageSizeInBytes.
+	SupportedPageUnits = "PAGE_UNIT"
 )
 
+// ProtocolVersionOption is the typed form of a value of the SupportedProtocolVersions option, e.g. "5/v5-beta".
+type ProtocolVersionOption struct {
+	Version     primitive.ProtocolVersion
+	Description string
+}
+
+// NewProtocolVersionOption returns a ProtocolVersionOption for the given version, with a description formatted the way
+// Cassandra and DSE do, e.g. "v4", "v5-beta" or "DSE_V2".
+func NewProtocolVersionOption(version primitive.ProtocolVersion, beta bool) ProtocolVersionOption {
+	var description string
+	if version.IsDse() {
+		description = fmt.Sprintf("DSE_V%d", version-primitive.ProtocolVersionDse1+1)
+	} else {
+		description = fmt.Sprintf("v%d", version)
+	}
+	if beta {
+		description += "-beta"
+	}
+	return ProtocolVersionOption{Version: version, Description: description}
+}
+
+// ParseProtocolVersionOption parses a value of the SupportedProtocolVersions option. Version numbers that are unknown
+// to this library are parsed as well.
+func ParseProtocolVersionOption(option string) (ProtocolVersionOption, error) {
+	i := strings.IndexByte(option, '/')
+	if i < 0 {
+		return ProtocolVersionOption{}, fmt.Errorf("cannot parse protocol version option %q: missing slash", option)
+	}
+	version, err := strconv.ParseUint(option[:i], 10, 8)
+	if err != nil {
+		return ProtocolVersionOption{}, fmt.Errorf("cannot parse protocol version option %q: %w", option, err)
+	}
+	return ProtocolVersionOption{Version: primitive.ProtocolVersion(version), Description: option[i+1:]}, nil
+}
+
+// IsBeta returns true if this version is a beta version. Beta versions can only be used by clients that explicitly
+// opt in, see primitive.HeaderFlagUseBeta.
+func (o ProtocolVersionOption) IsBeta() bool {
+	return strings.Contains(strings.ToLower(o.Description), "beta")
+}
+
+func (o ProtocolVersionOption) String() string {
+	return fmt.Sprintf("%d/%s", o.Version, o.Description)
+}
+
 // Supported is a response message sent in reply to an Options request.
 // +k8s:deepcopy-gen=true
 // +k8s:deepcopy-gen:interfaces=github.com/datastax/go-cassandra-native-protocol/message.Message
@@ -39,6 +90,161 @@ type Supported struct {
 	Options map[string][]string
 }
 
+// NewSupported returns a new Supported message with an empty options multimap.
+func NewSupported() *Supported {
+	return &Supported{Options: map[string][]string{}}
+}
+
+// GetProtocolVersions returns the protocol versions supported by the server, or nil if the server did not advertise
+// them, which is the case for servers that do not support protocol version 5 or higher.
+func (m *Supported) GetProtocolVersions() ([]ProtocolVersionOption, error) {
+	values := m.Options[SupportedProtocolVersions]
+	if values == nil {
+		return nil, nil
+	}
+	versions := make([]ProtocolVersionOption, len(values))
+	for i, value := range values {
+		var err error
+		if versions[i], err = ParseProtocolVersionOption(value); err != nil {
+			return nil, err
+		}
+	}
+	return versions, nil
+}
+
+func (m *Supported) SetProtocolVersions(versions ...ProtocolVersionOption) {
+	values := make([]string, len(versions))
+	for i, version := range versions {
+		values[i] = version.String()
+	}
+	m.setOption(SupportedProtocolVersions, values)
+}
+
+// GetCompressions returns the compression algorithms supported by the server. Compression names are case-insensitive,
+// and are normalized to upper case.
+func (m *Supported) GetCompressions() []primitive.Compression {
+	values := m.Options[StartupOptionCompression]
+	if values == nil {
+		return nil
+	}
+	compressions := make([]primitive.Compression, len(values))
+	for i, value := range values {
+		compressions[i] = primitive.Compression(strings.ToUpper(value))
+	}
+	return compressions
+}
+
+// SetCompressions sets the compression algorithms supported by the server. Compression names are written in lower
+// case, like Cassandra does.
+func (m *Supported) SetCompressions(compressions ...primitive.Compression) {
+	values := make([]string, len(compressions))
+	for i, compression := range compressions {
+		values[i] = strings.ToLower(string(compression))
+	}
+	m.setOption(StartupOptionCompression, values)
+}
+
+func (m *Supported) GetCqlVersions() []string {
+	return m.Options[StartupOptionCqlVersion]
+}
+
+func (m *Supported) SetCqlVersions(cqlVersions ...string) {
+	m.setOption(StartupOptionCqlVersion, cqlVersions)
+}
+
+func (m *Supported) GetPageUnits() []string {
+	return m.Options[SupportedPageUnits]
+}
+
+func (m *Supported) SetPageUnits(pageUnits ...string) {
+	m.setOption(SupportedPageUnits, pageUnits)
+}
+
+func (m *Supported) setOption(key string, values []string) {
+	if m.Options == nil {
+		m.Options = map[string][]string{}
+	}
+	m.Options[key] = values
+}
+
+// NegotiateProtocolVersion returns the highest protocol version among the given candidates that is also supported by
+// the server. Beta versions are only considered if allowBeta is true, and malformed versions are ignored. It returns
+// false if no common version was found, or if the server did not advertise its supported versions.
+func (m *Supported) NegotiateProtocolVersion(allowBeta bool, candidates ...primitive.ProtocolVersion) (primitive.ProtocolVersion, bool) {
+	var best primitive.ProtocolVersion
+	found := false
+	for _, value := range m.Options[SupportedProtocolVersions] {
+		version, err := ParseProtocolVersionOption(value)
+		if err != nil || (version.IsBeta() && !allowBeta) {
+			continue
+		}
+		for _, candidate := range candidates {
+			if candidate == version.Version && (!found || candidate > best) {
+				best = candidate
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// NegotiateCompression returns the first of the given preferred compression algorithms that is supported by both the
+// server and the given protocol version, or primitive.CompressionNone if there is none.
+func (m *Supported) NegotiateCompression(version primitive.ProtocolVersion, preferred ...primitive.Compression) primitive.Compression {
+	supported := m.GetCompressions()
+	for _, candidate := range preferred {
+		candidate = primitive.Compression(strings.ToUpper(string(candidate)))
+		if candidate == primitive.CompressionNone || !version.SupportsCompression(candidate) {
+			continue
+		}
+		for _, compression := range supported {
+			if compression == candidate {
+				return candidate
+			}
+		}
+	}
+	return primitive.CompressionNone
+}
+
+// NegotiateCqlVersion returns the highest CQL version among the given candidates that is also supported by the server.
+// Versions are compared numerically component by component, e.g. "3.4.10" is higher than "3.4.5". It returns false if
+// no common version was found.
+func (m *Supported) NegotiateCqlVersion(candidates ...string) (string, bool) {
+	var best string
+	found := false
+	for _, version := range m.GetCqlVersions() {
+		for _, candidate := range candidates {
+			if candidate == version && (!found || compareCqlVersions(candidate, best) > 0) {
+				best = candidate
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// compareCqlVersions compares dotted version strings numerically; non-numeric components compare as zero.
+func compareCqlVersions(v1, v2 string) int {
+	c1 := strings.Split(v1, ".")
+	c2 := strings.Split(v2, ".")
+	for i := 0; i < len(c1) || i < len(c2); i++ {
+		var n1, n2 int
+		if i < len(c1) {
+			n1, _ = strconv.Atoi(c1[i])
+		}
+		if i < len(c2) {
+			n2, _ = strconv.Atoi(c2[i])
+		}
+		if n1 != n2 {
+			if n1 < n2 {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
 func (m *Supported) IsResponse() bool {
 	return true
 }
@@ -48,6 +48,117 @@ func TestSupported_DeepCopy(t *testing.T) {
 	assert.Equal(t, "val6", cloned.Options["opt3"][0])
 }
 
+func TestProtocolVersionOption(t *testing.T) {
+	tests := []struct {
+		option   string
+		expected ProtocolVersionOption
+		beta     bool
+	}{
+		{"3/v3", NewProtocolVersionOption(primitive.ProtocolVersion3, false), false},
+		{"5/v5-beta", NewProtocolVersionOption(primitive.ProtocolVersion5, true), true},
+		{"65/DSE_V1", NewProtocolVersionOption(primitive.ProtocolVersionDse1, false), false},
+		{"66/DSE_V2", NewProtocolVersionOption(primitive.ProtocolVersionDse2, false), false},
+		{"6/v6-BETA", ProtocolVersionOption{Version: primitive.ProtocolVersion(6), Description: "v6-BETA"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.option, func(t *testing.T) {
+			actual, err := ParseProtocolVersionOption(tt.option)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.beta, actual.IsBeta())
+			assert.Equal(t, tt.option, actual.String())
+		})
+	}
+	_, err := ParseProtocolVersionOption("v3")
+	assert.EqualError(t, err, `cannot parse protocol version option "v3": missing slash`)
+	_, err = ParseProtocolVersionOption("300/v300")
+	assert.EqualError(t, err, `cannot parse protocol version option "300/v300": strconv.ParseUint: parsing "300": value out of range`)
+}
+
+func TestSupported_Options(t *testing.T) {
+	supported := &Supported{}
+	versions, err := supported.GetProtocolVersions()
+	assert.NoError(t, err)
+	assert.Nil(t, versions)
+	assert.Nil(t, supported.GetCompressions())
+	supported.SetProtocolVersions(
+		NewProtocolVersionOption(primitive.ProtocolVersion3, false),
+		NewProtocolVersionOption(primitive.ProtocolVersion4, false),
+		NewProtocolVersionOption(primitive.ProtocolVersion5, true),
+	)
+	supported.SetCompressions(primitive.CompressionSnappy, primitive.CompressionLz4)
+	supported.SetCqlVersions("3.4.5")
+	supported.SetPageUnits("bytes", "rows")
+	assert.Equal(t, map[string][]string{
+		SupportedProtocolVersions: {"3/v3", "4/v4", "5/v5-beta"},
+		StartupOptionCompression:  {"snappy", "lz4"},
+		StartupOptionCqlVersion:   {"3.4.5"},
+		SupportedPageUnits:        {"bytes", "rows"},
+	}, supported.Options)
+	versions, err = supported.GetProtocolVersions()
+	assert.NoError(t, err)
+	assert.Equal(t, []ProtocolVersionOption{
+		{Version: primitive.ProtocolVersion3, Description: "v3"},
+		{Version: primitive.ProtocolVersion4, Description: "v4"},
+		{Version: primitive.ProtocolVersion5, Description: "v5-beta"},
+	}, versions)
+	assert.Equal(t, []primitive.Compression{primitive.CompressionSnappy, primitive.CompressionLz4}, supported.GetCompressions())
+	assert.Equal(t, []string{"3.4.5"}, supported.GetCqlVersions())
+	assert.Equal(t, []string{"bytes", "rows"}, supported.GetPageUnits())
+	supported.Options[SupportedProtocolVersions] = []string{"3/v3", "nonsense"}
+	_, err = supported.GetProtocolVersions()
+	assert.EqualError(t, err, `cannot parse protocol version option "nonsense": missing slash`)
+}
+
+func TestSupported_NegotiateProtocolVersion(t *testing.T) {
+	supported := NewSupported()
+	_, found := supported.NegotiateProtocolVersion(true, primitive.ProtocolVersion4)
+	assert.False(t, found)
+	supported.Options[SupportedProtocolVersions] = []string{"3/v3", "4/v4", "5/v5-beta", "nonsense", "65/DSE_V1"}
+	tests := []struct {
+		name       string
+		allowBeta  bool
+		candidates []primitive.ProtocolVersion
+		expected   primitive.ProtocolVersion
+		found      bool
+	}{
+		{"highest", false, []primitive.ProtocolVersion{primitive.ProtocolVersion3, primitive.ProtocolVersion4}, primitive.ProtocolVersion4, true},
+		{"beta not allowed", false, []primitive.ProtocolVersion{primitive.ProtocolVersion5, primitive.ProtocolVersion3}, primitive.ProtocolVersion3, true},
+		{"beta allowed", true, []primitive.ProtocolVersion{primitive.ProtocolVersion3, primitive.ProtocolVersion5}, primitive.ProtocolVersion5, true},
+		{"DSE", false, primitive.SupportedProtocolVersions(), primitive.ProtocolVersionDse1, true},
+		{"none", true, []primitive.ProtocolVersion{primitive.ProtocolVersion2, primitive.ProtocolVersionDse2}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, found := supported.NegotiateProtocolVersion(tt.allowBeta, tt.candidates...)
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestSupported_NegotiateCompression(t *testing.T) {
+	supported := &Supported{Options: map[string][]string{StartupOptionCompression: {"snappy", "lz4"}}}
+	assert.Equal(t, primitive.CompressionLz4, supported.NegotiateCompression(primitive.ProtocolVersion4, primitive.CompressionLz4, primitive.CompressionSnappy))
+	assert.Equal(t, primitive.CompressionSnappy, supported.NegotiateCompression(primitive.ProtocolVersion4, "snappy", primitive.CompressionLz4))
+	assert.Equal(t, primitive.CompressionLz4, supported.NegotiateCompression(primitive.ProtocolVersion5, primitive.CompressionSnappy, primitive.CompressionLz4))
+	assert.Equal(t, primitive.CompressionNone, supported.NegotiateCompression(primitive.ProtocolVersion5, primitive.CompressionSnappy))
+	assert.Equal(t, primitive.CompressionNone, supported.NegotiateCompression(primitive.ProtocolVersion4))
+	assert.Equal(t, primitive.CompressionNone, NewSupported().NegotiateCompression(primitive.ProtocolVersion4, primitive.CompressionLz4))
+}
+
+func TestSupported_NegotiateCqlVersion(t *testing.T) {
+	supported := &Supported{Options: map[string][]string{StartupOptionCqlVersion: {"3.4.5", "3.4.10", "3.0.0"}}}
+	actual, found := supported.NegotiateCqlVersion("3.0.0", "3.4.10", "3.4.5")
+	assert.True(t, found)
+	assert.Equal(t, "3.4.10", actual)
+	actual, found = supported.NegotiateCqlVersion("3.0.0")
+	assert.True(t, found)
+	assert.Equal(t, "3.0.0", actual)
+	_, found = supported.NegotiateCqlVersion("4.0.0")
+	assert.False(t, found)
+}
+
 func TestSupportedCodec_Encode(test *testing.T) {
 	codec := &supportedCodec{}
 	for _, version := range primitive.SupportedProtocolVersions() {
@@ -0,0 +1,444 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// Validator is implemented by messages that can check whether they can be encoded with a given protocol version. All
+// the messages in this package implement it.
+type Validator interface {
+
+	// Validate returns a *ValidationError reporting all the fields of this message that cannot be encoded with the
+	// given protocol version, either because encoding would fail, or because the field would be silently dropped.
+	// It returns nil if the message is valid for the given protocol version.
+	Validate(version primitive.ProtocolVersion) error
+}
+
+// Validate validates the given message for the given protocol version, if the message implements Validator; other
+// messages are considered valid.
+func Validate(msg Message, version primitive.ProtocolVersion) error {
+	if validator, ok := msg.(Validator); ok {
+		return validator.Validate(version)
+	}
+	return nil
+}
+
+// Violation is a protocol version violation found when validating a message.
+type Violation struct {
+	// Field is the path of the offending field, relative to the message, e.g. "Options.Keyspace" or
+	// "Children[1].Values[0]"; it is empty when the message as a whole is invalid for the protocol version.
+	Field string
+	// Reason describes the violation.
+	Reason string
+}
+
+func (v *Violation) String() string {
+	if v.Field == "" {
+		return v.Reason
+	}
+	return fmt.Sprintf("%s: %s", v.Field, v.Reason)
+}
+
+// ValidationError is the error returned by Validator.Validate.
+type ValidationError struct {
+	OpCode     primitive.OpCode
+	Version    primitive.ProtocolVersion
+	Violations []*Violation
+}
+
+func (e *ValidationError) Error() string {
+	violations := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		violations[i] = violation.String()
+	}
+	return fmt.Sprintf("invalid %v message for %v: %s", e.OpCode, e.Version, strings.Join(violations, "; "))
+}
+
+// validation accumulates the violations found when validating a message.
+type validation struct {
+	version    primitive.ProtocolVersion
+	violations []*Violation
+}
+
+func newValidation(version primitive.ProtocolVersion) *validation {
+	v := &validation{version: version}
+	if !version.IsSupported() {
+		v.add("", "unsupported protocol version")
+	}
+	return v
+}
+
+func (v *validation) add(field string, reason string) {
+	v.violations = append(v.violations, &Violation{Field: field, Reason: reason})
+}
+
+func (v *validation) result(msg Message) error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{OpCode: msg.GetOpCode(), Version: v.version, Violations: v.violations}
+}
+
+// unsupported adds a violation for the given field if it is present but not supported.
+func (v *validation) unsupported(field string, present bool, supported bool) {
+	if present && !supported {
+		v.add(field, "not supported")
+	}
+}
+
+// resultMetadataId checks a result metadata id, which is mandatory when supported.
+func (v *validation) resultMetadataId(field string, id []byte) {
+	if v.version.SupportsResultMetadataId() {
+		if len(id) == 0 {
+			v.add(field, "required")
+		}
+	} else {
+		v.unsupported(field, len(id) > 0, false)
+	}
+}
+
+func (v *validation) value(field string, value *primitive.Value) {
+	if value != nil && value.Type == primitive.ValueTypeUnset && !v.version.SupportsUnsetValues() {
+		v.add(field, "unset values not supported")
+	}
+}
+
+func (v *validation) queryOptions(prefix string, options *QueryOptions) {
+	if options == nil {
+		return
+	}
+	for i, value := range options.PositionalValues {
+		v.value(fmt.Sprintf("%sPositionalValues[%d]", prefix, i), value)
+	}
+	if options.NamedValues != nil {
+		if options.PositionalValues != nil {
+			v.add(prefix+"NamedValues", "cannot be used together with positional values")
+		} else if !v.version.SupportsQueryFlag(primitive.QueryFlagValueNames) {
+			v.add(prefix+"NamedValues", "not supported")
+		} else {
+			names := make([]string, 0, len(options.NamedValues))
+			for name := range options.NamedValues {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				v.value(fmt.Sprintf("%sNamedValues[%s]", prefix, name), options.NamedValues[name])
+			}
+		}
+	}
+	v.unsupported(prefix+"PageSizeInBytes", options.PageSizeInBytes, v.version.SupportsQueryFlag(primitive.QueryFlagDsePageSizeBytes))
+	v.unsupported(prefix+"DefaultTimestamp", options.DefaultTimestamp != nil, v.version.SupportsQueryFlag(primitive.QueryFlagDefaultTimestamp))
+	v.unsupported(prefix+"Keyspace", options.Keyspace != "", v.version.SupportsQueryFlag(primitive.QueryFlagWithKeyspace))
+	v.unsupported(prefix+"NowInSeconds", options.NowInSeconds != nil, v.version.SupportsQueryFlag(primitive.QueryFlagNowInSeconds))
+	if options.ContinuousPagingOptions != nil {
+		if !v.version.SupportsQueryFlag(primitive.QueryFlagDseWithContinuousPagingOptions) {
+			v.add(prefix+"ContinuousPagingOptions", "not supported")
+		} else {
+			v.unsupported(prefix+"ContinuousPagingOptions.NextPages", options.ContinuousPagingOptions.NextPages != 0, v.version >= primitive.ProtocolVersionDse2)
+		}
+	}
+}
+
+func (v *validation) schemaChange(target primitive.SchemaChangeTarget, arguments []string) {
+	v.unsupported("Target", target.IsValid(), v.version.SupportsSchemaChangeTarget(target))
+	v.unsupported("Arguments", len(arguments) > 0, v.version >= primitive.ProtocolVersion4)
+}
+
+func (v *validation) rowsMetadata(prefix string, metadata *RowsMetadata) {
+	if metadata == nil {
+		return
+	}
+	v.unsupported(prefix+"NewResultMetadataId", len(metadata.NewResultMetadataId) > 0, v.version.SupportsResultMetadataId())
+	v.unsupported(prefix+"ContinuousPageNumber", metadata.ContinuousPageNumber != 0, v.version.IsDse())
+	v.unsupported(prefix+"LastContinuousPage", metadata.LastContinuousPage, v.version.IsDse())
+	v.columns(prefix+"Columns", metadata.Columns)
+}
+
+// failure checks the contents of READ FAILURE and WRITE FAILURE errors, which were introduced in protocol version 4.
+func (v *validation) failure(numFailures int32, failureReasons []*primitive.FailureReason) {
+	v.unsupported("", true, v.version >= primitive.ProtocolVersion4)
+	v.unsupported("NumFailures", numFailures != 0, !v.version.SupportsReadWriteFailureReasonMap())
+	v.unsupported("FailureReasons", len(failureReasons) > 0, v.version.SupportsReadWriteFailureReasonMap())
+}
+
+func (v *validation) columns(prefix string, columns []*ColumnMetadata) {
+	for i, column := range columns {
+		if column == nil {
+			continue
+		}
+		if unsupported := v.unsupportedDataType(column.Type); unsupported != nil {
+			v.add(fmt.Sprintf("%s[%d].Type", prefix, i), fmt.Sprintf("data type %v not supported", unsupported.AsCql()))
+		}
+	}
+}
+
+// unsupportedDataType returns the first data type not supported by the protocol version found in the given data type,
+// or nil if none was found.
+func (v *validation) unsupportedDataType(dt datatype.DataType) datatype.DataType {
+	if dt == nil {
+		return nil
+	} else if !v.version.SupportsDataType(dt.Code()) {
+		return dt
+	}
+	var children []datatype.DataType
+	switch t := dt.(type) {
+	case *datatype.List:
+		children = []datatype.DataType{t.ElementType}
+	case *datatype.Set:
+		children = []datatype.DataType{t.ElementType}
+	case *datatype.Map:
+		children = []datatype.DataType{t.KeyType, t.ValueType}
+	case *datatype.Tuple:
+		children = t.FieldTypes
+	case *datatype.UserDefined:
+		children = t.FieldTypes
+	}
+	for _, child := range children {
+		if unsupported := v.unsupportedDataType(child); unsupported != nil {
+			return unsupported
+		}
+	}
+	return nil
+}
+
+// REQUESTS
+
+func (m *Startup) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *Options) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *Query) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.queryOptions("Options.", m.Options)
+	return v.result(m)
+}
+
+func (m *Prepare) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.unsupported("Keyspace", m.Keyspace != "", version.SupportsPrepareFlags())
+	return v.result(m)
+}
+
+func (m *Execute) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.resultMetadataId("ResultMetadataId", m.ResultMetadataId)
+	v.queryOptions("Options.", m.Options)
+	return v.result(m)
+}
+
+func (m *Register) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *Batch) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	for i, child := range m.Children {
+		if child == nil {
+			continue
+		}
+		for j, value := range child.Values {
+			v.value(fmt.Sprintf("Children[%d].Values[%d]", i, j), value)
+		}
+	}
+	v.unsupported("SerialConsistency", m.SerialConsistency != nil, version.SupportsBatchQueryFlags())
+	v.unsupported("DefaultTimestamp", m.DefaultTimestamp != nil, version.SupportsQueryFlag(primitive.QueryFlagDefaultTimestamp))
+	v.unsupported("Keyspace", m.Keyspace != "", version.SupportsQueryFlag(primitive.QueryFlagWithKeyspace))
+	v.unsupported("NowInSeconds", m.NowInSeconds != nil, version.SupportsQueryFlag(primitive.QueryFlagNowInSeconds))
+	return v.result(m)
+}
+
+func (m *AuthResponse) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *Revise) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	if !version.IsDse() {
+		v.add("", "DSE protocol version required")
+	} else {
+		v.unsupported("RevisionType", m.RevisionType.IsValid(), version.SupportsDseRevisionType(m.RevisionType))
+	}
+	return v.result(m)
+}
+
+// RESPONSES
+
+func (m *Ready) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *Authenticate) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *Supported) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *AuthChallenge) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *AuthSuccess) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *SchemaChangeEvent) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.schemaChange(m.Target, m.Arguments)
+	return v.result(m)
+}
+
+func (m *StatusChangeEvent) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *TopologyChangeEvent) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.unsupported("ChangeType", m.ChangeType.IsValid(), version.SupportsTopologyChangeType(m.ChangeType))
+	return v.result(m)
+}
+
+// RESULTS
+
+func (m *VoidResult) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *SetKeyspaceResult) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *SchemaChangeResult) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.schemaChange(m.Target, m.Arguments)
+	return v.result(m)
+}
+
+func (m *PreparedResult) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.resultMetadataId("ResultMetadataId", m.ResultMetadataId)
+	if m.VariablesMetadata != nil {
+		v.unsupported("VariablesMetadata.PkIndices", m.VariablesMetadata.PkIndices != nil, version >= primitive.ProtocolVersion4)
+		v.columns("VariablesMetadata.Columns", m.VariablesMetadata.Columns)
+	}
+	v.rowsMetadata("ResultMetadata.", m.ResultMetadata)
+	return v.result(m)
+}
+
+func (m *RowsResult) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.rowsMetadata("Metadata.", m.Metadata)
+	return v.result(m)
+}
+
+func (m *LazyRowsResult) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.rowsMetadata("Metadata.", m.Metadata)
+	return v.result(m)
+}
+
+// ERRORS
+
+func (m *ServerError) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *ProtocolError) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *AuthenticationError) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *Overloaded) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *IsBootstrapping) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *TruncateError) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *SyntaxError) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *Unauthorized) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *Invalid) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *ConfigError) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *Unavailable) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *ReadTimeout) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *WriteTimeout) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.unsupported("Contentions", m.Contentions != 0, version.SupportsWriteTimeoutContentions() && m.WriteType == primitive.WriteTypeCas)
+	return v.result(m)
+}
+
+func (m *ReadFailure) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.failure(m.NumFailures, m.FailureReasons)
+	return v.result(m)
+}
+
+func (m *WriteFailure) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.failure(m.NumFailures, m.FailureReasons)
+	return v.result(m)
+}
+
+func (m *FunctionFailure) Validate(version primitive.ProtocolVersion) error {
+	v := newValidation(version)
+	v.unsupported("", true, version >= primitive.ProtocolVersion4)
+	return v.result(m)
+}
+
+func (m *Unprepared) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
+
+func (m *AlreadyExists) Validate(version primitive.ProtocolVersion) error {
+	return newValidation(version).result(m)
+}
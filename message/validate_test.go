@@ -0,0 +1,227 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+func TestValidate(t *testing.T) {
+	unset := &primitive.Value{Type: primitive.ValueTypeUnset}
+	tests := []struct {
+		name       string
+		msg        Message
+		version    primitive.ProtocolVersion
+		violations []string
+	}{
+		{"query valid", &Query{Query: "SELECT", Options: &QueryOptions{Keyspace: "ks1", NowInSeconds: int32Ptr(1)}}, primitive.ProtocolVersion5, nil},
+		{
+			"query keyspace and now in seconds",
+			&Query{Query: "SELECT", Options: &QueryOptions{Keyspace: "ks1", NowInSeconds: int32Ptr(1)}},
+			primitive.ProtocolVersion4,
+			[]string{"Options.Keyspace: not supported", "Options.NowInSeconds: not supported"},
+		},
+		{
+			"query now in seconds DSE",
+			&Query{Query: "SELECT", Options: &QueryOptions{Keyspace: "ks1", NowInSeconds: int32Ptr(1)}},
+			primitive.ProtocolVersionDse2,
+			[]string{"Options.NowInSeconds: not supported"},
+		},
+		{
+			"query named values",
+			&Query{Query: "SELECT", Options: &QueryOptions{NamedValues: map[string]*primitive.Value{"a": primitive.NewValue(nil)}}},
+			primitive.ProtocolVersion2,
+			[]string{"Options.NamedValues: not supported"},
+		},
+		{
+			"query positional and named values",
+			&Query{Query: "SELECT", Options: &QueryOptions{
+				PositionalValues: []*primitive.Value{primitive.NewValue(nil)},
+				NamedValues:      map[string]*primitive.Value{"a": primitive.NewValue(nil)},
+			}},
+			primitive.ProtocolVersion4,
+			[]string{"Options.NamedValues: cannot be used together with positional values"},
+		},
+		{
+			"query unset values",
+			&Query{Query: "SELECT", Options: &QueryOptions{NamedValues: map[string]*primitive.Value{"b": unset, "a": unset, "c": primitive.NewValue(nil)}}},
+			primitive.ProtocolVersion3,
+			[]string{"Options.NamedValues[a]: unset values not supported", "Options.NamedValues[b]: unset values not supported"},
+		},
+		{
+			"query v2 options",
+			&Query{Query: "SELECT", Options: &QueryOptions{
+				PositionalValues:        []*primitive.Value{primitive.NewValue(nil), unset},
+				PageSize:                100,
+				PageSizeInBytes:         true,
+				DefaultTimestamp:        int64Ptr(123),
+				ContinuousPagingOptions: &ContinuousPagingOptions{MaxPages: 10},
+			}},
+			primitive.ProtocolVersion2,
+			[]string{
+				"Options.PositionalValues[1]: unset values not supported",
+				"Options.PageSizeInBytes: not supported",
+				"Options.DefaultTimestamp: not supported",
+				"Options.ContinuousPagingOptions: not supported",
+			},
+		},
+		{
+			"query continuous paging DSE v1",
+			&Query{Query: "SELECT", Options: &QueryOptions{ContinuousPagingOptions: &ContinuousPagingOptions{NextPages: 1}}},
+			primitive.ProtocolVersionDse1,
+			[]string{"Options.ContinuousPagingOptions.NextPages: not supported"},
+		},
+		{"execute valid", &Execute{QueryId: []byte{1}}, primitive.ProtocolVersion4, nil},
+		{"execute missing result metadata id", &Execute{QueryId: []byte{1}}, primitive.ProtocolVersion5, []string{"ResultMetadataId: required"}},
+		{
+			"execute result metadata id",
+			&Execute{QueryId: []byte{1}, ResultMetadataId: []byte{2}, Options: &QueryOptions{Keyspace: "ks1"}},
+			primitive.ProtocolVersion4,
+			[]string{"ResultMetadataId: not supported", "Options.Keyspace: not supported"},
+		},
+		{
+			"batch v2",
+			&Batch{
+				Children:          []*BatchChild{{Query: "INSERT", Values: []*primitive.Value{primitive.NewValue(nil), unset}}},
+				SerialConsistency: consistencyLevelPtr(primitive.ConsistencyLevelSerial),
+				DefaultTimestamp:  int64Ptr(123),
+				Keyspace:          "ks1",
+				NowInSeconds:      int32Ptr(1),
+			},
+			primitive.ProtocolVersion2,
+			[]string{
+				"Children[0].Values[1]: unset values not supported",
+				"SerialConsistency: not supported",
+				"DefaultTimestamp: not supported",
+				"Keyspace: not supported",
+				"NowInSeconds: not supported",
+			},
+		},
+		{"prepare keyspace", &Prepare{Query: "SELECT", Keyspace: "ks1"}, primitive.ProtocolVersionDse1, []string{"Keyspace: not supported"}},
+		{"revise OSS", &Revise{RevisionType: primitive.DseRevisionTypeCancelContinuousPaging}, primitive.ProtocolVersion4, []string{"DSE protocol version required"}},
+		{"revise DSE v1", &Revise{RevisionType: primitive.DseRevisionTypeMoreContinuousPages}, primitive.ProtocolVersionDse1, []string{"RevisionType: not supported"}},
+		{
+			"schema change event",
+			&SchemaChangeEvent{ChangeType: primitive.SchemaChangeTypeCreated, Target: primitive.SchemaChangeTargetFunction, Keyspace: "ks1", Object: "f", Arguments: []string{"int"}},
+			primitive.ProtocolVersion3,
+			[]string{"Target: not supported", "Arguments: not supported"},
+		},
+		{
+			"schema change result",
+			&SchemaChangeResult{ChangeType: primitive.SchemaChangeTypeCreated, Target: primitive.SchemaChangeTargetType, Keyspace: "ks1", Object: "udt"},
+			primitive.ProtocolVersion2,
+			[]string{"Target: not supported"},
+		},
+		{"topology change", &TopologyChangeEvent{ChangeType: primitive.TopologyChangeTypeMovedNode}, primitive.ProtocolVersion2, []string{"ChangeType: not supported"}},
+		{
+			"prepared result",
+			&PreparedResult{
+				PreparedQueryId: []byte{1},
+				VariablesMetadata: &VariablesMetadata{
+					PkIndices: []uint16{0},
+					Columns:   []*ColumnMetadata{{Name: "c1", Type: datatype.Int}, {Name: "c2", Type: datatype.Date}},
+				},
+				ResultMetadata: &RowsMetadata{
+					ColumnCount: 1,
+					Columns:     []*ColumnMetadata{{Name: "c1", Type: datatype.NewMap(datatype.Varchar, datatype.NewList(datatype.Duration))}},
+				},
+			},
+			primitive.ProtocolVersion3,
+			[]string{
+				"VariablesMetadata.PkIndices: not supported",
+				"VariablesMetadata.Columns[1].Type: data type date not supported",
+				"ResultMetadata.Columns[0].Type: data type duration not supported",
+			},
+		},
+		{
+			"rows result",
+			&RowsResult{Metadata: &RowsMetadata{NewResultMetadataId: []byte{1}, ContinuousPageNumber: 1, LastContinuousPage: true}},
+			primitive.ProtocolVersion4,
+			[]string{
+				"Metadata.NewResultMetadataId: not supported",
+				"Metadata.ContinuousPageNumber: not supported",
+				"Metadata.LastContinuousPage: not supported",
+			},
+		},
+		{"rows result DSE", &RowsResult{Metadata: &RowsMetadata{ContinuousPageNumber: 1, LastContinuousPage: true}}, primitive.ProtocolVersionDse1, nil},
+		{
+			"lazy rows result",
+			&LazyRowsResult{Metadata: &RowsMetadata{ColumnCount: 1, Columns: []*ColumnMetadata{{Name: "c1", Type: datatype.Smallint}}}},
+			primitive.ProtocolVersion3,
+			[]string{"Metadata.Columns[0].Type: data type smallint not supported"},
+		},
+		{"write timeout contentions", &WriteTimeout{WriteType: primitive.WriteTypeCas, Contentions: 3}, primitive.ProtocolVersion4, []string{"Contentions: not supported"}},
+		{"write timeout contentions v5", &WriteTimeout{WriteType: primitive.WriteTypeCas, Contentions: 3}, primitive.ProtocolVersion5, nil},
+		{"read failure v3", &ReadFailure{}, primitive.ProtocolVersion3, []string{"not supported"}},
+		{"read failure num failures", &ReadFailure{NumFailures: 1}, primitive.ProtocolVersion5, []string{"NumFailures: not supported"}},
+		{
+			"write failure reasons",
+			&WriteFailure{FailureReasons: []*primitive.FailureReason{{Code: primitive.FailureCodeUnknown}}},
+			primitive.ProtocolVersion4,
+			[]string{"FailureReasons: not supported"},
+		},
+		{"function failure", &FunctionFailure{}, primitive.ProtocolVersion3, []string{"not supported"}},
+		{"unsupported version", &Startup{}, primitive.ProtocolVersion(1), []string{"unsupported protocol version"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.msg, tt.version)
+			if tt.violations == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr))
+			assert.Equal(t, tt.msg.GetOpCode(), validationErr.OpCode)
+			assert.Equal(t, tt.version, validationErr.Version)
+			var violations []string
+			for _, violation := range validationErr.Violations {
+				violations = append(violations, violation.String())
+			}
+			assert.Equal(t, tt.violations, violations)
+		})
+	}
+}
+
+func TestValidate_AllMessages(t *testing.T) {
+	messages := []Message{
+		&Startup{}, &Options{}, &Query{}, &Prepare{}, &Execute{QueryId: []byte{1}, ResultMetadataId: []byte{2}}, &Register{},
+		&Batch{}, &AuthResponse{}, &Ready{}, &Authenticate{}, &Supported{}, &AuthChallenge{}, &AuthSuccess{},
+		&SchemaChangeEvent{}, &StatusChangeEvent{}, &TopologyChangeEvent{}, &VoidResult{}, &SetKeyspaceResult{},
+		&SchemaChangeResult{}, &PreparedResult{ResultMetadataId: []byte{1}}, &RowsResult{}, &LazyRowsResult{},
+		&ServerError{}, &ProtocolError{}, &AuthenticationError{}, &Overloaded{}, &IsBootstrapping{}, &TruncateError{},
+		&SyntaxError{}, &Unauthorized{}, &Invalid{}, &ConfigError{}, &Unavailable{}, &ReadTimeout{}, &WriteTimeout{},
+		&ReadFailure{}, &WriteFailure{}, &FunctionFailure{}, &Unprepared{}, &AlreadyExists{},
+	}
+	for _, msg := range messages {
+		_, ok := msg.(Validator)
+		assert.True(t, ok, "%T does not implement Validator", msg)
+		assert.NoError(t, Validate(msg, primitive.ProtocolVersion5))
+	}
+	assert.NoError(t, Validate(&Revise{RevisionType: primitive.DseRevisionTypeMoreContinuousPages}, primitive.ProtocolVersionDse2))
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := (&Query{Options: &QueryOptions{Keyspace: "ks1", NowInSeconds: int32Ptr(1)}}).Validate(primitive.ProtocolVersion4)
+	assert.EqualError(t, err, "invalid OpCode QUERY [0x07] message for ProtocolVersion OSS 4: "+
+		"Options.Keyspace: not supported; Options.NowInSeconds: not supported")
+}
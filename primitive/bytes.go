@@ -31,7 +31,7 @@ func ReadBytes(source io.Reader) ([]byte, error) {
 		return []byte{}, nil
 	} else {
 		decoded := make([]byte, length)
-		if _, err := io.ReadFull(source, decoded); err != nil {
+		if err := readFull(source, decoded); err != nil {
 			return nil, fmt.Errorf("cannot read [bytes] content: %w", err)
 		}
 		return decoded, nil
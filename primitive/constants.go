@@ -536,6 +536,72 @@ func (c ErrorCode) IsQueryValidationError() bool {
 	return true
 }
 
+// IsTimeout returns true if the error code denotes a coordinator timing out while waiting for replicas to respond.
+func (c ErrorCode) IsTimeout() bool {
+	return c == ErrorCodeReadTimeout || c == ErrorCodeWriteTimeout
+}
+
+// IsCoordinatorFailure returns true if the error code denotes a failure specific to the coordinator that handled the
+// request, regardless of the state of the replicas: the request may succeed on another coordinator.
+func (c ErrorCode) IsCoordinatorFailure() bool {
+	switch c {
+	case ErrorCodeServerError:
+	case ErrorCodeOverloaded:
+	case ErrorCodeIsBootstrapping:
+	default:
+		return false
+	}
+	return true
+}
+
+// IsReplicaFailure returns true if the error code denotes replicas that were unavailable, did not respond in time or
+// failed to execute the request: trying another coordinator is only useful if the replicas' state changes in between.
+func (c ErrorCode) IsReplicaFailure() bool {
+	switch c {
+	case ErrorCodeUnavailable:
+	case ErrorCodeTruncateError:
+	case ErrorCodeWriteTimeout:
+	case ErrorCodeReadTimeout:
+	case ErrorCodeReadFailure:
+	case ErrorCodeWriteFailure:
+	default:
+		return false
+	}
+	return true
+}
+
+// IsRetryable returns true if the error code guarantees that the request was not executed, and that it may succeed if
+// retried, possibly on another coordinator, or after being prepared again for ErrorCodeUnprepared. Such requests can
+// be safely retried even if they are not idempotent.
+func (c ErrorCode) IsRetryable() bool {
+	switch c {
+	case ErrorCodeUnavailable:
+	case ErrorCodeOverloaded:
+	case ErrorCodeIsBootstrapping:
+	case ErrorCodeUnprepared:
+	default:
+		return false
+	}
+	return true
+}
+
+// IsRetryableIfIdempotent returns true if the error code denotes a request that may succeed if retried, but that may
+// also have been partially or totally applied. Such requests should only be retried if they are idempotent. All
+// retryable error codes, as per IsRetryable, are also retryable if idempotent.
+func (c ErrorCode) IsRetryableIfIdempotent() bool {
+	switch c {
+	case ErrorCodeServerError:
+	case ErrorCodeTruncateError:
+	case ErrorCodeWriteTimeout:
+	case ErrorCodeReadTimeout:
+	case ErrorCodeReadFailure:
+	case ErrorCodeWriteFailure:
+	default:
+		return c.IsRetryable()
+	}
+	return true
+}
+
 func (c ErrorCode) String() string {
 	switch c {
 	case ErrorCodeServerError:
@@ -14,7 +14,11 @@
 
 package primitive
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestProtocolVersion_String(t *testing.T) {
 	tests := []struct {
@@ -83,3 +87,43 @@ func TestDataTypeCode_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorCode_Classification(t *testing.T) {
+	tests := []struct {
+		code                  ErrorCode
+		timeout               bool
+		coordinatorFailure    bool
+		replicaFailure        bool
+		retryable             bool
+		retryableIfIdempotent bool
+	}{
+		{ErrorCodeServerError, false, true, false, false, true},
+		{ErrorCodeProtocolError, false, false, false, false, false},
+		{ErrorCodeAuthenticationError, false, false, false, false, false},
+		{ErrorCodeUnavailable, false, false, true, true, true},
+		{ErrorCodeOverloaded, false, true, false, true, true},
+		{ErrorCodeIsBootstrapping, false, true, false, true, true},
+		{ErrorCodeTruncateError, false, false, true, false, true},
+		{ErrorCodeWriteTimeout, true, false, true, false, true},
+		{ErrorCodeReadTimeout, true, false, true, false, true},
+		{ErrorCodeReadFailure, false, false, true, false, true},
+		{ErrorCodeFunctionFailure, false, false, false, false, false},
+		{ErrorCodeWriteFailure, false, false, true, false, true},
+		{ErrorCodeSyntaxError, false, false, false, false, false},
+		{ErrorCodeUnauthorized, false, false, false, false, false},
+		{ErrorCodeInvalid, false, false, false, false, false},
+		{ErrorCodeConfigError, false, false, false, false, false},
+		{ErrorCodeAlreadyExists, false, false, false, false, false},
+		{ErrorCodeUnprepared, false, false, false, true, true},
+		{ErrorCode(0x1234), false, false, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			assert.Equal(t, tt.timeout, tt.code.IsTimeout())
+			assert.Equal(t, tt.coordinatorFailure, tt.code.IsCoordinatorFailure())
+			assert.Equal(t, tt.replicaFailure, tt.code.IsReplicaFailure())
+			assert.Equal(t, tt.retryable, tt.code.IsRetryable())
+			assert.Equal(t, tt.retryableIfIdempotent, tt.code.IsRetryableIfIdempotent())
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"io"
+)
+
+// Cursor is an io.Reader that reads from a byte slice. All the Read functions of this package, and thus all the
+// message and frame decoders built upon them, detect when their source is a Cursor and then read directly from the
+// underlying slice, bypassing the io.Reader machinery: this is considerably faster than reading from an io.Reader
+// such as bytes.Buffer, especially for messages made of many small primitives.
+//
+// Decoded values never share memory with the underlying slice, except when explicitly documented otherwise, e.g.
+// Next and Remaining.
+type Cursor struct {
+	data []byte
+	pos  int
+}
+
+// NewCursor returns a new Cursor positioned at the beginning of the given slice.
+func NewCursor(data []byte) *Cursor {
+	return &Cursor{data: data}
+}
+
+// Read implements io.Reader.
+func (c *Cursor) Read(p []byte) (n int, err error) {
+	if c.pos >= len(c.data) {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	n = copy(p, c.data[c.pos:])
+	c.pos += n
+	return n, nil
+}
+
+// ReadByte implements io.ByteReader.
+func (c *Cursor) ReadByte() (byte, error) {
+	if c.pos >= len(c.data) {
+		return 0, io.EOF
+	}
+	b := c.data[c.pos]
+	c.pos++
+	return b, nil
+}
+
+// Next returns the next n bytes and advances the cursor past them. The returned slice shares its memory with the
+// underlying slice. If fewer than n bytes are available, the cursor is advanced to the end, and the error is io.EOF if
+// no bytes were available, or io.ErrUnexpectedEOF otherwise, just like io.ReadFull.
+func (c *Cursor) Next(n int) ([]byte, error) {
+	if n <= 0 {
+		return c.data[c.pos:c.pos], nil
+	} else if remaining := len(c.data) - c.pos; remaining < n {
+		c.pos = len(c.data)
+		if remaining == 0 {
+			return nil, io.EOF
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := c.data[c.pos : c.pos+n : c.pos+n]
+	c.pos += n
+	return b, nil
+}
+
+// Len returns the number of unread bytes.
+func (c *Cursor) Len() int {
+	return len(c.data) - c.pos
+}
+
+// Position returns the number of bytes read so far.
+func (c *Cursor) Position() int {
+	return c.pos
+}
+
+// Remaining returns the unread bytes, without advancing the cursor. The returned slice shares its memory with the
+// underlying slice.
+func (c *Cursor) Remaining() []byte {
+	return c.data[c.pos:]
+}
+
+// readFull is equivalent to io.ReadFull, but reads directly from the underlying slice when source is a Cursor.
+func readFull(source io.Reader, dest []byte) error {
+	if c, ok := source.(*Cursor); ok {
+		b, err := c.Next(len(dest))
+		copy(dest, b)
+		return err
+	}
+	_, err := io.ReadFull(source, dest)
+	return err
+}
+
+// readFixed reads exactly n bytes from source; when source is a Cursor, the returned slice shares its memory with the
+// underlying slice. Used to decode integers without the overhead of binary.Read.
+func readFixed(source io.Reader, n int) ([]byte, error) {
+	if c, ok := source.(*Cursor); ok {
+		return c.Next(n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(source, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// readStringContent reads a string of the given length from source; when source is a Cursor, the string is created
+// directly from the underlying slice, thus avoiding an intermediate copy.
+func readStringContent(source io.Reader, length int) (string, error) {
+	if c, ok := source.(*Cursor); ok {
+		b, err := c.Next(length)
+		return string(b), err
+	}
+	decoded := make([]byte, length)
+	if _, err := io.ReadFull(source, decoded); err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	c := NewCursor([]byte{1, 2, 3, 4, 5})
+	assert.Equal(t, 5, c.Len())
+	b, err := c.ReadByte()
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), b)
+	next, err := c.Next(2)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2, 3}, next)
+	assert.Equal(t, 3, c.Position())
+	assert.Equal(t, []byte{4, 5}, c.Remaining())
+	assert.Equal(t, 3, c.Position(), "Remaining must not advance the cursor")
+	buf := make([]byte, 10)
+	n, err := c.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []byte{4, 5}, buf[:n])
+	assert.Equal(t, 0, c.Len())
+	n, err = c.Read(buf)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, io.EOF, err)
+	n, err = c.Read(nil)
+	assert.Equal(t, 0, n)
+	assert.NoError(t, err)
+	_, err = c.ReadByte()
+	assert.Equal(t, io.EOF, err)
+	_, err = c.Next(1)
+	assert.Equal(t, io.EOF, err)
+	next, err = c.Next(0)
+	assert.NoError(t, err)
+	assert.Empty(t, next)
+}
+
+func TestCursor_Next_ShortRead(t *testing.T) {
+	c := NewCursor([]byte{1, 2, 3})
+	next, err := c.Next(4)
+	assert.Nil(t, next)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCursor_Next_SharesMemory(t *testing.T) {
+	data := []byte{1, 2, 3}
+	next, err := NewCursor(data).Next(2)
+	require.NoError(t, err)
+	next[0] = 42
+	assert.Equal(t, byte(42), data[0])
+	assert.Equal(t, 2, cap(next), "appending to the returned slice must not overwrite the underlying slice")
+}
+
+// Reading from a Cursor must be strictly equivalent to reading from any other io.Reader, including errors.
+func TestCursor_ReadFunctions(t *testing.T) {
+	readers := map[string]func(source io.Reader) (interface{}, error){
+		"byte":          func(source io.Reader) (interface{}, error) { return ReadByte(source) },
+		"short":         func(source io.Reader) (interface{}, error) { return ReadShort(source) },
+		"int":           func(source io.Reader) (interface{}, error) { return ReadInt(source) },
+		"long":          func(source io.Reader) (interface{}, error) { return ReadLong(source) },
+		"string":        func(source io.Reader) (interface{}, error) { return ReadString(source) },
+		"long string":   func(source io.Reader) (interface{}, error) { return ReadLongString(source) },
+		"bytes":         func(source io.Reader) (interface{}, error) { return ReadBytes(source) },
+		"short bytes":   func(source io.Reader) (interface{}, error) { return ReadShortBytes(source) },
+		"value":         func(source io.Reader) (interface{}, error) { return ReadValue(source, ProtocolVersion4) },
+		"uuid":          func(source io.Reader) (interface{}, error) { return ReadUuid(source) },
+		"inetaddr":      func(source io.Reader) (interface{}, error) { return ReadInetAddr(source) },
+		"string list":   func(source io.Reader) (interface{}, error) { return ReadStringList(source) },
+		"string map":    func(source io.Reader) (interface{}, error) { return ReadStringMap(source) },
+		"bytes map":     func(source io.Reader) (interface{}, error) { return ReadBytesMap(source) },
+		"unsigned vint": func(source io.Reader) (interface{}, error) { v, _, err := ReadUnsignedVint(source); return v, err },
+	}
+	inputs := map[string][]byte{
+		"empty":        {},
+		"one byte":     {0x04},
+		"short":        {0x00, 0x02, 'h', 'i'},
+		"int":          {0x00, 0x00, 0x00, 0x02, 0xca, 0xfe},
+		"long":         {0, 0, 0, 0, 0, 0, 0, 0x01, 0x02, 0x03},
+		"null":         {0xff, 0xff, 0xff, 0xff},
+		"uuid":         uuidBytes[:],
+		"ipv4":         {0x04, 127, 0, 0, 1},
+		"list":         {0x00, 0x01, 0x00, 0x01, 'a'},
+		"map":          {0x00, 0x01, 0x00, 0x01, 'a', 0x00, 0x01, 'b'},
+		"bytes map":    {0x00, 0x01, 0x00, 0x01, 'a', 0x00, 0x00, 0x00, 0x01, 'b'},
+		"truncated":    {0x00, 0x00, 0x00, 0x05, 'h', 'e'},
+		"short length": {0x00},
+	}
+	for readerName, read := range readers {
+		t.Run(readerName, func(t *testing.T) {
+			for inputName, input := range inputs {
+				t.Run(inputName, func(t *testing.T) {
+					expected, expectedErr := read(bytes.NewBuffer(input))
+					cursor := NewCursor(input)
+					actual, actualErr := read(cursor)
+					assert.Equal(t, expected, actual)
+					if expectedErr == nil {
+						assert.NoError(t, actualErr)
+					} else {
+						assert.EqualError(t, actualErr, expectedErr.Error())
+					}
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkReadString(b *testing.B) {
+	encoded := []byte{0x00, 0x0b, 'h', 'e', 'l', 'l', 'o', ' ', 'w', 'o', 'r', 'l', 'd'}
+	b.Run("bytes.Buffer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = ReadString(bytes.NewBuffer(encoded))
+		}
+	})
+	b.Run("Cursor", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = ReadString(NewCursor(encoded))
+		}
+	})
+}
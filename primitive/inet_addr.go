@@ -29,13 +29,13 @@ func ReadInetAddr(source io.Reader) (net.IP, error) {
 	} else {
 		if length == net.IPv4len {
 			decoded := make([]byte, net.IPv4len)
-			if _, err := io.ReadFull(source, decoded); err != nil {
+			if err := readFull(source, decoded); err != nil {
 				return nil, fmt.Errorf("cannot read [inetaddr] IPv4 content: %w", err)
 			}
 			return net.IPv4(decoded[0], decoded[1], decoded[2], decoded[3]), nil
 		} else if length == net.IPv6len {
 			decoded := make([]byte, net.IPv6len)
-			if _, err := io.ReadFull(source, decoded); err != nil {
+			if err := readFull(source, decoded); err != nil {
 				return nil, fmt.Errorf("cannot read [inetaddr] IPv6 content: %w", err)
 			}
 			return decoded, nil
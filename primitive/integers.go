@@ -30,8 +30,11 @@ const (
 // [byte] ([byte] is not defined in protocol specs but is used by other primitives)
 
 func ReadByte(source io.Reader) (decoded uint8, err error) {
-	if err = binary.Read(source, binary.BigEndian, &decoded); err != nil {
+	var b []byte
+	if b, err = readFixed(source, LengthOfByte); err != nil {
 		err = fmt.Errorf("cannot read [byte]: %w", err)
+	} else {
+		decoded = b[0]
 	}
 	return decoded, err
 }
@@ -46,8 +49,11 @@ func WriteByte(b uint8, dest io.Writer) error {
 // [short]
 
 func ReadShort(source io.Reader) (decoded uint16, err error) {
-	if err = binary.Read(source, binary.BigEndian, &decoded); err != nil {
+	var b []byte
+	if b, err = readFixed(source, LengthOfShort); err != nil {
 		err = fmt.Errorf("cannot read [short]: %w", err)
+	} else {
+		decoded = binary.BigEndian.Uint16(b)
 	}
 	return decoded, err
 }
@@ -62,8 +68,11 @@ func WriteShort(i uint16, dest io.Writer) error {
 // [int]
 
 func ReadInt(source io.Reader) (decoded int32, err error) {
-	if err = binary.Read(source, binary.BigEndian, &decoded); err != nil {
+	var b []byte
+	if b, err = readFixed(source, LengthOfInt); err != nil {
 		err = fmt.Errorf("cannot read [int]: %w", err)
+	} else {
+		decoded = int32(binary.BigEndian.Uint32(b))
 	}
 	return decoded, err
 }
@@ -78,8 +87,11 @@ func WriteInt(i int32, dest io.Writer) error {
 // [long]
 
 func ReadLong(source io.Reader) (decoded int64, err error) {
-	if err = binary.Read(source, binary.BigEndian, &decoded); err != nil {
+	var b []byte
+	if b, err = readFixed(source, LengthOfLong); err != nil {
 		err = fmt.Errorf("cannot read [long]: %w", err)
+	} else {
+		decoded = int64(binary.BigEndian.Uint64(b))
 	}
 	return decoded, err
 }
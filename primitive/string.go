@@ -28,11 +28,11 @@ func ReadString(source io.Reader) (string, error) {
 	} else if length <= 0 {
 		return "", nil
 	} else {
-		decoded := make([]byte, length)
-		if _, err := io.ReadFull(source, decoded); err != nil {
+		decoded, err := readStringContent(source, int(length))
+		if err != nil {
 			return "", fmt.Errorf("cannot read [string] content: %w", err)
 		}
-		return string(decoded), nil
+		return decoded, nil
 	}
 }
 
@@ -0,0 +1,200 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LengthOfTimeUuidNode is the length of the node identifier of a time-based (version 1) UUID.
+const LengthOfTimeUuidNode = 6
+
+// gregorianEpochOffset is the number of 100-nanosecond intervals between the start of the Gregorian calendar
+// (1582-10-15T00:00:00Z), which is the epoch of time-based UUIDs, and the Unix epoch.
+const gregorianEpochOffset = 0x01B21DD213814000
+
+const (
+	ticksPerSecond      = int64(time.Second / 100)
+	ticksPerMillisecond = int64(time.Millisecond / 100)
+	maxTicks            = 1<<60 - 1
+)
+
+// The clock sequence and node parts used by Cassandra's minTimeuuid and maxTimeuuid functions. Since Cassandra
+// compares these parts as signed bytes, they are respectively the lowest and highest possible values.
+var (
+	minTimeUuidClockSeqAndNode = [8]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80}
+	maxTimeUuidClockSeqAndNode = [8]byte{0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f}
+)
+
+// TimeUuidGenerator generates time-based (version 1) UUIDs, suitable for CQL timeuuid columns. Generated UUIDs are
+// strictly increasing according to Cassandra's timeuuid ordering, as long as the system clock does not go backwards:
+// when several UUIDs are generated within the same 100-nanosecond interval, the timestamp of each UUID is incremented
+// by one interval. If the system clock goes backwards, the clock sequence is incremented instead, so that generated
+// UUIDs remain unique. A TimeUuidGenerator is safe for concurrent use.
+type TimeUuidGenerator struct {
+	node      [LengthOfTimeUuidNode]byte
+	now       func() time.Time
+	mu        sync.Mutex
+	clockSeq  uint16
+	lastClock int64
+	lastTicks int64
+}
+
+// NewTimeUuidGenerator creates a new TimeUuidGenerator with the given node identifier, which must be either 6 bytes
+// long, e.g. a MAC address, or nil; in the latter case, a random node identifier is generated, with its multicast bit
+// set as mandated by RFC 4122. The initial clock sequence is always random.
+func NewTimeUuidGenerator(node []byte) (*TimeUuidGenerator, error) {
+	g := &TimeUuidGenerator{now: time.Now}
+	if node == nil {
+		if _, err := rand.Read(g.node[:]); err != nil {
+			return nil, fmt.Errorf("cannot generate random timeuuid node: %w", err)
+		}
+		g.node[0] |= 0x01
+	} else if len(node) != LengthOfTimeUuidNode {
+		return nil, fmt.Errorf("invalid timeuuid node: expected %d bytes, got: %d", LengthOfTimeUuidNode, len(node))
+	} else {
+		copy(g.node[:], node)
+	}
+	var clockSeq [2]byte
+	if _, err := rand.Read(clockSeq[:]); err != nil {
+		return nil, fmt.Errorf("cannot generate random timeuuid clock sequence: %w", err)
+	}
+	g.clockSeq = binary.BigEndian.Uint16(clockSeq[:]) & 0x3fff
+	return g, nil
+}
+
+// Node returns the node identifier of this generator.
+func (g *TimeUuidGenerator) Node() []byte {
+	node := g.node
+	return node[:]
+}
+
+// Generate returns a new time-based UUID for the current time.
+func (g *TimeUuidGenerator) Generate() *UUID {
+	g.mu.Lock()
+	clock := timeToTicks(g.now())
+	ticks := clock
+	if clock < g.lastClock {
+		// the system clock went backwards
+		g.clockSeq = (g.clockSeq + 1) & 0x3fff
+	} else if ticks <= g.lastTicks {
+		ticks = g.lastTicks + 1
+	}
+	g.lastClock, g.lastTicks = clock, ticks
+	clockSeq := g.clockSeq
+	g.mu.Unlock()
+	u := newTimeUuid(ticks)
+	u[8] = byte(clockSeq>>8) | 0x80
+	u[9] = byte(clockSeq)
+	copy(u[10:], g.node[:])
+	return u
+}
+
+var (
+	defaultTimeUuidGenerator     *TimeUuidGenerator
+	defaultTimeUuidGeneratorErr  error
+	defaultTimeUuidGeneratorOnce sync.Once
+)
+
+// NewTimeUuid returns a new time-based UUID for the current time, using a package-wide TimeUuidGenerator with a
+// random node identifier. An error is only returned if the random node identifier could not be generated.
+func NewTimeUuid() (*UUID, error) {
+	defaultTimeUuidGeneratorOnce.Do(func() {
+		defaultTimeUuidGenerator, defaultTimeUuidGeneratorErr = NewTimeUuidGenerator(nil)
+	})
+	if defaultTimeUuidGeneratorErr != nil {
+		return nil, defaultTimeUuidGeneratorErr
+	}
+	return defaultTimeUuidGenerator.Generate(), nil
+}
+
+// MinTimeUuid returns the smallest time-based UUID for the millisecond of the given time, according to Cassandra's
+// timeuuid ordering. It is equivalent to CQL's minTimeuuid function and is meant to be used as the lower bound of
+// range queries on timeuuid columns, e.g. WHERE t > minTimeuuid(?); it should never be stored.
+func MinTimeUuid(t time.Time) *UUID {
+	u := newTimeUuid(timeToTicks(t.Truncate(time.Millisecond)))
+	copy(u[8:], minTimeUuidClockSeqAndNode[:])
+	return u
+}
+
+// MaxTimeUuid returns the greatest time-based UUID for the millisecond of the given time, according to Cassandra's
+// timeuuid ordering. It is equivalent to CQL's maxTimeuuid function and is meant to be used as the upper bound of
+// range queries on timeuuid columns, e.g. WHERE t < maxTimeuuid(?); it should never be stored.
+func MaxTimeUuid(t time.Time) *UUID {
+	u := newTimeUuid(timeToTicks(t.Truncate(time.Millisecond)) + ticksPerMillisecond - 1)
+	copy(u[8:], maxTimeUuidClockSeqAndNode[:])
+	return u
+}
+
+// Version returns the version of this UUID, e.g. 1 for time-based UUIDs and 4 for random UUIDs.
+func (u *UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Time returns the timestamp of this time-based UUID, with a precision of 100 nanoseconds. It returns an error if
+// this UUID is not a version 1 UUID.
+func (u *UUID) Time() (time.Time, error) {
+	if u.Version() != 1 {
+		return time.Time{}, fmt.Errorf("cannot extract timestamp from UUID %v: not a time-based UUID (version %d)", u, u.Version())
+	}
+	ticks := u.ticks() - gregorianEpochOffset
+	return time.Unix(ticks/ticksPerSecond, ticks%ticksPerSecond*100).UTC(), nil
+}
+
+// CompareTimeUuids compares the given time-based UUIDs using Cassandra's timeuuid ordering, and returns -1, 0 or 1 if
+// u1 is respectively lesser than, equal to or greater than u2. UUIDs are ordered by timestamp first, then by their
+// clock sequence and node parts, compared as signed bytes.
+func CompareTimeUuids(u1, u2 *UUID) int {
+	if t1, t2 := u1.ticks(), u2.ticks(); t1 < t2 {
+		return -1
+	} else if t1 > t2 {
+		return 1
+	}
+	for i := 8; i < LengthOfUuid; i++ {
+		if b1, b2 := int8(u1[i]), int8(u2[i]); b1 < b2 {
+			return -1
+		} else if b1 > b2 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// ticks returns the 60-bit timestamp of this UUID, in 100-nanosecond intervals since the Gregorian epoch.
+func (u *UUID) ticks() int64 {
+	timeLow := int64(binary.BigEndian.Uint32(u[0:4]))
+	timeMid := int64(binary.BigEndian.Uint16(u[4:6]))
+	timeHigh := int64(binary.BigEndian.Uint16(u[6:8]) & 0x0fff)
+	return timeHigh<<48 | timeMid<<32 | timeLow
+}
+
+// timeToTicks converts the given time to a 60-bit timestamp in 100-nanosecond intervals since the Gregorian epoch;
+// times outside the range of time-based UUIDs, that is, before 1582 or after 5236, wrap around.
+func timeToTicks(t time.Time) int64 {
+	return (t.Unix()*ticksPerSecond + int64(t.Nanosecond())/100 + gregorianEpochOffset) & maxTicks
+}
+
+// newTimeUuid returns a new version 1 UUID with the given timestamp and a zero clock sequence and node.
+func newTimeUuid(ticks int64) *UUID {
+	u := new(UUID)
+	binary.BigEndian.PutUint32(u[0:4], uint32(ticks))
+	binary.BigEndian.PutUint16(u[4:6], uint16(ticks>>32))
+	binary.BigEndian.PutUint16(u[6:8], uint16(ticks>>48)&0x0fff|0x1000)
+	return u
+}
@@ -0,0 +1,225 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	timeUuidNode      = []byte{1, 2, 3, 4, 5, 6}
+	timeUuidTimestamp = time.Date(2021, 3, 4, 12, 34, 56, 789000000, time.UTC)
+)
+
+func mustParseUuid(t *testing.T, s string) *UUID {
+	u, err := ParseUuid(s)
+	require.NoError(t, err)
+	return u
+}
+
+// newTestTimeUuidGenerator returns a generator with a fixed node and clock sequence, whose clock returns the given
+// times in order, then repeats the last one.
+func newTestTimeUuidGenerator(t *testing.T, times ...time.Time) *TimeUuidGenerator {
+	g, err := NewTimeUuidGenerator(timeUuidNode)
+	require.NoError(t, err)
+	g.clockSeq = 0x1234
+	g.now = func() time.Time {
+		now := times[0]
+		if len(times) > 1 {
+			times = times[1:]
+		}
+		return now
+	}
+	return g
+}
+
+func TestNewTimeUuidGenerator(t *testing.T) {
+	g, err := NewTimeUuidGenerator(timeUuidNode)
+	require.NoError(t, err)
+	assert.Equal(t, timeUuidNode, g.Node())
+	assert.LessOrEqual(t, g.clockSeq, uint16(0x3fff))
+	g, err = NewTimeUuidGenerator(nil)
+	require.NoError(t, err)
+	assert.Len(t, g.Node(), LengthOfTimeUuidNode)
+	assert.Equal(t, byte(0x01), g.Node()[0]&0x01, "multicast bit must be set on random nodes")
+	g, err = NewTimeUuidGenerator([]byte{1, 2, 3})
+	assert.Nil(t, g)
+	assert.EqualError(t, err, "invalid timeuuid node: expected 6 bytes, got: 3")
+}
+
+func TestTimeUuidGenerator_Generate(t *testing.T) {
+	g := newTestTimeUuidGenerator(t, timeUuidTimestamp)
+	u := g.Generate()
+	assert.Equal(t, mustParseUuid(t, "07123c50-7ce6-11eb-9234-010203040506"), u)
+	assert.Equal(t, 1, u.Version())
+	ts, err := u.Time()
+	require.NoError(t, err)
+	assert.Equal(t, timeUuidTimestamp, ts)
+}
+
+func TestTimeUuidGenerator_Generate_SameTime(t *testing.T) {
+	g := newTestTimeUuidGenerator(t, timeUuidTimestamp)
+	u1 := g.Generate()
+	u2 := g.Generate()
+	u3 := g.Generate()
+	assert.Equal(t, mustParseUuid(t, "07123c51-7ce6-11eb-9234-010203040506"), u2)
+	assert.Equal(t, mustParseUuid(t, "07123c52-7ce6-11eb-9234-010203040506"), u3)
+	assert.Equal(t, -1, CompareTimeUuids(u1, u2))
+	assert.Equal(t, -1, CompareTimeUuids(u2, u3))
+}
+
+func TestTimeUuidGenerator_Generate_ClockBackwards(t *testing.T) {
+	g := newTestTimeUuidGenerator(t, timeUuidTimestamp, timeUuidTimestamp.Add(-time.Second))
+	u1 := g.Generate()
+	u2 := g.Generate()
+	assert.Equal(t, mustParseUuid(t, "07123c50-7ce6-11eb-9234-010203040506"), u1)
+	assert.Equal(t, mustParseUuid(t, "0679a5d0-7ce6-11eb-9235-010203040506"), u2)
+	assert.NotEqual(t, u1, u2)
+}
+
+func TestTimeUuidGenerator_Generate_Concurrent(t *testing.T) {
+	g, err := NewTimeUuidGenerator(nil)
+	require.NoError(t, err)
+	const goroutines, count = 8, 1000
+	results := make([][]*UUID, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < count; j++ {
+				results[i] = append(results[i], g.Generate())
+			}
+		}(i)
+	}
+	wg.Wait()
+	seen := make(map[UUID]bool)
+	for _, uuids := range results {
+		for j, u := range uuids {
+			assert.False(t, seen[*u], "duplicate UUID %v", u)
+			seen[*u] = true
+			if j > 0 {
+				assert.Equal(t, -1, CompareTimeUuids(uuids[j-1], u))
+			}
+		}
+	}
+}
+
+func TestNewTimeUuid(t *testing.T) {
+	before := time.Now().Add(-time.Millisecond)
+	u, err := NewTimeUuid()
+	require.NoError(t, err)
+	assert.Equal(t, 1, u.Version())
+	ts, err := u.Time()
+	require.NoError(t, err)
+	assert.True(t, ts.After(before))
+	assert.True(t, ts.Before(time.Now().Add(time.Millisecond)))
+}
+
+func TestMinMaxTimeUuid(t *testing.T) {
+	ts := timeUuidTimestamp.Add(123456 * time.Nanosecond)
+	minUuid := MinTimeUuid(ts)
+	maxUuid := MaxTimeUuid(ts)
+	assert.Equal(t, mustParseUuid(t, "07123c50-7ce6-11eb-8080-808080808080"), minUuid)
+	assert.Equal(t, mustParseUuid(t, "0712635f-7ce6-11eb-7f7f-7f7f7f7f7f7f"), maxUuid)
+	minTime, err := minUuid.Time()
+	require.NoError(t, err)
+	assert.Equal(t, timeUuidTimestamp, minTime)
+	maxTime, err := maxUuid.Time()
+	require.NoError(t, err)
+	assert.Equal(t, timeUuidTimestamp.Add(time.Millisecond-100*time.Nanosecond), maxTime)
+	// any UUID generated within the same millisecond falls within the bounds
+	g := newTestTimeUuidGenerator(t, ts)
+	u := g.Generate()
+	assert.Equal(t, -1, CompareTimeUuids(minUuid, u))
+	assert.Equal(t, 1, CompareTimeUuids(maxUuid, u))
+	// but not UUIDs generated in adjacent milliseconds
+	assert.Equal(t, 1, CompareTimeUuids(MinTimeUuid(ts.Add(time.Millisecond)), maxUuid))
+	assert.Equal(t, -1, CompareTimeUuids(MaxTimeUuid(ts.Add(-time.Millisecond)), minUuid))
+}
+
+func TestUUID_Version(t *testing.T) {
+	assert.Equal(t, 4, uuid.Version())
+	assert.Equal(t, 1, mustParseUuid(t, "07123c50-7ce6-11eb-9234-010203040506").Version())
+}
+
+func TestUUID_Time(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+		err      string
+	}{
+		{"timeuuid", "07123c50-7ce6-11eb-9234-010203040506", timeUuidTimestamp, ""},
+		{"gregorian epoch", "00000000-0000-1000-8000-000000000000", time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC), ""},
+		{"unix epoch", "13814000-1dd2-11b2-8000-000000000000", time.Unix(0, 0).UTC(), ""},
+		{"max", "ffffffff-ffff-1fff-8000-000000000000", time.Date(5236, 3, 31, 21, 21, 0, 684697500, time.UTC), ""},
+		{"random UUID", "c0d1d21e-bb01-4196-86db-bc317bc1796a", time.Time{}, "cannot extract timestamp from UUID c0d1d21e-bb01-4196-86db-bc317bc1796a: not a time-based UUID (version 4)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := mustParseUuid(t, tt.input).Time()
+			assert.Equal(t, tt.expected, actual)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCompareTimeUuids(t *testing.T) {
+	// in Cassandra's timeuuid order
+	ordered := []string{
+		"00000000-0000-1000-8080-808080808080",
+		"00000000-0000-1000-0000-000000000000",
+		"00000000-0000-1000-7f7f-7f7f7f7f7f7f",
+		"ffffffff-0000-1000-8080-808080808080",
+		"00000000-0001-1000-8080-808080808080",
+		"00000000-0000-1001-8080-808080808080",
+		"07123c50-7ce6-11eb-8080-808080808080",
+		"07123c50-7ce6-11eb-9234-010203040506",
+		"07123c50-7ce6-11eb-0234-010203040506",
+		"07123c50-7ce6-11eb-7f7f-7f7f7f7f7f7f",
+		"07123c51-7ce6-11eb-8080-808080808080",
+		"ffffffff-ffff-1fff-7f7f-7f7f7f7f7f7f",
+	}
+	for i, s1 := range ordered {
+		for j, s2 := range ordered {
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			assert.Equal(t, expected, CompareTimeUuids(mustParseUuid(t, s1), mustParseUuid(t, s2)), "%s vs %s", s1, s2)
+		}
+	}
+	shuffled := make([]*UUID, len(ordered))
+	for i, s := range ordered {
+		shuffled[(i*5)%len(ordered)] = mustParseUuid(t, s)
+	}
+	sort.Slice(shuffled, func(i, j int) bool { return CompareTimeUuids(shuffled[i], shuffled[j]) < 0 })
+	for i, s := range ordered {
+		assert.Equal(t, s, shuffled[i].String())
+	}
+}
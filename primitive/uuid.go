@@ -87,7 +87,7 @@ func ParseUuid(input string) (*UUID, error) {
 
 func ReadUuid(source io.Reader) (*UUID, error) {
 	decoded := new(UUID)
-	if _, err := io.ReadFull(source, decoded[:]); err != nil {
+	if err := readFull(source, decoded[:]); err != nil {
 		return nil, fmt.Errorf("cannot read [uuid] content: %w", err)
 	}
 	return decoded, nil
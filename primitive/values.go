@@ -69,7 +69,7 @@ func ReadValue(source io.Reader, version ProtocolVersion) (*Value, error) {
 		return NewValue([]byte{}), nil
 	} else {
 		decoded := make([]byte, length)
-		if _, err := io.ReadFull(source, decoded); err != nil {
+		if err := readFull(source, decoded); err != nil {
 			return nil, fmt.Errorf("cannot read [value] content: %w", err)
 		}
 		return NewValue(decoded), nil
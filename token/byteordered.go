@@ -0,0 +1,67 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ByteOrderedToken is a token produced by the ByteOrderedPartitioner. Its value is the routing key itself.
+type ByteOrderedToken []byte
+
+// String returns the hexadecimal representation of this token, as displayed by Cassandra.
+func (t ByteOrderedToken) String() string {
+	return hex.EncodeToString(t)
+}
+
+func (t ByteOrderedToken) Compare(other Token) int {
+	o, ok := other.(ByteOrderedToken)
+	if !ok {
+		panic(errCannotCompare(t, other))
+	}
+	return bytes.Compare(t, o)
+}
+
+// ByteOrderedPartitioner is an order-preserving partitioner. Its tokens are the routing keys themselves, compared
+// lexicographically.
+var ByteOrderedPartitioner Partitioner = byteOrderedPartitioner{}
+
+type byteOrderedPartitioner struct{}
+
+func (p byteOrderedPartitioner) Name() string {
+	return partitionerPackage + "ByteOrderedPartitioner"
+}
+
+func (p byteOrderedPartitioner) Hash(routingKey []byte) Token {
+	token := make(ByteOrderedToken, len(routingKey))
+	copy(token, routingKey)
+	return token
+}
+
+// ParseToken parses the hexadecimal representation of a token; an optional "0x" prefix is accepted.
+func (p byteOrderedPartitioner) ParseToken(s string) (Token, error) {
+	if decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")); err != nil {
+		return nil, fmt.Errorf("cannot parse ByteOrdered token '%s': %w", s, err)
+	} else {
+		return ByteOrderedToken(decoded), nil
+	}
+}
+
+func (p byteOrderedPartitioner) MinToken() Token {
+	return ByteOrderedToken{}
+}
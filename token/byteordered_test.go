@@ -0,0 +1,49 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByteOrderedPartitioner_Hash(t *testing.T) {
+	key := []byte{0xca, 0xfe}
+	actual := ByteOrderedPartitioner.Hash(key)
+	assert.Equal(t, ByteOrderedToken{0xca, 0xfe}, actual)
+	assert.Equal(t, "cafe", actual.String())
+	key[0] = 0
+	assert.Equal(t, ByteOrderedToken{0xca, 0xfe}, actual, "token should not share the routing key array")
+}
+
+func TestByteOrderedPartitioner_ParseToken(t *testing.T) {
+	for _, s := range []string{"cafe", "0xcafe", "CAFE"} {
+		actual, err := ByteOrderedPartitioner.ParseToken(s)
+		require.NoError(t, err)
+		assert.Equal(t, ByteOrderedToken{0xca, 0xfe}, actual)
+	}
+	actual, err := ByteOrderedPartitioner.ParseToken("caf")
+	assert.Nil(t, actual)
+	assert.EqualError(t, err, "cannot parse ByteOrdered token 'caf': encoding/hex: odd length hex string")
+}
+
+func TestByteOrderedToken_Compare(t *testing.T) {
+	assert.Equal(t, -1, ByteOrderedToken{1}.Compare(ByteOrderedToken{1, 0}))
+	assert.Equal(t, 0, ByteOrderedToken{1, 2}.Compare(ByteOrderedToken{1, 2}))
+	assert.Equal(t, 1, ByteOrderedToken{2}.Compare(ByteOrderedToken{1, 2}))
+	assert.Equal(t, -1, ByteOrderedPartitioner.MinToken().Compare(ByteOrderedToken{0}))
+	assert.Panics(t, func() { ByteOrderedToken{1}.Compare(Murmur3Token(1)) })
+}
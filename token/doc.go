@@ -0,0 +1,22 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+
+Package token contains functions to compute partitioner tokens client-side, as done by Cassandra when determining
+which replicas own a given partition. The Murmur3Partitioner, RandomPartitioner and ByteOrderedPartitioner are
+supported. This package also contains functions to assemble routing keys from partition key components.
+
+*/
+package token
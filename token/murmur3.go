@@ -0,0 +1,157 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+// Murmur3Token is a token produced by the Murmur3Partitioner.
+type Murmur3Token int64
+
+func (t Murmur3Token) String() string {
+	return strconv.FormatInt(int64(t), 10)
+}
+
+func (t Murmur3Token) Compare(other Token) int {
+	o, ok := other.(Murmur3Token)
+	if !ok {
+		panic(errCannotCompare(t, other))
+	}
+	if t < o {
+		return -1
+	} else if t > o {
+		return 1
+	}
+	return 0
+}
+
+// Murmur3Partitioner is Cassandra's default partitioner. Its tokens are the first 64 bits of the MurmurHash3 x64
+// 128-bit hash of the routing key, as computed by Cassandra's own implementation of the hash, which differs from the
+// reference implementation for keys whose length is not a multiple of 16 and that contain bytes greater than 0x7f.
+var Murmur3Partitioner Partitioner = murmur3Partitioner{}
+
+type murmur3Partitioner struct{}
+
+func (p murmur3Partitioner) Name() string {
+	return partitionerPackage + "Murmur3Partitioner"
+}
+
+func (p murmur3Partitioner) Hash(routingKey []byte) Token {
+	if len(routingKey) == 0 {
+		return p.MinToken()
+	}
+	h1 := murmur3H1(routingKey)
+	// Cassandra reserves math.MinInt64 for the minimum token
+	if h1 == math.MinInt64 {
+		h1 = math.MaxInt64
+	}
+	return Murmur3Token(h1)
+}
+
+func (p murmur3Partitioner) ParseToken(s string) (Token, error) {
+	if t, err := strconv.ParseInt(s, 10, 64); err != nil {
+		return nil, fmt.Errorf("cannot parse Murmur3 token '%s': %w", s, err)
+	} else {
+		return Murmur3Token(t), nil
+	}
+}
+
+func (p murmur3Partitioner) MinToken() Token {
+	return Murmur3Token(math.MinInt64)
+}
+
+const (
+	murmur3C1 = uint64(0x87c37b91114253d5)
+	murmur3C2 = uint64(0x4cf5ad432745937f)
+)
+
+// Computes the first 64 bits of the MurmurHash3 x64 128-bit hash of the given data, with seed 0. This is a port of
+// Cassandra's MurmurHash.hash3_x64_128 method; notably, the tail bytes are sign-extended, as they are Java bytes.
+func murmur3H1(data []byte) int64 {
+	length := len(data)
+	nBlocks := length / 16
+	var h1, h2 uint64
+	for i := 0; i < nBlocks; i++ {
+		k1 := binary.LittleEndian.Uint64(data[i*16:])
+		k2 := binary.LittleEndian.Uint64(data[i*16+8:])
+		h1 ^= murmur3MixK1(k1)
+		h1 = bits.RotateLeft64(h1, 27)
+		h1 += h2
+		h1 = h1*5 + 0x52dce729
+		h2 ^= murmur3MixK2(k2)
+		h2 = bits.RotateLeft64(h2, 31)
+		h2 += h1
+		h2 = h2*5 + 0x38495ab5
+	}
+	tail := data[nBlocks*16:]
+	var k1, k2 uint64
+	for i := len(tail) - 1; i >= 8; i-- {
+		k2 ^= signExtend(tail[i]) << (uint(i-8) * 8)
+	}
+	if len(tail) > 8 {
+		h2 ^= murmur3MixK2(k2)
+	}
+	k1Length := len(tail)
+	if k1Length > 8 {
+		k1Length = 8
+	}
+	for i := k1Length - 1; i >= 0; i-- {
+		k1 ^= signExtend(tail[i]) << (uint(i) * 8)
+	}
+	if len(tail) > 0 {
+		h1 ^= murmur3MixK1(k1)
+	}
+	h1 ^= uint64(length)
+	h2 ^= uint64(length)
+	h1 += h2
+	h2 += h1
+	h1 = murmur3Fmix(h1)
+	h2 = murmur3Fmix(h2)
+	h1 += h2
+	return int64(h1)
+}
+
+func murmur3MixK1(k1 uint64) uint64 {
+	k1 *= murmur3C1
+	k1 = bits.RotateLeft64(k1, 31)
+	k1 *= murmur3C2
+	return k1
+}
+
+func murmur3MixK2(k2 uint64) uint64 {
+	k2 *= murmur3C2
+	k2 = bits.RotateLeft64(k2, 33)
+	k2 *= murmur3C1
+	return k2
+}
+
+func murmur3Fmix(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
+
+// Converts the given byte to uint64 as if it were a signed Java byte.
+func signExtend(b byte) uint64 {
+	return uint64(int64(int8(b)))
+}
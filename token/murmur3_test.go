@@ -0,0 +1,63 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package token
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMurmur3Partitioner_Hash(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      []byte
+		expected Murmur3Token
+	}{
+		{"empty", []byte{}, math.MinInt64},
+		{"nil", nil, math.MinInt64},
+		{"int 1", []byte{0, 0, 0, 1}, -4069959284402364209},
+		{"hello", []byte("hello"), -0x3427584cbe4264fe},
+		{"hello, world", []byte("hello, world"), 0x342fac623a5ebc8e},
+		{"19 Jan 2038 at 3:14:07 AM", []byte("19 Jan 2038 at 3:14:07 AM"), -0x4761a67748c85004},
+		{"quick brown fox", []byte("The quick brown fox jumps over the lazy dog."), -0x3266b7e06116fd37},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Murmur3Partitioner.Hash(tt.key)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestMurmur3Partitioner_ParseToken(t *testing.T) {
+	actual, err := Murmur3Partitioner.ParseToken("-4069959284402364209")
+	require.NoError(t, err)
+	assert.Equal(t, Murmur3Token(-4069959284402364209), actual)
+	actual, err = Murmur3Partitioner.ParseToken("not a token")
+	assert.Nil(t, actual)
+	assert.EqualError(t, err, "cannot parse Murmur3 token 'not a token': strconv.ParseInt: parsing \"not a token\": invalid syntax")
+}
+
+func TestMurmur3Token_Compare(t *testing.T) {
+	assert.Equal(t, -1, Murmur3Token(1).Compare(Murmur3Token(2)))
+	assert.Equal(t, 0, Murmur3Token(2).Compare(Murmur3Token(2)))
+	assert.Equal(t, 1, Murmur3Token(2).Compare(Murmur3Token(1)))
+	assert.Equal(t, -1, Murmur3Partitioner.MinToken().Compare(Murmur3Token(math.MinInt64+1)))
+	assert.PanicsWithValue(t, "cannot compare token.Murmur3Token with token.ByteOrderedToken", func() {
+		Murmur3Token(1).Compare(ByteOrderedToken{1})
+	})
+}
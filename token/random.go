@@ -0,0 +1,97 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"crypto/md5"
+	"fmt"
+	"math/big"
+)
+
+// RandomToken is a token produced by the RandomPartitioner. Its value is an integer between -1 (the minimum token)
+// and 2^127 inclusive. The zero value is a token whose value is 0.
+type RandomToken struct {
+	value *big.Int
+}
+
+// NewRandomToken creates a new RandomToken with the given value. The value is copied.
+func NewRandomToken(value *big.Int) RandomToken {
+	return RandomToken{value: new(big.Int).Set(value)}
+}
+
+// Value returns a copy of this token's value.
+func (t RandomToken) Value() *big.Int {
+	return new(big.Int).Set(t.bigInt())
+}
+
+func (t RandomToken) String() string {
+	return t.bigInt().String()
+}
+
+func (t RandomToken) Compare(other Token) int {
+	o, ok := other.(RandomToken)
+	if !ok {
+		panic(errCannotCompare(t, other))
+	}
+	return t.bigInt().Cmp(o.bigInt())
+}
+
+// bigInt returns this token's value, without copying it; the value of the zero RandomToken is 0.
+func (t RandomToken) bigInt() *big.Int {
+	if t.value == nil {
+		return randomZeroToken
+	}
+	return t.value
+}
+
+// RandomPartitioner is a legacy partitioner. Its tokens are the absolute value of the MD5 digest of the routing key,
+// interpreted as a signed 128-bit big-endian integer, like Java's BigInteger.
+var RandomPartitioner Partitioner = randomPartitioner{}
+
+type randomPartitioner struct{}
+
+var (
+	randomMinToken  = big.NewInt(-1)
+	randomZeroToken = big.NewInt(0)
+)
+
+func (p randomPartitioner) Name() string {
+	return partitionerPackage + "RandomPartitioner"
+}
+
+func (p randomPartitioner) Hash(routingKey []byte) Token {
+	if len(routingKey) == 0 {
+		return p.MinToken()
+	}
+	digest := md5.Sum(routingKey)
+	value := new(big.Int).SetBytes(digest[:])
+	if digest[0]&0x80 != 0 {
+		// negative two's complement value: its absolute value is 2^128 - value
+		value.Sub(new(big.Int).Lsh(big.NewInt(1), 128), value)
+	}
+	return RandomToken{value: value}
+}
+
+func (p randomPartitioner) ParseToken(s string) (Token, error) {
+	if value, ok := new(big.Int).SetString(s, 10); !ok {
+		return nil, fmt.Errorf("cannot parse Random token '%s'", s)
+	} else {
+		return RandomToken{value: value}, nil
+	}
+}
+
+func (p randomPartitioner) MinToken() Token {
+	return RandomToken{value: randomMinToken}
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package token
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomPartitioner_Hash(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      []byte
+		expected string
+	}{
+		{"empty", []byte{}, "-1"},
+		// md5 = 5d41402abc4b2a76b9719d911017c592, positive
+		{"hello", []byte("hello"), "123957004363873451094272536567338222994"},
+		// md5 = 900150983cd24fb0d6963f7d28e17f72, negative
+		{"abc", []byte("abc"), "148866708576779697295343134153845407886"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := RandomPartitioner.Hash(tt.key)
+			assert.Equal(t, tt.expected, actual.String())
+		})
+	}
+}
+
+func TestRandomPartitioner_ParseToken(t *testing.T) {
+	actual, err := RandomPartitioner.ParseToken("123957004363873451094272536567338222994")
+	require.NoError(t, err)
+	assert.Equal(t, 0, actual.Compare(RandomPartitioner.Hash([]byte("hello"))))
+	actual, err = RandomPartitioner.ParseToken("not a token")
+	assert.Nil(t, actual)
+	assert.EqualError(t, err, "cannot parse Random token 'not a token'")
+}
+
+func TestRandomToken_Compare(t *testing.T) {
+	one := NewRandomToken(big.NewInt(1))
+	two := NewRandomToken(big.NewInt(2))
+	assert.Equal(t, -1, one.Compare(two))
+	assert.Equal(t, 0, two.Compare(NewRandomToken(big.NewInt(2))))
+	assert.Equal(t, 1, two.Compare(one))
+	assert.Equal(t, -1, RandomPartitioner.MinToken().Compare(NewRandomToken(big.NewInt(0))))
+	assert.Panics(t, func() { one.Compare(Murmur3Token(1)) })
+}
+
+func TestRandomToken_ZeroValue(t *testing.T) {
+	var zero RandomToken
+	assert.Equal(t, "0", zero.String())
+	assert.Equal(t, big.NewInt(0), zero.Value())
+	assert.Equal(t, 0, zero.Compare(NewRandomToken(big.NewInt(0))))
+	assert.Equal(t, 0, NewRandomToken(big.NewInt(0)).Compare(zero))
+	assert.Equal(t, 1, zero.Compare(RandomPartitioner.MinToken()))
+	assert.Equal(t, -1, zero.Compare(NewRandomToken(big.NewInt(1))))
+}
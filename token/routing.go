@@ -0,0 +1,94 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+// RoutingKey assembles a routing key from the given encoded partition key components. If there is only one
+// component, the routing key is the component itself. Otherwise, the partition key is composite, and each component
+// is encoded as a 2-byte big-endian length, followed by the component bytes, followed by a 0x00 byte; this is the
+// format of Cassandra's CompositeType. An error is returned if there are no components, or if a component is longer
+// than 65535 bytes.
+func RoutingKey(components ...[]byte) ([]byte, error) {
+	switch len(components) {
+	case 0:
+		return nil, errors.New("cannot assemble routing key: no partition key components")
+	case 1:
+		return components[0], nil
+	}
+	length := 0
+	for i, component := range components {
+		if len(component) > math.MaxUint16 {
+			return nil, fmt.Errorf("cannot assemble routing key: component %d is too long: %d bytes", i, len(component))
+		}
+		length += 2 + len(component) + 1
+	}
+	routingKey := make([]byte, 0, length)
+	for _, component := range components {
+		routingKey = append(routingKey, byte(len(component)>>8), byte(len(component)))
+		routingKey = append(routingKey, component...)
+		routingKey = append(routingKey, 0)
+	}
+	return routingKey, nil
+}
+
+// RoutingKeyFromValues assembles a routing key from the given bound values, as found in message.QueryOptions
+// PositionalValues. The partition key components are the values at the positions given by the metadata PkIndices,
+// in that order, see PartitionKeyFromValues. An error is returned if the metadata contains no partition key indices,
+// which is always the case for protocol versions lesser than 4, or if a partition key value is missing, null or unset.
+func RoutingKeyFromValues(metadata *message.VariablesMetadata, values []*primitive.Value) ([]byte, error) {
+	if metadata == nil || len(metadata.PkIndices) == 0 {
+		return nil, errors.New("cannot assemble routing key: no partition key indices")
+	}
+	components, err := PartitionKeyFromValues(metadata, values)
+	if err != nil {
+		return nil, fmt.Errorf("cannot assemble routing key: %w", err)
+	}
+	return RoutingKey(components...)
+}
+
+// PartitionKeyFromValues returns the contents of the given bound values that correspond to the partition key
+// variables, in partition key order, as indicated by the metadata PkIndices. The returned slice is nil if the metadata
+// is nil or contains no partition key indices. An error is returned if a partition key value is missing, null or
+// unset.
+func PartitionKeyFromValues(metadata *message.VariablesMetadata, values []*primitive.Value) ([][]byte, error) {
+	if metadata == nil || len(metadata.PkIndices) == 0 {
+		return nil, nil
+	}
+	components := make([][]byte, len(metadata.PkIndices))
+	for i, index := range metadata.PkIndices {
+		if int(index) >= len(values) || values[index] == nil {
+			return nil, fmt.Errorf("partition key variable %s is missing", partitionKeyVariable(metadata, index))
+		} else if values[index].Type != primitive.ValueTypeRegular {
+			return nil, fmt.Errorf("partition key variable %s is null or unset", partitionKeyVariable(metadata, index))
+		}
+		components[i] = values[index].Contents
+	}
+	return components, nil
+}
+
+func partitionKeyVariable(metadata *message.VariablesMetadata, index uint16) string {
+	if int(index) < len(metadata.Columns) && metadata.Columns[index] != nil {
+		return fmt.Sprintf("%d (%s)", index, metadata.Columns[index].Name)
+	}
+	return fmt.Sprintf("%d", index)
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datastax/go-cassandra-native-protocol/datatype"
+	"github.com/datastax/go-cassandra-native-protocol/message"
+	"github.com/datastax/go-cassandra-native-protocol/primitive"
+)
+
+func TestRoutingKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		components [][]byte
+		expected   []byte
+		err        string
+	}{
+		{"none", nil, nil, "cannot assemble routing key: no partition key components"},
+		{"single", [][]byte{{0, 0, 0, 1}}, []byte{0, 0, 0, 1}, ""},
+		{"composite", [][]byte{{0, 0, 0, 1}, {'a', 'b'}}, []byte{0, 4, 0, 0, 0, 1, 0, 0, 2, 'a', 'b', 0}, ""},
+		{"composite empty component", [][]byte{{}, {1}}, []byte{0, 0, 0, 0, 1, 1, 0}, ""},
+		{"component too long", [][]byte{{1}, make([]byte, 65536)}, nil, "cannot assemble routing key: component 1 is too long: 65536 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := RoutingKey(tt.components...)
+			assert.Equal(t, tt.expected, actual)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRoutingKeyFromValues(t *testing.T) {
+	metadata := &message.VariablesMetadata{
+		PkIndices: []uint16{2, 0},
+		Columns: []*message.ColumnMetadata{
+			{Keyspace: "ks1", Table: "t1", Name: "pk2", Index: 0, Type: datatype.Varchar},
+			{Keyspace: "ks1", Table: "t1", Name: "c", Index: 1, Type: datatype.Int},
+			{Keyspace: "ks1", Table: "t1", Name: "pk1", Index: 2, Type: datatype.Int},
+		},
+	}
+	values := []*primitive.Value{
+		primitive.NewValue([]byte("ab")),
+		primitive.NewValue([]byte{0, 0, 0, 2}),
+		primitive.NewValue([]byte{0, 0, 0, 1}),
+	}
+	actual, err := RoutingKeyFromValues(metadata, values)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 4, 0, 0, 0, 1, 0, 0, 2, 'a', 'b', 0}, actual)
+	single := &message.VariablesMetadata{PkIndices: []uint16{2}, Columns: metadata.Columns}
+	actual, err = RoutingKeyFromValues(single, values)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 1}, actual)
+	assert.Equal(t, Murmur3Token(-4069959284402364209), Murmur3Partitioner.Hash(actual))
+	tests := []struct {
+		name     string
+		metadata *message.VariablesMetadata
+		values   []*primitive.Value
+		err      string
+	}{
+		{"nil metadata", nil, values, "cannot assemble routing key: no partition key indices"},
+		{"no pk indices", &message.VariablesMetadata{Columns: metadata.Columns}, values, "cannot assemble routing key: no partition key indices"},
+		{"missing value", metadata, values[:2], "cannot assemble routing key: partition key variable 2 (pk1) is missing"},
+		{"nil value", metadata, []*primitive.Value{nil, nil, values[2]}, "cannot assemble routing key: partition key variable 0 (pk2) is missing"},
+		{"null value", metadata, []*primitive.Value{primitive.NewNullValue(), nil, values[2]}, "cannot assemble routing key: partition key variable 0 (pk2) is null or unset"},
+		{"no column specs", &message.VariablesMetadata{PkIndices: []uint16{0}}, nil, "cannot assemble routing key: partition key variable 0 is missing"},
+		{"unset value", metadata, []*primitive.Value{values[0], nil, primitive.NewUnsetValue()}, "cannot assemble routing key: partition key variable 2 (pk1) is null or unset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := RoutingKeyFromValues(tt.metadata, tt.values)
+			assert.Nil(t, actual)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
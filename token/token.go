@@ -0,0 +1,66 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Token is a partitioner token.
+type Token interface {
+	fmt.Stringer
+
+	// Compare returns -1, 0 or 1 depending on whether this token is less than, equal to, or greater than the given
+	// token. Tokens created by different partitioners cannot be compared; in that case, this method panics.
+	Compare(other Token) int
+}
+
+// Partitioner computes tokens from routing keys.
+type Partitioner interface {
+
+	// Name returns the fully-qualified class name of this partitioner, e.g.
+	// "org.apache.cassandra.dht.Murmur3Partitioner".
+	Name() string
+
+	// Hash computes the token of the given routing key.
+	Hash(routingKey []byte) Token
+
+	// ParseToken parses the string representation of a token, as found in the system.local and system.peers tables.
+	ParseToken(s string) (Token, error)
+
+	// MinToken returns the minimum token of this partitioner.
+	MinToken() Token
+}
+
+const partitionerPackage = "org.apache.cassandra.dht."
+
+// PartitionerByName returns the partitioner with the given class name. Both fully-qualified names, e.g.
+// "org.apache.cassandra.dht.Murmur3Partitioner", and simple names, e.g. "Murmur3Partitioner", are accepted.
+func PartitionerByName(name string) (Partitioner, error) {
+	if !strings.Contains(name, ".") {
+		name = partitionerPackage + name
+	}
+	for _, partitioner := range []Partitioner{Murmur3Partitioner, RandomPartitioner, ByteOrderedPartitioner} {
+		if partitioner.Name() == name {
+			return partitioner, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown partitioner: %s", name)
+}
+
+func errCannotCompare(token Token, other Token) string {
+	return fmt.Sprintf("cannot compare %T with %T", token, other)
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 DataStax
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionerByName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Partitioner
+		err      string
+	}{
+		{"Murmur3Partitioner", Murmur3Partitioner, ""},
+		{"org.apache.cassandra.dht.Murmur3Partitioner", Murmur3Partitioner, ""},
+		{"RandomPartitioner", RandomPartitioner, ""},
+		{"org.apache.cassandra.dht.RandomPartitioner", RandomPartitioner, ""},
+		{"ByteOrderedPartitioner", ByteOrderedPartitioner, ""},
+		{"org.apache.cassandra.dht.ByteOrderedPartitioner", ByteOrderedPartitioner, ""},
+		{"OrderPreservingPartitioner", nil, "unknown partitioner: org.apache.cassandra.dht.OrderPreservingPartitioner"},
+		{"com.example.Murmur3Partitioner", nil, "unknown partitioner: com.example.Murmur3Partitioner"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := PartitionerByName(tt.name)
+			assert.Equal(t, tt.expected, actual)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}